@@ -1,7 +1,10 @@
 package configs
 
 import (
+	"flag"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/labstack/gommon/log"
 	"github.com/spf13/viper"
@@ -10,6 +13,8 @@ import (
 type AppConfig struct {
 	Port     int `yaml:"port"`
 	Database struct {
+		// mysql, postgres or sqlite, for sqlite the name is the database file
+		// and an empty name or ":memory:" keeps the database in memory
 		Driver   string `yaml:"driver"`
 		Name     string `yaml:"name"`
 		Address  string `yaml:"address"`
@@ -17,6 +22,56 @@ type AppConfig struct {
 		Username string `yaml:"username"`
 		Password string `yaml:"password"`
 	}
+	JWT struct {
+		Issuer   string `yaml:"issuer"`
+		Audience string `yaml:"audience"`
+		// the first key signs new tokens, the rest only verify tokens issued before a rotation
+		Keys []JWTKey `yaml:"keys"`
+	}
+	Invitation struct {
+		Secret string        `yaml:"secret"`
+		TTL    time.Duration `yaml:"ttl"`
+		// link sent in the invitation email, the token is appended to it
+		BaseURL string `yaml:"base_url" mapstructure:"base_url"`
+	}
+	Mail struct {
+		// smtp, file or log
+		Driver   string `yaml:"driver"`
+		From     string `yaml:"from"`
+		Host     string `yaml:"host"`
+		Port     int    `yaml:"port"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+		File     string `yaml:"file"`
+	}
+	Storage struct {
+		// local or s3, s3 works with any S3 compatible service
+		Driver string `yaml:"driver"`
+		Path   string `yaml:"path"`
+		// largest accepted upload in bytes
+		MaxSize int64 `yaml:"max_size" mapstructure:"max_size"`
+		// media types accepted for uploads, detected from the content not the file name
+		AllowedTypes []string `yaml:"allowed_types" mapstructure:"allowed_types"`
+		S3           struct {
+			Endpoint  string `yaml:"endpoint"`
+			Region    string `yaml:"region"`
+			Bucket    string `yaml:"bucket"`
+			AccessKey string `yaml:"access_key" mapstructure:"access_key"`
+			SecretKey string `yaml:"secret_key" mapstructure:"secret_key"`
+		}
+	}
+	Tasks struct {
+		// refuse to complete a task while one of the tasks blocking it is open
+		BlockCompletion bool `yaml:"block_completion" mapstructure:"block_completion"`
+	}
+}
+
+type JWTKey struct {
+	ID             string `yaml:"id" mapstructure:"id"`
+	Algorithm      string `yaml:"algorithm" mapstructure:"algorithm"`
+	Secret         string `yaml:"secret" mapstructure:"secret"`
+	PrivateKeyFile string `yaml:"private_key_file" mapstructure:"private_key_file"`
+	PublicKeyFile  string `yaml:"public_key_file" mapstructure:"public_key_file"`
 }
 
 var lock = &sync.Mutex{}
@@ -41,6 +96,21 @@ func initConfig() *AppConfig {
 	defaultConfig.Database.Port = 3306
 	defaultConfig.Database.Username = "root"
 	defaultConfig.Database.Password = "root"
+	defaultConfig.JWT.Issuer = "be6-project-api"
+	defaultConfig.JWT.Audience = "be6-project-api"
+	defaultConfig.JWT.Keys = []JWTKey{{ID: "default", Algorithm: "HS256", Secret: "secret"}}
+	defaultConfig.Invitation.Secret = "secret"
+	defaultConfig.Invitation.TTL = 7 * 24 * time.Hour
+	defaultConfig.Invitation.BaseURL = "http://localhost:8000/invitations"
+	defaultConfig.Mail.Driver = "log"
+	defaultConfig.Mail.From = "noreply@localhost"
+	defaultConfig.Mail.Port = 587
+	defaultConfig.Storage.Driver = "local"
+	defaultConfig.Storage.Path = "./storage"
+	defaultConfig.Storage.MaxSize = 10 << 20
+	defaultConfig.Storage.AllowedTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp", "application/pdf", "text/plain", "application/zip"}
+	defaultConfig.Storage.S3.Region = "us-east-1"
+	defaultConfig.Tasks.BlockCompletion = true
 
 	viper.SetConfigType("yaml")
 	viper.SetConfigName("config")
@@ -49,14 +119,78 @@ func initConfig() *AppConfig {
 	if err := viper.ReadInConfig(); err != nil {
 		// fmt.Println("kok mlaku")
 		log.Info("error in open file")
+		applyEnv(&defaultConfig)
 		return &defaultConfig
 	}
 
-	var finalConfig AppConfig
+	finalConfig := defaultConfig
 
 	if err := viper.Unmarshal(&finalConfig); err != nil {
 		log.Info("error in extract external config, must use default config")
+		applyEnv(&defaultConfig)
 		return &defaultConfig
 	}
+	applyEnv(&finalConfig)
 	return &finalConfig
 }
+
+// applyEnv lets deployments keep the signing key and other secrets out of config.yaml
+func applyEnv(config *AppConfig) {
+	if len(config.JWT.Keys) == 0 {
+		config.JWT.Keys = []JWTKey{{}}
+	}
+	signing := &config.JWT.Keys[0]
+
+	if v := os.Getenv("JWT_KEY_ID"); v != "" {
+		signing.ID = v
+	}
+	if v := os.Getenv("JWT_ALGORITHM"); v != "" {
+		signing.Algorithm = v
+	}
+	if v := os.Getenv("JWT_SECRET"); v != "" {
+		signing.Secret = v
+	}
+	if v := os.Getenv("JWT_PRIVATE_KEY_FILE"); v != "" {
+		signing.PrivateKeyFile = v
+	}
+	if v := os.Getenv("JWT_ISSUER"); v != "" {
+		config.JWT.Issuer = v
+	}
+	if v := os.Getenv("JWT_AUDIENCE"); v != "" {
+		config.JWT.Audience = v
+	}
+	if v := os.Getenv("INVITATION_SECRET"); v != "" {
+		config.Invitation.Secret = v
+	}
+	if v := os.Getenv("DB_DRIVER"); v != "" {
+		config.Database.Driver = v
+	} else if underTest() {
+		// the test suite needs no database server unless DB_DRIVER names one
+		config.Database.Driver = "sqlite"
+		config.Database.Name = ":memory:"
+	}
+	if v := os.Getenv("DB_NAME"); v != "" {
+		config.Database.Name = v
+	}
+	if v := os.Getenv("DB_ADDRESS"); v != "" {
+		config.Database.Address = v
+	}
+	if v := os.Getenv("DB_PASSWORD"); v != "" {
+		config.Database.Password = v
+	}
+	if v := os.Getenv("SMTP_PASSWORD"); v != "" {
+		config.Mail.Password = v
+	}
+	if v := os.Getenv("S3_ACCESS_KEY"); v != "" {
+		config.Storage.S3.AccessKey = v
+	}
+	if v := os.Getenv("S3_SECRET_KEY"); v != "" {
+		config.Storage.S3.SecretKey = v
+	}
+}
+
+// underTest reports whether the config is read by a test binary, the testing
+// package registers its flags before any test runs
+func underTest() bool {
+	return flag.Lookup("test.v") != nil
+}
@@ -1,3 +1,8 @@
 package configs
 
-const JWT_SECRET = "secret"
\ No newline at end of file
+import "time"
+
+const (
+	ACCESS_TOKEN_TTL  = time.Hour
+	REFRESH_TOKEN_TTL = time.Hour * 24 * 7
+)
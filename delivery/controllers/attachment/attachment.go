@@ -0,0 +1,179 @@
+package attachment
+
+import (
+	"bufio"
+	"mime"
+	"net/http"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/task"
+	"part3/models/base"
+	_task "part3/models/task"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+)
+
+// multipart framing around the file, requests bigger than the limit plus this are refused unread
+const formOverhead = 1 << 20
+
+type AttachmentController struct {
+	repo         task.Attachment
+	maxSize      int64
+	allowedTypes map[string]bool
+}
+
+// New takes the upload limits, an empty allowedTypes accepts every media type
+func New(repository task.Attachment, maxSize int64, allowedTypes []string) *AttachmentController {
+	allowed := map[string]bool{}
+	for _, allowedType := range allowedTypes {
+		allowed[strings.ToLower(allowedType)] = true
+	}
+	return &AttachmentController{
+		repo:         repository,
+		maxSize:      maxSize,
+		allowedTypes: allowed,
+	}
+}
+
+// Create takes the file from the "file" field, its type is sniffed from the content
+func (ac *AttachmentController) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if c.Request().ContentLength > ac.maxSize+formOverhead {
+			return task.ErrAttachmentTooLarge
+		}
+		// the length is not known for chunked bodies, the reader stops them instead
+		c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, ac.maxSize+formOverhead)
+
+		header, err := c.FormFile("file")
+		if err != nil && tooLarge(err) {
+			return task.ErrAttachmentTooLarge
+		}
+		if err != nil || header.Size == 0 {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input attachment", nil))
+		}
+		if header.Size > ac.maxSize {
+			return task.ErrAttachmentTooLarge
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input attachment", nil))
+		}
+		defer file.Close()
+
+		content := bufio.NewReaderSize(file, 512)
+		head, _ := content.Peek(512)
+		contentType := http.DetectContentType(head)
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if len(ac.allowedTypes) > 0 && !ac.allowedTypes[mediaType] {
+			return task.ErrUnsupportedType
+		}
+
+		att := _task.Attachment{
+			Name:        attachmentName(header.Filename),
+			ContentType: contentType,
+			Size:        header.Size,
+		}
+		res, err := ac.repo.Create(task_id, user_id, att, content)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, base.Success(http.StatusCreated, "success to upload attachment", res.ToAttachmentResponse()))
+	}
+}
+
+func (ac *AttachmentController) GetAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := ac.repo.GetAll(task_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get attachments", res))
+	}
+}
+
+// Download streams the content straight from the storage
+func (ac *AttachmentController) Download() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := validation.ParamID(c, "attachment_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		att, content, err := ac.repo.Open(task_id, id, user_id)
+		if err != nil {
+			return err
+		}
+		defer content.Close()
+
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": att.Name}))
+		header.Set(echo.HeaderContentLength, strconv.FormatInt(att.Size, 10))
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Checksum-Sha256", att.Checksum)
+		return c.Stream(http.StatusOK, att.ContentType, content)
+	}
+}
+
+func (ac *AttachmentController) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := validation.ParamID(c, "attachment_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := ac.repo.DeleteById(task_id, id, user_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to delete attachment", nil))
+	}
+}
+
+// attachmentName keeps the base name only, browsers send full paths sometimes
+func attachmentName(filename string) string {
+	name := filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if name == "." || name == "/" {
+		return "attachment"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+		for !utf8.ValidString(name) {
+			name = name[1:]
+		}
+	}
+	return name
+}
+
+// tooLarge reports the error of a body cut by http.MaxBytesReader, it has no type of its own
+func tooLarge(err error) bool {
+	return strings.Contains(err.Error(), "http: request body too large")
+}
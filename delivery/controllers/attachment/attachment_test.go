@@ -0,0 +1,232 @@
+package attachment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"part3/delivery/controllers/auth"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	_proLib "part3/lib/database/project"
+	_taskLib "part3/lib/database/task"
+	"part3/models/session"
+	"part3/models/task"
+	"part3/models/task/response"
+	"part3/models/user"
+	reqU "part3/models/user/request"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func upload(filename string, content string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	if filename != "" {
+		part, _ := form.CreateFormFile("file", filename)
+		part.Write([]byte(content))
+	}
+	form.Close()
+	return body, form.FormDataContentType()
+}
+
+func TestAttachments(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name     string
+		repo     _taskLib.Attachment
+		maxSize  int64
+		handler  func(ac *AttachmentController) echo.HandlerFunc
+		filename string
+		content  string
+		code     int
+		message  string
+		errCode  string
+	}{
+		{"error in input attachment", &MockAttachmentLib{}, 1024, (*AttachmentController).Create, "", "", 400, "error in input attachment", "bad_request"},
+		{"attachment is too large", &MockAttachmentLib{}, 4, (*AttachmentController).Create, "notes.txt", "hello", 413, "attachment is too large", "attachment_too_large"},
+		{"unsupported attachment type", &MockAttachmentLib{}, 1024, (*AttachmentController).Create, "page.html", "<html><body>hello</body></html>", 415, "unsupported attachment type", "unsupported_media_type"},
+		{"success to upload attachment", &MockAttachmentLib{}, 1024, (*AttachmentController).Create, "C:\\Users\\anonim\\notes.txt", "hello", 201, "success to upload attachment", ""},
+		{"forbidden upload attachment", &MockFailAttachmentLib{}, 1024, (*AttachmentController).Create, "notes.txt", "hello", 403, "forbidden access", "forbidden"},
+		{"success to get attachments", &MockAttachmentLib{}, 1024, (*AttachmentController).GetAll, "", "", 200, "success to get attachments", ""},
+		{"error in get attachments", &MockFailAttachmentLib{}, 1024, (*AttachmentController).GetAll, "", "", 500, "error in server", "internal_error"},
+		{"download missing attachment", &MockFailAttachmentLib{}, 1024, (*AttachmentController).Download, "", "", 404, "task or attachment not found", "attachment_not_found"},
+		{"success to delete attachment", &MockAttachmentLib{}, 1024, (*AttachmentController).Delete, "", "", 200, "success to delete attachment", ""},
+		{"delete missing attachment", &MockFailAttachmentLib{}, 1024, (*AttachmentController).Delete, "", "", 404, "task or attachment not found", "attachment_not_found"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, contentType := upload(tc.filename, tc.content)
+			req := httptest.NewRequest(http.MethodPost, "/", reqBody)
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/todo/tasks/:id/attachments/:attachment_id")
+			context.SetParamNames("id", "attachment_id")
+			context.SetParamValues("1", "1")
+
+			attachmentController := New(tc.repo, tc.maxSize, []string{"text/plain", "image/png"})
+			if err := middlewares.JwtMiddleware()(tc.handler(attachmentController))(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetRespFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+			assert.Equal(t, tc.errCode, response.Error)
+		})
+	}
+
+	t.Run("attachment of unknown length is too large", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, contentType := upload("notes.txt", strings.Repeat("a", formOverhead+8))
+		req := httptest.NewRequest(http.MethodPost, "/", reqBody)
+		req.ContentLength = -1
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/attachments")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := New(&MockAttachmentLib{}, 4, nil)
+		if err := middlewares.JwtMiddleware()(attachmentController.Create())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 413, response.Code)
+		assert.Equal(t, "attachment is too large", response.Message)
+		assert.Equal(t, "attachment_too_large", response.Error)
+	})
+
+	t.Run("success to download attachment", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/attachments/:attachment_id")
+		context.SetParamNames("id", "attachment_id")
+		context.SetParamValues("1", "1")
+
+		attachmentController := New(&MockAttachmentLib{}, 1024, nil)
+		if err := middlewares.JwtMiddleware()(attachmentController.Download())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "hello", res.Body.String())
+		assert.Equal(t, "text/plain; charset=utf-8", res.Header().Get("Content-Type"))
+		assert.Equal(t, "attachment; filename*=utf-8''r%C3%A9sum%C3%A9.txt", res.Header().Get("Content-Disposition"))
+		assert.Equal(t, "5", res.Header().Get("Content-Length"))
+	})
+}
+
+type MockAttachmentLib struct{}
+
+func (m *MockAttachmentLib) Create(task_id int, user_id int, att task.Attachment, content io.Reader) (task.Attachment, error) {
+	raw, err := ioutil.ReadAll(content)
+	if err != nil {
+		return att, err
+	}
+	if att.Name != "notes.txt" || string(raw) != "hello" {
+		return att, errors.New("unexpected upload")
+	}
+	att.ID = 1
+	att.Task_ID = uint(task_id)
+	return att, nil
+}
+
+func (m *MockAttachmentLib) GetAll(task_id int, user_id int) ([]response.AttachmentResponse, error) {
+	return []response.AttachmentResponse{}, nil
+}
+
+func (m *MockAttachmentLib) Open(task_id int, id int, user_id int) (task.Attachment, io.ReadCloser, error) {
+	att := task.Attachment{ID: uint(id), Name: "résumé.txt", ContentType: "text/plain; charset=utf-8", Size: 5}
+	return att, ioutil.NopCloser(strings.NewReader("hello")), nil
+}
+
+func (m *MockAttachmentLib) DeleteById(task_id int, id int, user_id int) error {
+	return nil
+}
+
+type MockFailAttachmentLib struct{}
+
+func (m *MockFailAttachmentLib) Create(task_id int, user_id int, att task.Attachment, content io.Reader) (task.Attachment, error) {
+	return att, _proLib.ErrForbidden
+}
+
+func (m *MockFailAttachmentLib) GetAll(task_id int, user_id int) ([]response.AttachmentResponse, error) {
+	return nil, errors.New("error in database process")
+}
+
+func (m *MockFailAttachmentLib) Open(task_id int, id int, user_id int) (task.Attachment, io.ReadCloser, error) {
+	return task.Attachment{}, nil, _taskLib.ErrAttachmentNotFound
+}
+
+func (m *MockFailAttachmentLib) DeleteById(task_id int, id int, user_id int) error {
+	return _taskLib.ErrAttachmentNotFound
+}
+
+type MockAuthLib struct{}
+
+func (m *MockAuthLib) Login(UserLogin reqU.Userlogin) (user.User, error) {
+	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
+}
+
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
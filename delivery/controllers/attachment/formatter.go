@@ -0,0 +1,8 @@
+package attachment
+
+type GetRespFormat struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Error   string      `json:"error"`
+	Data    interface{} `json:"data"`
+}
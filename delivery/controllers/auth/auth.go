@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"part3/delivery/middlewares"
 	"part3/lib/database/auth"
@@ -8,6 +9,7 @@ import (
 	"part3/models/user/request"
 
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type AuthController struct {
@@ -24,23 +26,107 @@ func (ac *AuthController) Login() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		Userlogin := request.Userlogin{}
 
-		if err := c.Bind(&Userlogin); err != nil || Userlogin.Email == "" || Userlogin.Password == "" {
+		if err := c.Bind(&Userlogin); err != nil {
 			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input file", nil))
 		}
+		if err := c.Validate(&Userlogin); err != nil {
+			return err
+		}
 		checkedUser, err := ac.repo.Login(Userlogin)
 
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusUnauthorized, base.Unauthorized(nil, "wrong email or password", nil))
+		}
+		if err != nil {
+			return err
+		}
+
+		sess, refreshToken, err := ac.repo.CreateSession(int(checkedUser.ID))
+
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(nil, "error in call database", nil))
+			return err
 		}
-		token, err := middlewares.GenerateToken(checkedUser)
+
+		token, err := middlewares.GenerateToken(checkedUser, sess.ID)
 
 		if err != nil {
 			return c.JSON(http.StatusNotAcceptable, base.BadRequest(http.StatusNotAcceptable, "error in process token", nil))
 		}
 
 		return c.JSON(http.StatusOK, base.Success(nil, "success login", map[string]interface{}{
-			"data":  checkedUser,
-			"token": token,
+			"data":          checkedUser.ToUserResponse(),
+			"token":         token,
+			"refresh_token": refreshToken,
+		}))
+	}
+}
+
+func (ac *AuthController) Refresh() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		refresh := request.RefreshToken{}
+
+		if err := c.Bind(&refresh); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input refresh token", nil))
+		}
+		if err := c.Validate(&refresh); err != nil {
+			return err
+		}
+
+		checkedUser, sess, refreshToken, err := ac.repo.RefreshSession(refresh.RefreshToken)
+
+		if errors.Is(err, auth.ErrInvalidRefreshToken) || errors.Is(err, auth.ErrRefreshTokenReused) {
+			return c.JSON(http.StatusUnauthorized, base.Unauthorized(nil, err.Error(), nil))
+		}
+		if err != nil {
+			return err
+		}
+
+		token, err := middlewares.GenerateToken(checkedUser, sess.ID)
+
+		if err != nil {
+			return c.JSON(http.StatusNotAcceptable, base.BadRequest(http.StatusNotAcceptable, "error in process token", nil))
+		}
+
+		return c.JSON(http.StatusOK, base.Success(nil, "success refresh token", map[string]interface{}{
+			"token":         token,
+			"refresh_token": refreshToken,
 		}))
 	}
 }
+
+func (ac *AuthController) Logout() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user_id := int(middlewares.ExtractTokenId(c))
+		session_id := int(middlewares.ExtractTokenSessionId(c))
+
+		if err := ac.repo.RevokeSession(session_id, user_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(nil, "success logout", nil))
+	}
+}
+
+func (ac *AuthController) LogoutAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := ac.repo.RevokeAllSessions(user_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(nil, "success logout all devices", nil))
+	}
+}
+
+func (ac *AuthController) JWKS() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		jwks, err := middlewares.JWKS()
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, jwks)
+	}
+}
@@ -3,9 +3,14 @@ package auth
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/auth"
+	"part3/models/base"
+	"part3/models/session"
 	"part3/models/user"
 	"part3/models/user/request"
 	"testing"
@@ -17,8 +22,9 @@ import (
 )
 
 func TestLogin(t *testing.T) {
-	t.Run("error in input file", func(t *testing.T) {
+	t.Run("validation failed", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		reqBody, _ := json.Marshal(map[string]string{
 			"email": "anonim@123",
@@ -32,18 +38,27 @@ func TestLogin(t *testing.T) {
 		context.SetPath("/login")
 
 		authCont := New(&MockAuthLib{})
-		authCont.Login()(context)
+		if err := authCont.Login()(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
 
-		resp := LoginRespFormat{}
+		resp := base.Response{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &resp)
-		assert.Equal(t, 400, resp.Code)
-		assert.Equal(t, "error in input file", resp.Message)
+		assert.Equal(t, float64(422), resp.Code)
+		assert.Equal(t, "validation failed", resp.Message)
+		assert.Equal(t, "validation_failed", resp.Error)
+		assert.Equal(t, []interface{}{map[string]interface{}{
+			"field":   "password",
+			"code":    "required",
+			"message": "password is required",
+		}}, resp.Data)
 
 	})
 
-	t.Run("error in call database", func(t *testing.T) {
+	t.Run("wrong email or password", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim",
@@ -59,12 +74,13 @@ func TestLogin(t *testing.T) {
 		response := LoginRespFormat{}
 		log.Info(res.Body)
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in call database", response.Message)
+		assert.Equal(t, 401, response.Code)
+		assert.Equal(t, "wrong email or password", response.Message)
 	})
 
 	t.Run("fail in process token", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -85,6 +101,7 @@ func TestLogin(t *testing.T) {
 
 	t.Run("success login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -101,6 +118,153 @@ func TestLogin(t *testing.T) {
 
 		assert.Equal(t, 200, response.Code)
 		assert.NotNil(t, response.Data["token"])
+		assert.Equal(t, "refresh", response.Data["refresh_token"])
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	t.Run("error in input refresh token", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/token/refresh")
+		authController := New(&MockAuthLib{})
+		if err := authController.Refresh()(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		resp := base.Response{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &resp)
+
+		assert.Equal(t, float64(422), resp.Code)
+		assert.Equal(t, []interface{}{map[string]interface{}{
+			"field":   "refresh_token",
+			"code":    "required",
+			"message": "refresh_token is required",
+		}}, resp.Data)
+	})
+
+	t.Run("refresh token reused", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"refresh_token": "stolen",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/token/refresh")
+		authController := New(&MockAuthLib{})
+		authController.Refresh()(context)
+		response := LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+
+		assert.Equal(t, 401, response.Code)
+		assert.Equal(t, "refresh token reused", response.Message)
+	})
+
+	t.Run("success refresh token", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"refresh_token": "refresh",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/token/refresh")
+		authController := New(&MockAuthLib{})
+		authController.Refresh()(context)
+		response := LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+		assert.Equal(t, "refresh", response.Data["refresh_token"])
+	})
+}
+
+func TestLogout(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := New(&MockAuthLib{})
+		authController.Login()(context)
+		response := LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+	})
+
+	t.Run("success logout", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/logout")
+		authController := New(&MockAuthLib{})
+		if err := middlewares.JwtMiddleware()(authController.Logout())(context); err != nil {
+			t.Fatal(err)
+		}
+		response := LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "success logout", response.Message)
+	})
+
+	t.Run("success logout all devices", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/logout/all")
+		authController := New(&MockAuthLib{})
+		if err := middlewares.JwtMiddleware()(authController.LogoutAll())(context); err != nil {
+			t.Fatal(err)
+		}
+		response := LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "success logout all devices", response.Message)
+	})
+
+	t.Run("revoked token rejected", func(t *testing.T) {
+		middlewares.SetSessionChecker(&MockAuthLibToken{})
+		defer middlewares.SetSessionChecker(nil)
+
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/logout")
+		authController := New(&MockAuthLib{})
+		err := middlewares.JwtMiddleware()(authController.Logout())(context)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusUnauthorized, err.(*echo.HTTPError).Code)
 	})
 }
 
@@ -108,13 +272,56 @@ type MockAuthLib struct{}
 
 func (m *MockAuthLib) Login(UserLogin request.Userlogin) (user.User, error) {
 	if UserLogin.Email != "anonim@123" && UserLogin.Password != "anonim123" {
-		return user.User{}, errors.New("record not found")
+		return user.User{}, gorm.ErrRecordNotFound
 	}
 	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
 }
 
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	if refreshToken != "refresh" {
+		return user.User{}, session.Session{}, "", auth.ErrRefreshTokenReused
+	}
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
+
 type MockAuthLibToken struct{}
 
 func (m *MockAuthLibToken) Login(UserLogin request.Userlogin) (user.User, error) {
 	return user.User{}, nil
 }
+
+func (m *MockAuthLibToken) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLibToken) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLibToken) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLibToken) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLibToken) IsSessionActive(session_id int, user_id int) bool {
+	return false
+}
@@ -0,0 +1,140 @@
+package board
+
+import (
+	"net/http"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/task"
+	"part3/models/base"
+	"part3/models/board/request"
+
+	"github.com/labstack/echo/v4"
+)
+
+type BoardController struct {
+	repo task.Board
+}
+
+func New(repository task.Board) *BoardController {
+	return &BoardController{
+		repo: repository,
+	}
+}
+
+// sectionFields are the failing fields of a section the tags cannot check, a
+// name is required on create only
+func sectionFields(sectionReq *request.SectionRequest, create bool) []base.FieldError {
+	if create && sectionReq.ToSection().Name == "" {
+		return []base.FieldError{{Field: "name", Code: "required", Message: "name is required"}}
+	}
+	return nil
+}
+
+// GetBoard groups the tasks of the project by status, or by section with ?by=section
+func (bc *BoardController) GetBoard() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := bc.repo.GetBoard(project_id, user_id, c.QueryParam("by"))
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get board", res))
+	}
+}
+
+func (bc *BoardController) GetSections() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := bc.repo.GetSections(project_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get sections", res))
+	}
+}
+
+func (bc *BoardController) CreateSection() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		req := request.SectionRequest{}
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input section", nil))
+		}
+		if err := validation.Check(c, &req, sectionFields(&req, true)...); err != nil {
+			return err
+		}
+
+		res, err := bc.repo.CreateSection(project_id, user_id, req)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, base.Success(http.StatusCreated, "success to create section", res.ToSectionResponse()))
+	}
+}
+
+func (bc *BoardController) PutSection() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := validation.ParamID(c, "section_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		req := request.SectionRequest{}
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input section", nil))
+		}
+		if err := validation.Check(c, &req, sectionFields(&req, false)...); err != nil {
+			return err
+		}
+
+		res, err := bc.repo.UpdateSection(project_id, id, user_id, req)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to update section", res.ToSectionResponse()))
+	}
+}
+
+func (bc *BoardController) DeleteSection() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := validation.ParamID(c, "section_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := bc.repo.DeleteSection(project_id, id, user_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to delete section", nil))
+	}
+}
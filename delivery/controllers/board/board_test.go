@@ -0,0 +1,172 @@
+package board
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"part3/delivery/controllers/auth"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	_proLib "part3/lib/database/project"
+	_taskLib "part3/lib/database/task"
+	"part3/models/board"
+	"part3/models/board/request"
+	"part3/models/board/response"
+	"part3/models/session"
+	taskResp "part3/models/task/response"
+	"part3/models/user"
+	reqU "part3/models/user/request"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestBoard(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _taskLib.Board
+		handler func(bc *BoardController) echo.HandlerFunc
+		query   string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"success to get board", &MockBoardLib{}, (*BoardController).GetBoard, "", nil, 200, "success to get board"},
+		{"success to get board by section", &MockBoardLib{}, (*BoardController).GetBoard, "?by=section", nil, 200, "success to get board"},
+		{"error in input board", &MockFailBoardLib{}, (*BoardController).GetBoard, "?by=label", nil, 422, "unknown board grouping"},
+		{"success to get sections", &MockBoardLib{}, (*BoardController).GetSections, "", nil, 200, "success to get sections"},
+		{"get sections forbidden", &MockFailBoardLib{}, (*BoardController).GetSections, "", nil, 403, "forbidden access"},
+		{"error in input section", &MockBoardLib{}, (*BoardController).CreateSection, "", map[string]interface{}{"name": " "}, 422, "validation failed"},
+		{"error in input section position", &MockBoardLib{}, (*BoardController).CreateSection, "", map[string]interface{}{"name": "Backlog", "position": -1}, 422, "validation failed"},
+		{"success to create section", &MockBoardLib{}, (*BoardController).CreateSection, "", map[string]interface{}{"name": "Backlog"}, 201, "success to create section"},
+		{"error in create section", &MockFailBoardLib{}, (*BoardController).CreateSection, "", map[string]interface{}{"name": "Backlog"}, 500, "error in server"},
+		{"success to update section", &MockBoardLib{}, (*BoardController).PutSection, "", map[string]interface{}{"position": 2}, 200, "success to update section"},
+		{"update missing section", &MockFailBoardLib{}, (*BoardController).PutSection, "", map[string]interface{}{"name": "Doing"}, 404, "section not found"},
+		{"success to delete section", &MockBoardLib{}, (*BoardController).DeleteSection, "", nil, 200, "success to delete section"},
+		{"delete missing section", &MockFailBoardLib{}, (*BoardController).DeleteSection, "", nil, 404, "section not found"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/"+tc.query, bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/projects/:id/sections/:section_id")
+			context.SetParamNames("id", "section_id")
+			context.SetParamValues("1", "2")
+
+			boardController := New(tc.repo)
+			if err := middlewares.JwtMiddleware()(tc.handler(boardController))(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetRespFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+}
+
+type MockBoardLib struct{}
+
+func (m *MockBoardLib) GetBoard(project_id int, user_id int, by string) (response.BoardResponse, error) {
+	return response.BoardResponse{By: board.ByStatus, Columns: []response.ColumnResponse{{Key: "todo", Name: "todo", Tasks: []taskResp.TaskResponse{{ID: 1, Rank: "n0"}}}}}, nil
+}
+
+func (m *MockBoardLib) GetSections(project_id int, user_id int) ([]response.SectionResponse, error) {
+	return []response.SectionResponse{{ID: 2, Name: "Backlog"}}, nil
+}
+
+func (m *MockBoardLib) CreateSection(project_id int, user_id int, sectionReq request.SectionRequest) (board.Section, error) {
+	section := sectionReq.ToSection()
+	section.ID, section.Project_ID = 2, uint(project_id)
+	return section, nil
+}
+
+func (m *MockBoardLib) UpdateSection(project_id int, id int, user_id int, sectionReq request.SectionRequest) (board.Section, error) {
+	return board.Section{ID: uint(id), Project_ID: uint(project_id), Name: "Backlog", Position: 2}, nil
+}
+
+func (m *MockBoardLib) DeleteSection(project_id int, id int, user_id int) error {
+	return nil
+}
+
+type MockFailBoardLib struct{}
+
+func (m *MockFailBoardLib) GetBoard(project_id int, user_id int, by string) (response.BoardResponse, error) {
+	return response.BoardResponse{}, _taskLib.ErrInvalidBoard
+}
+
+func (m *MockFailBoardLib) GetSections(project_id int, user_id int) ([]response.SectionResponse, error) {
+	return nil, _proLib.ErrForbidden
+}
+
+func (m *MockFailBoardLib) CreateSection(project_id int, user_id int, sectionReq request.SectionRequest) (board.Section, error) {
+	return board.Section{}, errors.New("error in database process")
+}
+
+func (m *MockFailBoardLib) UpdateSection(project_id int, id int, user_id int, sectionReq request.SectionRequest) (board.Section, error) {
+	return board.Section{}, _taskLib.ErrSectionNotFound
+}
+
+func (m *MockFailBoardLib) DeleteSection(project_id int, id int, user_id int) error {
+	return _taskLib.ErrSectionNotFound
+}
+
+type MockAuthLib struct{}
+
+func (m *MockAuthLib) Login(UserLogin reqU.Userlogin) (user.User, error) {
+	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
+}
+
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
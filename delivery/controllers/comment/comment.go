@@ -0,0 +1,147 @@
+package comment
+
+import (
+	"net/http"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/comment"
+	"part3/models/base"
+	"part3/models/comment/request"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CommentController struct {
+	repo comment.Comment
+}
+
+func New(repository comment.Comment) *CommentController {
+	return &CommentController{
+		repo: repository,
+	}
+}
+
+// commentFields are the failing fields of a comment the tags cannot check, a
+// body of blanks is missing
+func commentFields(commentReq *request.CommentRequest) []base.FieldError {
+	if strings.TrimSpace(commentReq.Body) == "" {
+		return []base.FieldError{{Field: "body", Code: "required", Message: "body is required"}}
+	}
+	return nil
+}
+
+func (cc *CommentController) GetAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := cc.repo.GetAll(task_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get comments", res))
+	}
+}
+
+// Create notifies the project members mentioned with @handle in the body
+func (cc *CommentController) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		req := request.CommentRequest{}
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input comment", nil))
+		}
+		if err := validation.Check(c, &req, commentFields(&req)...); err != nil {
+			return err
+		}
+		body := strings.TrimSpace(req.Body)
+
+		res, err := cc.repo.Create(task_id, user_id, body)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, base.Success(http.StatusCreated, "success to create comment", res))
+	}
+}
+
+func (cc *CommentController) Put() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := validation.ParamID(c, "comment_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		req := request.CommentRequest{}
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input comment", nil))
+		}
+		if err := validation.Check(c, &req, commentFields(&req)...); err != nil {
+			return err
+		}
+		body := strings.TrimSpace(req.Body)
+
+		res, err := cc.repo.UpdateById(task_id, id, user_id, body)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to update comment", res))
+	}
+}
+
+func (cc *CommentController) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := validation.ParamID(c, "comment_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := cc.repo.DeleteById(task_id, id, user_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to delete comment", nil))
+	}
+}
+
+func (cc *CommentController) GetHistory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := validation.ParamID(c, "comment_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := cc.repo.GetHistory(task_id, id, user_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get comment history", res))
+	}
+}
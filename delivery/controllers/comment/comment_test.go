@@ -0,0 +1,166 @@
+package comment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"part3/delivery/controllers/auth"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	_commentLib "part3/lib/database/comment"
+	_proLib "part3/lib/database/project"
+	"part3/models/comment/response"
+	"part3/models/session"
+	"part3/models/user"
+	reqU "part3/models/user/request"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestComments(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _commentLib.Comment
+		handler func(cc *CommentController) echo.HandlerFunc
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"success to get comments", &MockCommentLib{}, (*CommentController).GetAll, nil, 200, "success to get comments"},
+		{"forbidden get comments", &MockFailCommentLib{}, (*CommentController).GetAll, nil, 404, "task or comment not found"},
+		{"error in input comment", &MockCommentLib{}, (*CommentController).Create, map[string]interface{}{"body": "   "}, 422, "validation failed"},
+		{"error in input comment too long", &MockCommentLib{}, (*CommentController).Create, map[string]interface{}{"body": strings.Repeat("a", 10001)}, 422, "validation failed"},
+		{"success to create comment", &MockCommentLib{}, (*CommentController).Create, map[string]interface{}{"body": "ping @anonim"}, 201, "success to create comment"},
+		{"error in create comment", &MockFailCommentLib{}, (*CommentController).Create, map[string]interface{}{"body": "ping @anonim"}, 500, "error in server"},
+		{"success to update comment", &MockCommentLib{}, (*CommentController).Put, map[string]interface{}{"body": "pong"}, 200, "success to update comment"},
+		{"update comment of another user", &MockFailCommentLib{}, (*CommentController).Put, map[string]interface{}{"body": "pong"}, 403, "forbidden access"},
+		{"success to delete comment", &MockCommentLib{}, (*CommentController).Delete, nil, 200, "success to delete comment"},
+		{"delete comment of another user", &MockFailCommentLib{}, (*CommentController).Delete, nil, 403, "forbidden access"},
+		{"success to get comment history", &MockCommentLib{}, (*CommentController).GetHistory, nil, 200, "success to get comment history"},
+		{"history of missing comment", &MockFailCommentLib{}, (*CommentController).GetHistory, nil, 404, "task or comment not found"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/todo/tasks/:id/comments/:comment_id")
+			context.SetParamNames("id", "comment_id")
+			context.SetParamValues("1", "1")
+
+			commentController := New(tc.repo)
+			if err := middlewares.JwtMiddleware()(tc.handler(commentController))(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetRespFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+}
+
+type MockCommentLib struct{}
+
+func (m *MockCommentLib) GetAll(task_id int, user_id int) ([]response.CommentResponse, error) {
+	return []response.CommentResponse{}, nil
+}
+
+func (m *MockCommentLib) Create(task_id int, user_id int, body string) (response.CommentResponse, error) {
+	return response.CommentResponse{Id: 1, Task_id: uint(task_id), User_id: uint(user_id), Body: body, Mentions: []response.MentionResponse{}}, nil
+}
+
+func (m *MockCommentLib) UpdateById(task_id int, id int, user_id int, body string) (response.CommentResponse, error) {
+	return response.CommentResponse{Id: uint(id), Task_id: uint(task_id), User_id: uint(user_id), Body: body, Mentions: []response.MentionResponse{}}, nil
+}
+
+func (m *MockCommentLib) DeleteById(task_id int, id int, user_id int) error {
+	return nil
+}
+
+func (m *MockCommentLib) GetHistory(task_id int, id int, user_id int) ([]response.RevisionResponse, error) {
+	return []response.RevisionResponse{{Id: 1, Body: "ping"}}, nil
+}
+
+type MockFailCommentLib struct{}
+
+func (m *MockFailCommentLib) GetAll(task_id int, user_id int) ([]response.CommentResponse, error) {
+	return nil, _commentLib.ErrCommentNotFound
+}
+
+func (m *MockFailCommentLib) Create(task_id int, user_id int, body string) (response.CommentResponse, error) {
+	return response.CommentResponse{}, errors.New("error in database process")
+}
+
+func (m *MockFailCommentLib) UpdateById(task_id int, id int, user_id int, body string) (response.CommentResponse, error) {
+	return response.CommentResponse{}, _proLib.ErrForbidden
+}
+
+func (m *MockFailCommentLib) DeleteById(task_id int, id int, user_id int) error {
+	return _proLib.ErrForbidden
+}
+
+func (m *MockFailCommentLib) GetHistory(task_id int, id int, user_id int) ([]response.RevisionResponse, error) {
+	return nil, _commentLib.ErrCommentNotFound
+}
+
+type MockAuthLib struct{}
+
+func (m *MockAuthLib) Login(UserLogin reqU.Userlogin) (user.User, error) {
+	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
+}
+
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
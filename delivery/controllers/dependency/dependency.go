@@ -0,0 +1,103 @@
+package dependency
+
+import (
+	"net/http"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/task"
+	"part3/models/base"
+	"part3/models/task/request"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DependencyController struct {
+	repo task.Dependency
+}
+
+func New(repository task.Dependency) *DependencyController {
+	return &DependencyController{
+		repo: repository,
+	}
+}
+
+func (dc *DependencyController) GetAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := dc.repo.GetAll(task_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get dependencies", res))
+	}
+}
+
+// Create makes the task wait on the blocker given in the body
+func (dc *DependencyController) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		dep := request.DependencyRequest{}
+		if err := c.Bind(&dep); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input dependency", nil))
+		}
+		if err := c.Validate(&dep); err != nil {
+			return err
+		}
+
+		res, err := dc.repo.Create(task_id, user_id, dep.Blocker_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, base.Success(http.StatusCreated, "success to create dependency", res))
+	}
+}
+
+func (dc *DependencyController) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		blocker_id, err := validation.ParamID(c, "blocker_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := dc.repo.DeleteById(task_id, blocker_id, user_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to delete dependency", nil))
+	}
+}
+
+// GetGraph returns the dependency DAG of a project with its critical path
+func (dc *DependencyController) GetGraph() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := dc.repo.GetGraph(project_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get graph", res))
+	}
+}
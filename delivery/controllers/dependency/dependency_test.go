@@ -0,0 +1,159 @@
+package dependency
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"part3/delivery/controllers/auth"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	_proLib "part3/lib/database/project"
+	_taskLib "part3/lib/database/task"
+	"part3/models/session"
+	"part3/models/task/response"
+	"part3/models/user"
+	reqU "part3/models/user/request"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestDependencies(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _taskLib.Dependency
+		handler func(dc *DependencyController) echo.HandlerFunc
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"success to get dependencies", &MockDependencyLib{}, (*DependencyController).GetAll, nil, 200, "success to get dependencies"},
+		{"get dependencies of missing task", &MockFailDependencyLib{}, (*DependencyController).GetAll, nil, 404, "task or dependency not found"},
+		{"error in input dependency", &MockDependencyLib{}, (*DependencyController).Create, map[string]interface{}{"blocker_id": 0}, 422, "validation failed"},
+		{"success to create dependency", &MockDependencyLib{}, (*DependencyController).Create, map[string]interface{}{"blocker_id": 2}, 201, "success to create dependency"},
+		{"create dependency cycle", &MockFailDependencyLib{}, (*DependencyController).Create, map[string]interface{}{"blocker_id": 2}, 409, "dependency would create a cycle"},
+		{"success to delete dependency", &MockDependencyLib{}, (*DependencyController).Delete, nil, 200, "success to delete dependency"},
+		{"delete dependency forbidden", &MockFailDependencyLib{}, (*DependencyController).Delete, nil, 403, "forbidden access"},
+		{"success to get graph", &MockDependencyLib{}, (*DependencyController).GetGraph, nil, 200, "success to get graph"},
+		{"error in get graph", &MockFailDependencyLib{}, (*DependencyController).GetGraph, nil, 500, "error in server"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/todo/tasks/:id/dependencies/:blocker_id")
+			context.SetParamNames("id", "blocker_id")
+			context.SetParamValues("1", "2")
+
+			dependencyController := New(tc.repo)
+			if err := middlewares.JwtMiddleware()(tc.handler(dependencyController))(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetRespFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+}
+
+type MockDependencyLib struct{}
+
+func (m *MockDependencyLib) GetAll(task_id int, user_id int) (response.DependenciesResponse, error) {
+	return response.DependenciesResponse{Blocked_by: []response.DependencyTaskResponse{{ID: 2, Name: "anonim"}}, Blocks: []response.DependencyTaskResponse{}}, nil
+}
+
+func (m *MockDependencyLib) Create(task_id int, user_id int, blocker_id uint) (response.DependenciesResponse, error) {
+	return response.DependenciesResponse{Blocked_by: []response.DependencyTaskResponse{{ID: blocker_id, Name: "anonim"}}, Blocks: []response.DependencyTaskResponse{}}, nil
+}
+
+func (m *MockDependencyLib) DeleteById(task_id int, blocker_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockDependencyLib) GetGraph(project_id int, user_id int) (response.GraphResponse, error) {
+	return response.GraphResponse{
+		Nodes:           []response.GraphNodeResponse{{ID: 1, Duration: 1}, {ID: 2, Duration: 2}},
+		Edges:           []response.GraphEdgeResponse{{Blocker_id: 2, Blocked_id: 1}},
+		Critical_path:   []uint{2, 1},
+		Critical_length: 3,
+	}, nil
+}
+
+type MockFailDependencyLib struct{}
+
+func (m *MockFailDependencyLib) GetAll(task_id int, user_id int) (response.DependenciesResponse, error) {
+	return response.DependenciesResponse{}, _taskLib.ErrDependencyNotFound
+}
+
+func (m *MockFailDependencyLib) Create(task_id int, user_id int, blocker_id uint) (response.DependenciesResponse, error) {
+	return response.DependenciesResponse{}, _taskLib.ErrDependencyCycle
+}
+
+func (m *MockFailDependencyLib) DeleteById(task_id int, blocker_id int, user_id int) error {
+	return _proLib.ErrForbidden
+}
+
+func (m *MockFailDependencyLib) GetGraph(project_id int, user_id int) (response.GraphResponse, error) {
+	return response.GraphResponse{}, errors.New("error in database process")
+}
+
+type MockAuthLib struct{}
+
+func (m *MockAuthLib) Login(UserLogin reqU.Userlogin) (user.User, error) {
+	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
+}
+
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
@@ -0,0 +1,7 @@
+package invitation
+
+type GetRespFormat struct {
+	Code    int                    `json:"code"`
+	Message string                 `json:"message"`
+	Data    map[string]interface{} `json:"data"`
+}
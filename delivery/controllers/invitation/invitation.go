@@ -0,0 +1,142 @@
+package invitation
+
+import (
+	"fmt"
+	"net/http"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/project"
+	"part3/lib/mailer"
+	"part3/models/base"
+	"part3/models/project/request"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type InvitationController struct {
+	repo    project.Invitation
+	mailer  mailer.Mailer
+	baseURL string
+}
+
+func New(repository project.Invitation, mail mailer.Mailer, baseURL string) *InvitationController {
+	return &InvitationController{
+		repo:    repository,
+		mailer:  mail,
+		baseURL: baseURL,
+	}
+}
+
+func (ic *InvitationController) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+		newInvitation := request.InvitationRequest{}
+
+		if err := c.Bind(&newInvitation); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input invitation", nil))
+		}
+		if err := c.Validate(&newInvitation); err != nil {
+			return err
+		}
+
+		res, token, err := ic.repo.CreateInvitation(id, user_id, newInvitation.ToInvitation())
+		if err != nil {
+			return err
+		}
+
+		msg := mailer.Message{
+			To:      res.Email,
+			Subject: fmt.Sprintf("You are invited to %v", res.Project_name),
+			Body: fmt.Sprintf("You have been invited to join the project %v as %v.\n\nOpen %v/%v to accept or decline, the invitation expires on %v.",
+				res.Project_name, res.Role, ic.baseURL, token, res.Expires_at.UTC().Format("2006-01-02 15:04 MST")),
+		}
+		// an invitation nobody received cannot be answered, the retry makes a new one
+		if err := ic.mailer.Send(msg); err != nil {
+			if errRevoke := ic.repo.RevokeInvitation(id, user_id, int(res.Id)); errRevoke != nil {
+				log.Warn("error in revoke unsent invitation ", errRevoke)
+			}
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, base.Success(http.StatusCreated, "success to create invitation", res))
+	}
+}
+
+func (ic *InvitationController) GetAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := ic.repo.GetInvitations(id, user_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get invitations", res))
+	}
+}
+
+func (ic *InvitationController) GetMine() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := ic.repo.GetMyInvitations(user_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get invitations", res))
+	}
+}
+
+func (ic *InvitationController) Accept() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := ic.repo.AcceptInvitation(c.Param("token"), user_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to accept invitation", res.ToMemberResponse()))
+	}
+}
+
+// Decline only needs the token, invitees do not have to register to turn an invitation down
+func (ic *InvitationController) Decline() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := ic.repo.DeclineInvitation(c.Param("token")); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to decline invitation", nil))
+	}
+}
+
+func (ic *InvitationController) Revoke() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		invitation_id, err := validation.ParamID(c, "invitation_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := ic.repo.RevokeInvitation(id, user_id, invitation_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to revoke invitation", nil))
+	}
+}
@@ -0,0 +1,244 @@
+package invitation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"part3/delivery/controllers/auth"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	_proLib "part3/lib/database/project"
+	"part3/lib/mailer"
+	proMod "part3/models/project"
+	"part3/models/project/response"
+	"part3/models/session"
+	"part3/models/user"
+	reqU "part3/models/user/request"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestInvitations(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _proLib.Invitation
+		mail    mailer.Mailer
+		handler func(ic *InvitationController) echo.HandlerFunc
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"error in input invitation", &MockInvitationLib{}, &MockMailer{}, (*InvitationController).Create, map[string]interface{}{"email": "not an email", "role": "editor"}, 422, "validation failed"},
+		{"error in input invitation role", &MockInvitationLib{}, &MockMailer{}, (*InvitationController).Create, map[string]interface{}{"email": "anonim@mail.com", "role": "admin"}, 422, "validation failed"},
+		{"error in server", &MockInvitationLib{}, &MockMailer{fail: true}, (*InvitationController).Create, map[string]interface{}{"email": "anonim@mail.com", "role": "editor"}, 500, "error in server"},
+		{"member already exists", &MockFailInvitationLib{}, &MockMailer{}, (*InvitationController).Create, map[string]interface{}{"email": "anonim@mail.com", "role": "editor"}, 409, "user is already a member"},
+		{"success to get invitations", &MockInvitationLib{}, &MockMailer{}, (*InvitationController).GetAll, nil, 200, "success to get invitations"},
+		{"forbidden get invitations", &MockFailInvitationLib{}, &MockMailer{}, (*InvitationController).GetAll, nil, 403, "forbidden access"},
+		{"success to get my invitations", &MockInvitationLib{}, &MockMailer{}, (*InvitationController).GetMine, nil, 200, "success to get invitations"},
+		{"success to accept invitation", &MockInvitationLib{}, &MockMailer{}, (*InvitationController).Accept, nil, 200, "success to accept invitation"},
+		{"accept for another email", &MockFailInvitationLib{}, &MockMailer{}, (*InvitationController).Accept, nil, 403, "invitation was sent to another email"},
+		{"success to decline invitation", &MockInvitationLib{}, &MockMailer{}, (*InvitationController).Decline, nil, 200, "success to decline invitation"},
+		{"decline expired invitation", &MockFailInvitationLib{}, &MockMailer{}, (*InvitationController).Decline, nil, 404, "invalid or expired invitation"},
+		{"success to revoke invitation", &MockInvitationLib{}, &MockMailer{}, (*InvitationController).Revoke, nil, 200, "success to revoke invitation"},
+		{"revoke missing invitation", &MockFailInvitationLib{}, &MockMailer{}, (*InvitationController).Revoke, nil, 404, "invitation not found"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/projects/:id/invitations/:invitation_id")
+			context.SetParamNames("id", "invitation_id", "token")
+			context.SetParamValues("1", "1", "1.signature")
+
+			invitationController := New(tc.repo, tc.mail, "http://localhost/invitations")
+			if err := middlewares.JwtMiddleware()(tc.handler(invitationController))(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetRespFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+
+	t.Run("success to create invitation", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{"email": "Anonim@mail.com", "role": "viewer"})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects/:id/invitations")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		mail := &MockMailer{}
+		invitationController := New(&MockInvitationLib{}, mail, "http://localhost/invitations")
+		if err := middlewares.JwtMiddleware()(invitationController.Create())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 201, response.Code)
+		assert.Equal(t, "success to create invitation", response.Message)
+		assert.Equal(t, "anonim@mail.com", response.Data["email"])
+		assert.Equal(t, "anonim@mail.com", mail.sent.To)
+		assert.True(t, strings.Contains(mail.sent.Body, "http://localhost/invitations/1.signature"))
+	})
+
+	t.Run("unsent invitation is revoked", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{"email": "anonim@mail.com", "role": "viewer"})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects/:id/invitations")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		repo := &MockInvitationLib{}
+		invitationController := New(repo, &MockMailer{fail: true}, "http://localhost/invitations")
+		if err := middlewares.JwtMiddleware()(invitationController.Create())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, 1, repo.revoked)
+	})
+}
+
+type MockMailer struct {
+	fail bool
+	sent mailer.Message
+}
+
+func (m *MockMailer) Send(msg mailer.Message) error {
+	if m.fail {
+		return errors.New("error in send mail")
+	}
+	m.sent = msg
+	return nil
+}
+
+type MockInvitationLib struct {
+	revoked int
+}
+
+func (m *MockInvitationLib) CreateInvitation(id int, user_id int, inv proMod.Invitation) (response.InvitationResponse, string, error) {
+	return response.InvitationResponse{Id: 1, Project_id: uint(id), Project_name: "Proanonim", Email: inv.Email, Role: inv.Role}, "1.signature", nil
+}
+
+func (m *MockInvitationLib) GetInvitations(id int, user_id int) ([]response.InvitationResponse, error) {
+	return []response.InvitationResponse{}, nil
+}
+
+func (m *MockInvitationLib) GetMyInvitations(user_id int) ([]response.InvitationResponse, error) {
+	return []response.InvitationResponse{}, nil
+}
+
+func (m *MockInvitationLib) AcceptInvitation(token string, user_id int) (proMod.Member, error) {
+	return proMod.Member{Project_ID: 1, User_ID: uint(user_id), Role: proMod.MemberEditor}, nil
+}
+
+func (m *MockInvitationLib) DeclineInvitation(token string) error {
+	return nil
+}
+
+func (m *MockInvitationLib) RevokeInvitation(id int, user_id int, invitation_id int) error {
+	m.revoked = invitation_id
+	return nil
+}
+
+type MockFailInvitationLib struct{}
+
+func (m *MockFailInvitationLib) CreateInvitation(id int, user_id int, inv proMod.Invitation) (response.InvitationResponse, string, error) {
+	return response.InvitationResponse{}, "", _proLib.ErrMemberExists
+}
+
+func (m *MockFailInvitationLib) GetInvitations(id int, user_id int) ([]response.InvitationResponse, error) {
+	return nil, _proLib.ErrForbidden
+}
+
+func (m *MockFailInvitationLib) GetMyInvitations(user_id int) ([]response.InvitationResponse, error) {
+	return nil, errors.New("error in database process")
+}
+
+func (m *MockFailInvitationLib) AcceptInvitation(token string, user_id int) (proMod.Member, error) {
+	return proMod.Member{}, _proLib.ErrInvitationEmail
+}
+
+func (m *MockFailInvitationLib) DeclineInvitation(token string) error {
+	return _proLib.ErrInvalidInvitation
+}
+
+func (m *MockFailInvitationLib) RevokeInvitation(id int, user_id int, invitation_id int) error {
+	return _proLib.ErrInvitationNotFound
+}
+
+type MockAuthLib struct{}
+
+func (m *MockAuthLib) Login(UserLogin reqU.Userlogin) (user.User, error) {
+	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
+}
+
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
@@ -0,0 +1,7 @@
+package label
+
+type GetRespFormat struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
@@ -0,0 +1,178 @@
+package label
+
+import (
+	"net/http"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/label"
+	"part3/models/base"
+	_label "part3/models/label"
+	"part3/models/label/request"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LabelController struct {
+	repo label.Label
+}
+
+func New(repository label.Label) *LabelController {
+	return &LabelController{
+		repo: repository,
+	}
+}
+
+// labelFields are the failing fields of a label the tags cannot check, a name
+// is required on create only
+func labelFields(labelReq *request.LabelRequest, create bool) []base.FieldError {
+	newLabel := labelReq.ToLabel()
+	fields := []base.FieldError{}
+	if create && newLabel.Name == "" {
+		fields = append(fields, base.FieldError{Field: "name", Code: "required", Message: "name is required"})
+	}
+	if newLabel.Colour != "" && !_label.IsValidColour(newLabel.Colour) {
+		fields = append(fields, base.FieldError{Field: "colour", Code: "invalid_colour", Message: "colour must be a hex colour like #1f6feb"})
+	}
+	return fields
+}
+
+func (lc *LabelController) GetAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := lc.repo.GetAll(project_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get labels", res))
+	}
+}
+
+func (lc *LabelController) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		req := request.LabelRequest{}
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input label", nil))
+		}
+		if err := validation.Check(c, &req, labelFields(&req, true)...); err != nil {
+			return err
+		}
+		// an empty colour falls back to the default
+		newLabel := req.ToLabel()
+		if newLabel.Colour == "" {
+			newLabel.Colour = _label.DefaultColour
+		}
+
+		res, err := lc.repo.Create(project_id, user_id, newLabel)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, base.Success(http.StatusCreated, "success to create label", res.ToLabelResponse()))
+	}
+}
+
+func (lc *LabelController) Put() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := validation.ParamID(c, "label_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		req := request.LabelRequest{}
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input label", nil))
+		}
+		if err := validation.Check(c, &req, labelFields(&req, false)...); err != nil {
+			return err
+		}
+
+		res, err := lc.repo.UpdateById(project_id, id, user_id, req.ToLabel())
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to update label", res.ToLabelResponse()))
+	}
+}
+
+func (lc *LabelController) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := validation.ParamID(c, "label_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := lc.repo.DeleteById(project_id, id, user_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to delete label", nil))
+	}
+}
+
+func (lc *LabelController) Attach() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		req := request.TaskLabelRequest{}
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input label", nil))
+		}
+		if err := c.Validate(&req); err != nil {
+			return err
+		}
+
+		res, err := lc.repo.Attach(task_id, user_id, req.Label_ids)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to label task", res))
+	}
+}
+
+func (lc *LabelController) Detach() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		task_id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		label_id, err := validation.ParamID(c, "label_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := lc.repo.Detach(task_id, label_id, user_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to unlabel task", nil))
+	}
+}
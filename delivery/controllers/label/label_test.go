@@ -0,0 +1,181 @@
+package label
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"part3/delivery/controllers/auth"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	_labelLib "part3/lib/database/label"
+	_proLib "part3/lib/database/project"
+	"part3/models/label"
+	"part3/models/label/response"
+	"part3/models/session"
+	"part3/models/user"
+	reqU "part3/models/user/request"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestLabels(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _labelLib.Label
+		handler func(lc *LabelController) echo.HandlerFunc
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"success to get labels", &MockLabelLib{}, (*LabelController).GetAll, nil, 200, "success to get labels"},
+		{"forbidden get labels", &MockFailLabelLib{}, (*LabelController).GetAll, nil, 404, "task or label not found"},
+		{"error in input label name", &MockLabelLib{}, (*LabelController).Create, map[string]interface{}{"name": "  "}, 422, "validation failed"},
+		{"error in input label too long", &MockLabelLib{}, (*LabelController).Create, map[string]interface{}{"name": strings.Repeat("a", 51)}, 422, "validation failed"},
+		{"error in input label colour", &MockLabelLib{}, (*LabelController).Create, map[string]interface{}{"name": "bug", "colour": "red"}, 422, "validation failed"},
+		{"success to create label", &MockLabelLib{}, (*LabelController).Create, map[string]interface{}{"name": "bug"}, 201, "success to create label"},
+		{"create existing label", &MockFailLabelLib{}, (*LabelController).Create, map[string]interface{}{"name": "bug", "colour": "#FF0000"}, 409, "label already exists"},
+		{"success to update label", &MockLabelLib{}, (*LabelController).Put, map[string]interface{}{"colour": "#00ff00"}, 200, "success to update label"},
+		{"update label forbidden", &MockFailLabelLib{}, (*LabelController).Put, map[string]interface{}{"name": "bug"}, 403, "forbidden access"},
+		{"success to delete label", &MockLabelLib{}, (*LabelController).Delete, nil, 200, "success to delete label"},
+		{"error in delete label", &MockFailLabelLib{}, (*LabelController).Delete, nil, 500, "error in server"},
+		{"error in input label ids", &MockLabelLib{}, (*LabelController).Attach, map[string]interface{}{"label_ids": []uint{}}, 422, "validation failed"},
+		{"success to label task", &MockLabelLib{}, (*LabelController).Attach, map[string]interface{}{"label_ids": []uint{1}}, 200, "success to label task"},
+		{"label of another project", &MockFailLabelLib{}, (*LabelController).Attach, map[string]interface{}{"label_ids": []uint{1}}, 422, "label must belong to the task's project"},
+		{"success to unlabel task", &MockLabelLib{}, (*LabelController).Detach, nil, 200, "success to unlabel task"},
+		{"unlabel missing label", &MockFailLabelLib{}, (*LabelController).Detach, nil, 404, "task or label not found"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/projects/:id/labels/:label_id")
+			context.SetParamNames("id", "label_id")
+			context.SetParamValues("1", "1")
+
+			labelController := New(tc.repo)
+			if err := middlewares.JwtMiddleware()(tc.handler(labelController))(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetRespFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+}
+
+type MockLabelLib struct{}
+
+func (m *MockLabelLib) GetAll(project_id int, user_id int) ([]response.LabelResponse, error) {
+	return []response.LabelResponse{{ID: 1, Name: "bug", Colour: label.DefaultColour}}, nil
+}
+
+func (m *MockLabelLib) Create(project_id int, user_id int, newLabel label.Label) (label.Label, error) {
+	newLabel.ID = 1
+	newLabel.Project_ID = uint(project_id)
+	return newLabel, nil
+}
+
+func (m *MockLabelLib) UpdateById(project_id int, id int, user_id int, upLabel label.Label) (label.Label, error) {
+	return label.Label{ID: uint(id), Project_ID: uint(project_id), Name: "bug", Colour: upLabel.Colour}, nil
+}
+
+func (m *MockLabelLib) DeleteById(project_id int, id int, user_id int) error {
+	return nil
+}
+
+func (m *MockLabelLib) Attach(task_id int, user_id int, label_ids []uint) ([]response.LabelResponse, error) {
+	return []response.LabelResponse{{ID: 1, Name: "bug", Colour: label.DefaultColour}}, nil
+}
+
+func (m *MockLabelLib) Detach(task_id int, label_id int, user_id int) error {
+	return nil
+}
+
+type MockFailLabelLib struct{}
+
+func (m *MockFailLabelLib) GetAll(project_id int, user_id int) ([]response.LabelResponse, error) {
+	return nil, _labelLib.ErrLabelNotFound
+}
+
+func (m *MockFailLabelLib) Create(project_id int, user_id int, newLabel label.Label) (label.Label, error) {
+	return label.Label{}, _labelLib.ErrLabelExists
+}
+
+func (m *MockFailLabelLib) UpdateById(project_id int, id int, user_id int, upLabel label.Label) (label.Label, error) {
+	return label.Label{}, _proLib.ErrForbidden
+}
+
+func (m *MockFailLabelLib) DeleteById(project_id int, id int, user_id int) error {
+	return errors.New("error in database process")
+}
+
+func (m *MockFailLabelLib) Attach(task_id int, user_id int, label_ids []uint) ([]response.LabelResponse, error) {
+	return nil, _labelLib.ErrInvalidLabel
+}
+
+func (m *MockFailLabelLib) Detach(task_id int, label_id int, user_id int) error {
+	return _labelLib.ErrLabelNotFound
+}
+
+type MockAuthLib struct{}
+
+func (m *MockAuthLib) Login(UserLogin reqU.Userlogin) (user.User, error) {
+	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
+}
+
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
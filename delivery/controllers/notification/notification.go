@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"errors"
+	"net/http"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/notification"
+	"part3/models/base"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type NotificationController struct {
+	repo notification.Notification
+}
+
+func New(repository notification.Notification) *NotificationController {
+	return &NotificationController{
+		repo: repository,
+	}
+}
+
+func (nc *NotificationController) GetAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		unread := false
+		if value := c.QueryParam("unread"); value != "" {
+			var err error
+			if unread, err = strconv.ParseBool(value); err != nil {
+				return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input unread", nil))
+			}
+		}
+
+		res, err := nc.repo.GetAll(user_id, unread)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to get notifications", res))
+	}
+}
+
+func (nc *NotificationController) Read() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		err = nc.repo.MarkRead(id, user_id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, base.NotFound(nil, "notification not found", nil))
+		}
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to read notification", nil))
+	}
+}
+
+func (nc *NotificationController) ReadAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := nc.repo.MarkAllRead(user_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "success to read notifications", nil))
+	}
+}
@@ -0,0 +1,141 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"part3/delivery/controllers/auth"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	_notificationLib "part3/lib/database/notification"
+	"part3/models/notification/response"
+	"part3/models/session"
+	"part3/models/user"
+	reqU "part3/models/user/request"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestNotifications(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _notificationLib.Notification
+		handler func(nc *NotificationController) echo.HandlerFunc
+		query   string
+		code    int
+		message string
+	}{
+		{"success to get notifications", &MockNotificationLib{}, (*NotificationController).GetAll, "/?unread=true", 200, "success to get notifications"},
+		{"error in input unread", &MockNotificationLib{}, (*NotificationController).GetAll, "/?unread=maybe", 400, "error in input unread"},
+		{"error in get notifications", &MockFailNotificationLib{}, (*NotificationController).GetAll, "/", 500, "error in server"},
+		{"success to read notification", &MockNotificationLib{}, (*NotificationController).Read, "/", 200, "success to read notification"},
+		{"notification not found", &MockFailNotificationLib{}, (*NotificationController).Read, "/", 404, "notification not found"},
+		{"success to read notifications", &MockNotificationLib{}, (*NotificationController).ReadAll, "/", 200, "success to read notifications"},
+		{"error in read notifications", &MockFailNotificationLib{}, (*NotificationController).ReadAll, "/", 500, "error in server"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			req := httptest.NewRequest(http.MethodGet, tc.query, bytes.NewBuffer(nil))
+			res := httptest.NewRecorder()
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/notifications/:id/read")
+			context.SetParamNames("id")
+			context.SetParamValues("1")
+
+			notificationController := New(tc.repo)
+			if err := middlewares.JwtMiddleware()(tc.handler(notificationController))(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetRespFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+}
+
+type MockNotificationLib struct{}
+
+func (m *MockNotificationLib) GetAll(user_id int, unread bool) ([]response.NotificationResponse, error) {
+	return []response.NotificationResponse{}, nil
+}
+
+func (m *MockNotificationLib) MarkRead(id int, user_id int) error {
+	return nil
+}
+
+func (m *MockNotificationLib) MarkAllRead(user_id int) error {
+	return nil
+}
+
+type MockFailNotificationLib struct{}
+
+func (m *MockFailNotificationLib) GetAll(user_id int, unread bool) ([]response.NotificationResponse, error) {
+	return nil, errors.New("error in database process")
+}
+
+func (m *MockFailNotificationLib) MarkRead(id int, user_id int) error {
+	return gorm.ErrRecordNotFound
+}
+
+func (m *MockFailNotificationLib) MarkAllRead(user_id int) error {
+	return errors.New("error in database process")
+}
+
+type MockAuthLib struct{}
+
+func (m *MockAuthLib) Login(UserLogin reqU.Userlogin) (user.User, error) {
+	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
+}
+
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
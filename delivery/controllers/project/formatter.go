@@ -1,7 +1,23 @@
 package project
 
+import "part3/models/base"
+
 type GetRespFormat struct {
 	Code    int                    `json:"code"`
 	Message string                 `json:"message"`
 	Data    map[string]interface{} `json:"data"`
 }
+
+type GetListFormat struct {
+	Code    int                      `json:"code"`
+	Message string                   `json:"message"`
+	Data    []map[string]interface{} `json:"data"`
+	Meta    map[string]interface{}   `json:"meta"`
+}
+
+type ValidationRespFormat struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Error   string            `json:"error"`
+	Data    []base.FieldError `json:"data"`
+}
@@ -3,10 +3,12 @@ package project
 import (
 	"net/http"
 	"part3/delivery/middlewares"
+	"part3/delivery/validation"
 	"part3/lib/database/project"
 	"part3/models/base"
 	"part3/models/project/request"
-	"strconv"
+	wfReq "part3/models/workflow/request"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -25,14 +27,17 @@ func (pc *ProController) Create() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		user_id := int(middlewares.ExtractTokenId(c))
 		newPro := request.ProRequest{}
-		if err := c.Bind(&newPro); err != nil || newPro.Name == "" {
+		if err := c.Bind(&newPro); err != nil {
 			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input project", nil))
 		}
+		if err := c.Validate(&newPro); err != nil {
+			return err
+		}
 
 		res, err := pc.repo.Create(user_id, newPro.ToProject())
 
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(nil, "error in call database", nil))
+			return err
 		}
 		return c.JSON(http.StatusCreated, base.Success(http.StatusCreated, "success create project", res.ToProResponse()))
 	}
@@ -42,65 +47,116 @@ func (pc *ProController) GetAll() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		user_id := int(middlewares.ExtractTokenId(c))
 
-		res, err := pc.repo.GetAll(user_id)
+		filter, err := proFilter(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input filter", nil))
+		}
+		page, err := base.ParsePage(c.QueryParams())
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input page", nil))
+		}
+
+		res, meta, err := pc.repo.GetAll(user_id, filter, page)
 
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(
-				http.StatusInternalServerError,
-				"error in database process",
-				nil,
-			))
+			return err
 		}
 
-		return c.JSON(http.StatusCreated, base.Success(
-			http.StatusCreated,
+		meta.SetLinks(c.Request().URL)
+		return c.JSON(http.StatusOK, base.SuccessPage(
+			http.StatusOK,
 			"success to get all project",
 			res,
+			meta,
 		))
 	}
 }
 
+// proFilter reads the listing filters, dates must be RFC3339 with an offset
+func proFilter(c echo.Context) (request.ProFilter, error) {
+	filter := request.ProFilter{Name: c.QueryParam("name")}
+
+	for param, target := range map[string]**time.Time{"created_before": &filter.CreatedBefore, "created_after": &filter.CreatedAfter} {
+		if value := c.QueryParam(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, err
+			}
+			*target = &parsed
+		}
+	}
+
+	return filter, nil
+}
+
 func (pc *ProController) Put() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		id, _ := strconv.Atoi(c.Param("id"))
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
 		user_id := int(middlewares.ExtractTokenId(c))
 		upPro := request.ProRequest{}
-		if err := c.Bind(&upPro); err != nil || upPro.Name == "" {
+		if err := c.Bind(&upPro); err != nil {
 			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input project", nil))
 		}
 
-		res, err := pc.repo.UpdateById(id, user_id, upPro)
+		return pc.update(c, id, user_id, upPro)
+	}
+}
 
+// Patch changes only the fields of the merge patch
+func (pc *ProController) Patch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+		current, err := pc.repo.GetById(id, user_id)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(
-				http.StatusInternalServerError,
-				"error in database proces",
-				nil,
-			))
+			return err
+		}
+		upPro := request.ProRequest{Name: current.Name}
+		if err := validation.BindPatch(c, &upPro); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input project", nil))
 		}
 
-		return c.JSON(http.StatusCreated, base.Success(
-			http.StatusCreated,
-			"success to update project",
-			res.ToProResponse(),
-		))
+		return pc.update(c, id, user_id, upPro)
+	}
+}
+
+func (pc *ProController) update(c echo.Context, id int, user_id int, upPro request.ProRequest) error {
+	if err := c.Validate(&upPro); err != nil {
+		return err
 	}
+
+	res, err := pc.repo.UpdateById(id, user_id, upPro)
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, base.Success(
+		http.StatusOK,
+		"success to update project",
+		res.ToProResponse(),
+	))
 }
 
 func (pc *ProController) Delete() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		id, _ := strconv.Atoi(c.Param("id"))
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
 
 		user_id := int(middlewares.ExtractTokenId(c))
 
 		res, err := pc.repo.DeleteById(id, user_id)
 
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(
-				http.StatusInternalServerError,
-				"error in database process",
-				nil,
-			))
+			return err
 		}
 
 		return c.JSON(http.StatusOK, base.Success(
@@ -110,3 +166,166 @@ func (pc *ProController) Delete() echo.HandlerFunc {
 		))
 	}
 }
+
+func (pc *ProController) GetWorkflow() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := pc.repo.GetWorkflow(id, user_id)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to get workflow",
+			res.ToWorkflowResponse(),
+		))
+	}
+}
+
+func (pc *ProController) PutWorkflow() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+		upWorkflow := wfReq.WorkflowRequest{}
+
+		if err := c.Bind(&upWorkflow); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input workflow", nil))
+		}
+		wf, err := upWorkflow.ToWorkflow()
+		if err != nil {
+			return base.ValidationError("invalid_workflow", err.Error())
+		}
+
+		res, err := pc.repo.UpdateWorkflow(id, user_id, wf)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to update workflow",
+			res.ToWorkflowResponse(),
+		))
+	}
+}
+
+func (pc *ProController) GetMembers() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := pc.repo.GetMembers(id, user_id)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to get members",
+			res,
+		))
+	}
+}
+
+func (pc *ProController) AddMember() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+		newMember := request.MemberRequest{}
+
+		if err := c.Bind(&newMember); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input member", nil))
+		}
+		if err := c.Validate(&newMember); err != nil {
+			return err
+		}
+
+		res, err := pc.repo.AddMember(id, user_id, newMember.ToMember())
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, base.Success(
+			http.StatusCreated,
+			"success to add member",
+			res.ToMemberResponse(),
+		))
+	}
+}
+
+func (pc *ProController) PutMember() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		member_id, err := validation.ParamID(c, "user_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+		upMember := request.MemberRoleRequest{}
+
+		if err := c.Bind(&upMember); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input member", nil))
+		}
+		if err := c.Validate(&upMember); err != nil {
+			return err
+		}
+
+		res, err := pc.repo.UpdateMember(id, user_id, member_id, upMember.Role)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to update member",
+			res.ToMemberResponse(),
+		))
+	}
+}
+
+func (pc *ProController) DeleteMember() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		member_id, err := validation.ParamID(c, "user_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := pc.repo.RemoveMember(id, user_id, member_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to remove member",
+			nil,
+		))
+	}
+}
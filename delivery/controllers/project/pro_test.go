@@ -9,11 +9,18 @@ import (
 	"net/http/httptest"
 	"part3/delivery/controllers/auth"
 	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/paginate"
+	_proLib "part3/lib/database/project"
+	"part3/models/base"
 	proMod "part3/models/project"
 	"part3/models/project/request"
 	"part3/models/project/response"
+	"part3/models/session"
 	"part3/models/user"
 	reqU "part3/models/user/request"
+	"part3/models/workflow"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
@@ -26,6 +33,7 @@ func TestCreate(t *testing.T) {
 	var jwtToken string
 	t.Run("success login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -46,6 +54,7 @@ func TestCreate(t *testing.T) {
 
 	t.Run("error in input project", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]int{"name": 1})
 		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
 		res := httptest.NewRecorder()
@@ -56,8 +65,7 @@ func TestCreate(t *testing.T) {
 
 		taskController := NewRepo(&MockFailProLib{})
 		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetRespFormat{}
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
@@ -65,8 +73,9 @@ func TestCreate(t *testing.T) {
 		assert.Equal(t, "error in input project", response.Message)
 	})
 
-	t.Run("error in database process", func(t *testing.T) {
+	t.Run("error in server", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{
 			"name": "anonim",
 		})
@@ -80,18 +89,18 @@ func TestCreate(t *testing.T) {
 		taskController := NewRepo(&MockFailProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetRespFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in call database", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
 	t.Run("success to create project", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{
 			"name": "anonim",
 		})
@@ -105,8 +114,7 @@ func TestCreate(t *testing.T) {
 		taskController := NewRepo(&MockProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetRespFormat{}
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
@@ -119,6 +127,7 @@ func TestGetAll(t *testing.T) {
 	var jwtToken string
 	t.Run("success login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -137,8 +146,9 @@ func TestGetAll(t *testing.T) {
 		assert.NotNil(t, response.Data["token"])
 	})
 
-	t.Run("error in database process", func(t *testing.T) {
+	t.Run("error in server", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
@@ -150,17 +160,17 @@ func TestGetAll(t *testing.T) {
 		taskController := NewRepo(&MockFailProLib{})
 
 		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetRespFormat{}
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in database process", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
 	t.Run("success to get all project", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
@@ -172,20 +182,84 @@ func TestGetAll(t *testing.T) {
 		taskController := NewRepo(&MockProLib{})
 
 		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetRespFormat{}
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		assert.Equal(t, 201, response.Code)
+		assert.Equal(t, 200, response.Code)
 		assert.Equal(t, "success to get all project", response.Message)
 	})
+
+	t.Run("success to get all project with cursor link", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+
+		req := httptest.NewRequest(http.MethodGet, "/projects?name=home&sort=-created_at", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects")
+
+		taskController := NewRepo(&MockProLib{})
+
+		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetListFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 1, len(response.Data))
+		assert.Equal(t, "/projects?cursor=abc&name=home&sort=-created_at", response.Meta["next"])
+		assert.Nil(t, response.Meta["prev"])
+	})
+
+	t.Run("error in input filter", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+
+		req := httptest.NewRequest(http.MethodGet, "/projects?created_after=yesterday", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects")
+
+		taskController := NewRepo(&MockProLib{})
+
+		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 400, response.Code)
+		assert.Equal(t, "error in input filter", response.Message)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+
+		req := httptest.NewRequest(http.MethodGet, "/projects?cursor=stale", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects")
+
+		taskController := NewRepo(&MockProLib{})
+
+		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, "invalid cursor", response.Message)
+	})
 }
 
 func TestPut(t *testing.T) {
 	var jwtToken string
 	t.Run("success login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -204,8 +278,9 @@ func TestPut(t *testing.T) {
 		assert.NotNil(t, response.Data["token"])
 	})
 
-	t.Run("error in input projects", func(t *testing.T) {
+	t.Run("validation failed", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{})
 		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
 		res := httptest.NewRecorder()
@@ -216,20 +291,44 @@ func TestPut(t *testing.T) {
 		context.SetParamNames("id")
 		context.SetParamValues("1")
 		taskController := NewRepo(&MockFailProLib{})
-		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(taskController.Put())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
-		response := GetRespFormat{}
+		response := ValidationRespFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		assert.Equal(t, 400, response.Code)
-		assert.Equal(t, "error in input project", response.Message)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, "validation_failed", response.Error)
+		assert.Equal(t, []base.FieldError{{Field: "name", Code: "required", Message: "name is required"}}, response.Data)
+	})
+
+	t.Run("validation failed name too long", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{"name": strings.Repeat("a", 101)})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects/:id")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+		taskController := NewRepo(&MockFailProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.Put())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, "validation_failed", response.Error)
+		assert.Equal(t, []base.FieldError{{Field: "name", Code: "too_long", Message: "name must be at most 100 characters"}}, response.Data)
 	})
 
 	t.Run("error in database proses", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{
 			"name": "anonim",
 		})
@@ -239,25 +338,25 @@ func TestPut(t *testing.T) {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
 		context := e.NewContext(req, res)
 		context.SetPath("/todo/tasks/:id")
-		// context.SetParamNames("id")
-		// context.SetParamValues("1")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
 		log.Info(context.Path())
 		ProkController := NewRepo(&MockFailProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(ProkController.Put())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetRespFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in database proces", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
 	t.Run("success to update project", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{
 			"name": "anonim123",
 		})
@@ -273,22 +372,103 @@ func TestPut(t *testing.T) {
 		ProkController := NewRepo(&MockProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(ProkController.Put())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetRespFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		assert.Equal(t, 201, response.Code)
+		assert.Equal(t, 200, response.Code)
 		assert.Equal(t, "success to update project", response.Message)
 	})
 }
 
+func TestPatch(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	patch := func(body string, proLib _proLib.Project) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", validation.MIMEMergePatch)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects/:id")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+		ProController := NewRepo(proLib)
+		if err := middlewares.JwtMiddleware()(ProController.Patch())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		return res
+	}
+
+	t.Run("success to patch project", func(t *testing.T) {
+		for body, name := range map[string]string{`{}`: "Proanonim", `{"name":"anonim123"}`: "anonim123"} {
+			res := patch(body, &MockPatchProLib{})
+			response := GetRespFormat{}
+
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, 200, response.Code)
+			assert.Equal(t, "success to update project", response.Message)
+			assert.Equal(t, name, response.Data["name"])
+		}
+	})
+
+	t.Run("validation failed", func(t *testing.T) {
+		res := patch(`{"name":null}`, &MockPatchProLib{})
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{{Field: "name", Code: "required", Message: "name is required"}}, response.Data)
+	})
+
+	t.Run("error input project", func(t *testing.T) {
+		res := patch(`"anonim123"`, &MockPatchProLib{})
+		response := GetRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 400, response.Code)
+		assert.Equal(t, "error in input project", response.Message)
+	})
+
+	t.Run("error in database proses", func(t *testing.T) {
+		res := patch(`{"name":"anonim123"}`, &MockFailProLib{})
+		response := GetRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
+	})
+}
+
 func TestDelete(t *testing.T) {
 	var jwtToken string
 	t.Run("success login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -307,41 +487,45 @@ func TestDelete(t *testing.T) {
 		assert.NotNil(t, response.Data["token"])
 	})
 
-	t.Run("error in database process", func(t *testing.T) {
+	t.Run("error in server", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		req := httptest.NewRequest(http.MethodDelete, "/", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
 		context := e.NewContext(req, res)
-		context.SetPath("/todo/tasks/1")
+		context.SetPath("/todo/tasks/:id")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
 		taskController := NewRepo(&MockFailProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(taskController.Delete())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetRespFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in database process", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
 	t.Run("success to delete project", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		req := httptest.NewRequest(http.MethodDelete, "/", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
 		context := e.NewContext(req, res)
-		context.SetPath("/todo/tasks/1")
+		context.SetPath("/todo/tasks/:id")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
 
 		taskController := NewRepo(&MockProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(taskController.Delete())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetRespFormat{}
 
@@ -351,20 +535,263 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestWorkflow(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	t.Run("error in server", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects/:id/workflow")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		proController := NewRepo(&MockFailProLib{})
+		if err := middlewares.JwtMiddleware()(proController.GetWorkflow())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
+	})
+
+	t.Run("success to get workflow", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects/:id/workflow")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		proController := NewRepo(&MockProLib{})
+		if err := middlewares.JwtMiddleware()(proController.GetWorkflow())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "success to get workflow", response.Message)
+		assert.Equal(t, 5, len(response.Data["statuses"].([]interface{})))
+	})
+
+	t.Run("error in input workflow", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"statuses": []map[string]interface{}{{"name": "todo"}, {"name": "todo", "done": true}},
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects/:id/workflow")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		proController := NewRepo(&MockProLib{})
+		if err := middlewares.JwtMiddleware()(proController.PutWorkflow())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, "invalid status name", response.Message)
+	})
+
+	workflowBody := map[string]interface{}{
+		"statuses": []map[string]interface{}{
+			{"name": "open"},
+			{"name": "doing"},
+			{"name": "closed", "done": true},
+		},
+		"transitions": []map[string]interface{}{
+			{"from": "open", "to": "doing"},
+			{"from": "doing", "to": "closed"},
+			{"from": "closed", "to": "open"},
+		},
+	}
+
+	t.Run("status still used by tasks", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(workflowBody)
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects/:id/workflow")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		proController := NewRepo(&MockFailProLib{})
+		if err := middlewares.JwtMiddleware()(proController.PutWorkflow())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 409, response.Code)
+		assert.Equal(t, "status still used by tasks", response.Message)
+	})
+
+	t.Run("success to update workflow", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(workflowBody)
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/projects/:id/workflow")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		proController := NewRepo(&MockProLib{})
+		if err := middlewares.JwtMiddleware()(proController.PutWorkflow())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "success to update workflow", response.Message)
+		assert.Equal(t, 3, len(response.Data["transitions"].([]interface{})))
+	})
+}
+
+func TestMembers(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _proLib.Project
+		handler func(pc *ProController) echo.HandlerFunc
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"success to get members", &MockProLib{}, (*ProController).GetMembers, nil, 200, "success to get members"},
+		{"forbidden get members", &MockFailProLib{}, (*ProController).GetMembers, nil, 403, "forbidden access"},
+		{"error in input member", &MockProLib{}, (*ProController).AddMember, map[string]interface{}{"user_id": 2, "role": "admin"}, 422, "validation failed"},
+		{"success to add member", &MockProLib{}, (*ProController).AddMember, map[string]interface{}{"user_id": 2, "role": "editor"}, 201, "success to add member"},
+		{"member already exists", &MockFailProLib{}, (*ProController).AddMember, map[string]interface{}{"user_id": 2, "role": "editor"}, 409, "user is already a member"},
+		{"success to update member", &MockProLib{}, (*ProController).PutMember, map[string]interface{}{"role": "viewer"}, 200, "success to update member"},
+		{"last owner demoted", &MockFailProLib{}, (*ProController).PutMember, map[string]interface{}{"role": "viewer"}, 409, "project needs at least one owner"},
+		{"success to remove member", &MockProLib{}, (*ProController).DeleteMember, nil, 200, "success to remove member"},
+		{"member not found", &MockFailProLib{}, (*ProController).DeleteMember, nil, 404, "member not found"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/projects/:id/members/:user_id")
+			context.SetParamNames("id", "user_id")
+			context.SetParamValues("1", "2")
+
+			proController := NewRepo(tc.repo)
+			if err := middlewares.JwtMiddleware()(tc.handler(proController))(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetRespFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+}
+
 type MockAuthLib struct{}
 
 func (m *MockAuthLib) Login(UserLogin reqU.Userlogin) (user.User, error) {
 	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
 }
 
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
+
 type MockProLib struct{}
 
 func (m *MockProLib) Create(user_id int, newPro proMod.Project) (proMod.Project, error) {
 	return proMod.Project{User_ID: uint(user_id), Name: newPro.Name}, nil
 }
 
-func (m *MockProLib) GetAll(user_id int) ([]response.ProResponse, error) {
-	return []response.ProResponse{}, nil
+func (m *MockProLib) GetAll(user_id int, filter request.ProFilter, page base.Page) ([]response.ProResponse, base.Meta, error) {
+	if page.Cursor == "stale" {
+		return nil, base.Meta{}, paginate.ErrInvalidCursor
+	}
+	return []response.ProResponse{{Id: 1}}, base.Meta{Total: 1, Limit: page.Limit, NextCursor: "abc"}, nil
 }
 
 func (m *MockProLib) UpdateById(id int, user_id int, upPro request.ProRequest) (proMod.Project, error) {
@@ -380,14 +807,47 @@ func (m *MockProLib) GetById(id int, user_id int) (proMod.Project, error) {
 	return proMod.Project{}, nil
 }
 
+func (m *MockProLib) GetWorkflow(id int, user_id int) (workflow.Workflow, error) {
+	return workflow.Default(), nil
+}
+
+func (m *MockProLib) UpdateWorkflow(id int, user_id int, wf workflow.Workflow) (workflow.Workflow, error) {
+	return wf, nil
+}
+
+func (m *MockProLib) GetMembers(id int, user_id int) ([]response.MemberResponse, error) {
+	return []response.MemberResponse{}, nil
+}
+
+func (m *MockProLib) AddMember(id int, user_id int, newMember proMod.Member) (proMod.Member, error) {
+	return newMember, nil
+}
+
+func (m *MockProLib) UpdateMember(id int, user_id int, member_id int, role string) (proMod.Member, error) {
+	return proMod.Member{User_ID: uint(member_id), Role: role}, nil
+}
+
+func (m *MockProLib) RemoveMember(id int, user_id int, member_id int) error {
+	return nil
+}
+
+// MockPatchProLib has a project to patch
+type MockPatchProLib struct {
+	MockProLib
+}
+
+func (m *MockPatchProLib) GetById(id int, user_id int) (proMod.Project, error) {
+	return proMod.Project{Model: gorm.Model{ID: uint(id)}, User_ID: uint(user_id), Name: "Proanonim"}, nil
+}
+
 type MockFailProLib struct{}
 
 func (m *MockFailProLib) Create(user_id int, newPro proMod.Project) (proMod.Project, error) {
 	return proMod.Project{}, errors.New("error in call database")
 }
 
-func (m *MockFailProLib) GetAll(user_id int) ([]response.ProResponse, error) {
-	return []response.ProResponse{}, errors.New("error in call database")
+func (m *MockFailProLib) GetAll(user_id int, filter request.ProFilter, page base.Page) ([]response.ProResponse, base.Meta, error) {
+	return nil, base.Meta{}, errors.New("error in call database")
 }
 
 func (m *MockFailProLib) UpdateById(id int, user_id int, upPro request.ProRequest) (proMod.Project, error) {
@@ -402,3 +862,27 @@ func (m *MockFailProLib) DeleteById(id int, user_id int) (gorm.DeletedAt, error)
 func (m *MockFailProLib) GetById(id int, user_id int) (proMod.Project, error) {
 	return proMod.Project{}, nil
 }
+
+func (m *MockFailProLib) GetWorkflow(id int, user_id int) (workflow.Workflow, error) {
+	return workflow.Workflow{}, errors.New("error in database process")
+}
+
+func (m *MockFailProLib) UpdateWorkflow(id int, user_id int, wf workflow.Workflow) (workflow.Workflow, error) {
+	return workflow.Workflow{}, _proLib.ErrStatusInUse
+}
+
+func (m *MockFailProLib) GetMembers(id int, user_id int) ([]response.MemberResponse, error) {
+	return nil, _proLib.ErrForbidden
+}
+
+func (m *MockFailProLib) AddMember(id int, user_id int, newMember proMod.Member) (proMod.Member, error) {
+	return proMod.Member{}, _proLib.ErrMemberExists
+}
+
+func (m *MockFailProLib) UpdateMember(id int, user_id int, member_id int, role string) (proMod.Member, error) {
+	return proMod.Member{}, _proLib.ErrLastOwner
+}
+
+func (m *MockFailProLib) RemoveMember(id int, user_id int, member_id int) error {
+	return _proLib.ErrMemberNotFound
+}
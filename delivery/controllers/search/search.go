@@ -0,0 +1,65 @@
+package search
+
+import (
+	"net/http"
+	"part3/delivery/middlewares"
+	"part3/lib/database/search"
+	"part3/models/base"
+	_search "part3/models/search"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SearchController struct {
+	repo search.Search
+}
+
+func New(repository search.Search) *SearchController {
+	return &SearchController{
+		repo: repository,
+	}
+}
+
+// searchTypes reads type=task,comment, no type means every kind
+func searchTypes(c echo.Context) ([]string, bool) {
+	types := []string{}
+	if value := c.QueryParam("type"); value != "" {
+		for _, kind := range strings.Split(value, ",") {
+			kind = strings.TrimSpace(kind)
+			known := false
+			for _, t := range _search.Types {
+				known = known || t == kind
+			}
+			if !known {
+				return nil, false
+			}
+			types = append(types, kind)
+		}
+	}
+	return types, true
+}
+
+// Search looks for q in the projects, tasks and comments the user can see
+func (sc *SearchController) Search() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		types, ok := searchTypes(c)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input type", nil))
+		}
+		page, err := base.ParsePage(c.QueryParams())
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in input page", nil))
+		}
+
+		res, meta, err := sc.repo.Search(user_id, c.QueryParam("q"), types, page)
+		if err != nil {
+			return err
+		}
+
+		meta.SetLinks(c.Request().URL)
+		return c.JSON(http.StatusOK, base.SuccessPage(http.StatusOK, "success to search", res, meta))
+	}
+}
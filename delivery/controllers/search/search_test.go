@@ -0,0 +1,131 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"part3/delivery/controllers/auth"
+	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/paginate"
+	_searchLib "part3/lib/database/search"
+	"part3/models/base"
+	_search "part3/models/search"
+	"part3/models/search/response"
+	"part3/models/session"
+	"part3/models/user"
+	reqU "part3/models/user/request"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSearch(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _searchLib.Search
+		query   string
+		code    int
+		message string
+	}{
+		{"success to search", &MockSearchLib{}, "?q=design", 200, "success to search"},
+		{"success to search comments", &MockSearchLib{}, "?q=design&type=comment,task", 200, "success to search"},
+		{"error in input type", &MockSearchLib{}, "?q=design&type=user", 400, "error in input type"},
+		{"error in input page", &MockSearchLib{}, "?q=design&limit=0", 400, "error in input page"},
+		{"error in input sort", &MockFailSearchLib{}, "?q=design&sort=name", 422, "invalid sort field"},
+		{"error in input query", &MockFailSearchLib{}, "?q=%2B%2B", 422, "search query has no words"},
+		{"error in server", &MockFailSearchLib{}, "?q=design", 500, "error in server"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			req := httptest.NewRequest(http.MethodGet, "/search"+tc.query, nil)
+			res := httptest.NewRecorder()
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/search")
+
+			searchController := New(tc.repo)
+			if err := middlewares.JwtMiddleware()(searchController.Search())(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetRespFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+}
+
+type MockSearchLib struct{}
+
+func (m *MockSearchLib) Search(user_id int, q string, types []string, page base.Page) ([]response.ResultResponse, base.Meta, error) {
+	return []response.ResultResponse{{Type: _search.TypeTask, ID: 1, Project_id: 1, Title: "Design", Snippet: "<mark>Design</mark>", Score: 6.5}}, base.Meta{Total: 1, Limit: page.Limit}, nil
+}
+
+type MockFailSearchLib struct{}
+
+func (m *MockFailSearchLib) Search(user_id int, q string, types []string, page base.Page) ([]response.ResultResponse, base.Meta, error) {
+	switch {
+	case len(page.Sort) > 0:
+		return nil, base.Meta{}, paginate.ErrInvalidSort
+	case q == "++":
+		return nil, base.Meta{}, _searchLib.ErrInvalidQuery
+	}
+	return nil, base.Meta{}, errors.New("error in database process")
+}
+
+type MockAuthLib struct{}
+
+func (m *MockAuthLib) Login(UserLogin reqU.Userlogin) (user.User, error) {
+	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
+}
+
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
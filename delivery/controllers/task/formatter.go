@@ -1,7 +1,23 @@
 package task
 
+import "part3/models/base"
+
 type GetTaskResponFormat struct {
 	Code    int                    `json:"code"`
 	Message string                 `json:"message"`
 	Data    map[string]interface{} `json:"data"`
-}
\ No newline at end of file
+}
+
+type GetTaskListFormat struct {
+	Code    int                      `json:"code"`
+	Message string                   `json:"message"`
+	Data    []map[string]interface{} `json:"data"`
+	Meta    map[string]interface{}   `json:"meta"`
+}
+
+type ValidationRespFormat struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Error   string            `json:"error"`
+	Data    []base.FieldError `json:"data"`
+}
@@ -1,15 +1,21 @@
 package task
 
 import (
+	"fmt"
 	"net/http"
 	"part3/delivery/middlewares"
+	"part3/delivery/validation"
 	"part3/lib/database/project"
 	"part3/lib/database/task"
+	"part3/lib/recurrence"
 	"part3/models/base"
 
 	"part3/models/task/request"
+	wfReq "part3/models/workflow/request"
 
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -31,40 +37,31 @@ func (tc *TaskController) Create() echo.HandlerFunc {
 		user_id := int(middlewares.ExtractTokenId(c))
 		newTask := request.TaskRequest{}
 
-		if err := c.Bind(&newTask); err != nil || newTask.Name == "" {
+		if err := c.Bind(&newTask); err != nil {
 			return c.JSON(http.StatusBadRequest, base.BadRequest(
 				http.StatusBadRequest,
 				"error in input task",
 				nil,
 			))
 		}
+		if err := validation.Check(c, &newTask, taskFields(&newTask)...); err != nil {
+			return err
+		}
 
 		if _, err := tc.proLib.GetById(int(newTask.Project_id), user_id); err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(
-				http.StatusInternalServerError,
-				"error in database process",
-				nil,
-			))
+			return err
 		}
 
 		resC, err := tc.repo.Create(user_id, newTask.ToTask())
 
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(
-				http.StatusInternalServerError,
-				"error in database process",
-				nil,
-			))
+			return err
 		}
 
 		res, err := tc.repo.GetByIdResp(int(resC.ID), user_id)
 
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(
-				http.StatusInternalServerError,
-				"error in database process",
-				nil,
-			))
+			return err
 		}
 
 		return c.JSON(http.StatusCreated, base.Success(
@@ -79,30 +76,198 @@ func (tc *TaskController) GetAll() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		user_id := int(middlewares.ExtractTokenId(c))
 
-		res, err := tc.repo.GetAll(user_id)
+		filter, err := taskFilter(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(
+				http.StatusBadRequest,
+				"error in input filter",
+				nil,
+			))
+		}
 
+		page, err := base.ParsePage(c.QueryParams())
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(
-				http.StatusInternalServerError,
-				"error in database process",
+			return c.JSON(http.StatusBadRequest, base.BadRequest(
+				http.StatusBadRequest,
+				"error in input page",
 				nil,
 			))
 		}
 
-		return c.JSON(http.StatusOK, base.Success(
+		res, meta, err := tc.repo.GetAll(user_id, filter, page)
+
+		if err != nil {
+			return err
+		}
+
+		meta.SetLinks(c.Request().URL)
+		return c.JSON(http.StatusOK, base.SuccessPage(
 			http.StatusOK,
 			"success to get all task",
 			res,
+			meta,
+		))
+	}
+}
+
+func (tc *TaskController) GetAgenda() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		loc, err := time.LoadLocation(c.QueryParam("tz"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(
+				http.StatusBadRequest,
+				"error in input timezone",
+				nil,
+			))
+		}
+
+		days := 7
+		if c.QueryParam("days") != "" {
+			days, err = strconv.Atoi(c.QueryParam("days"))
+			if err != nil || days < 0 || days > 366 {
+				return c.JSON(http.StatusBadRequest, base.BadRequest(
+					http.StatusBadRequest,
+					"error in input days",
+					nil,
+				))
+			}
+		}
+
+		res, err := tc.repo.GetAgenda(user_id, time.Now(), loc, days)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to get agenda",
+			res,
 		))
 	}
 }
 
+// taskFilter reads the listing filters, dates must be RFC3339 with an offset
+func taskFilter(c echo.Context) (request.TaskFilter, error) {
+	filter := request.TaskFilter{
+		Status: c.QueryParam("status"),
+		Name:   c.QueryParam("name"),
+	}
+
+	if value := c.QueryParam("project_id"); value != "" {
+		project_id, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Project_id = project_id
+	}
+	for param, target := range map[string]**int{"priority_min": &filter.PriorityMin, "priority_max": &filter.PriorityMax} {
+		if value := c.QueryParam(param); value != "" {
+			priority, err := strconv.Atoi(value)
+			if err != nil {
+				return filter, err
+			}
+			*target = &priority
+		}
+	}
+	for param, target := range map[string]**time.Time{
+		"created_before": &filter.CreatedBefore,
+		"created_after":  &filter.CreatedAfter,
+		"due_before":     &filter.DueBefore,
+		"due_after":      &filter.DueAfter,
+	} {
+		if value := c.QueryParam(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, err
+			}
+			*target = &parsed
+		}
+	}
+	// parent_id=none lists top level tasks only
+	if value := c.QueryParam("parent_id"); value == "none" {
+		none := uint(0)
+		filter.Parent_id = &none
+	} else if value != "" {
+		parent_id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return filter, err
+		}
+		parent := uint(parent_id)
+		filter.Parent_id = &parent
+	}
+	// assignee=me is the "assigned to me" view, any other value is a user id
+	if value := c.QueryParam("assignee"); value == "me" {
+		filter.Assignee = int(middlewares.ExtractTokenId(c))
+	} else if value != "" {
+		assignee, err := strconv.Atoi(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Assignee = assignee
+	}
+	if value := c.QueryParam("overdue"); value != "" {
+		overdue, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Overdue = overdue
+	}
+	// label=bug,urgent matches by name, label_mode says whether any or all of them are needed
+	for _, name := range strings.Split(c.QueryParam("label"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			filter.Labels = append(filter.Labels, name)
+		}
+	}
+	switch filter.LabelMode = c.QueryParam("label_mode"); filter.LabelMode {
+	case "", request.LabelModeAny, request.LabelModeAll:
+	default:
+		return filter, fmt.Errorf("unknown label mode %s", filter.LabelMode)
+	}
+
+	return filter, nil
+}
+
+// taskFields are the failing fields the tags cannot check, a project is needed
+// on update too as the request replaces the task. The recurrence rule is stored
+// in its canonical form
+func taskFields(taskReq *request.TaskRequest) []base.FieldError {
+	fields := []base.FieldError{}
+	if taskReq.Project_id == 0 {
+		fields = append(fields, base.FieldError{Field: "project_id", Code: "required", Message: "project_id is required"})
+	}
+	if !taskReq.ValidDates() {
+		fields = append(fields, base.FieldError{Field: "due_at", Code: "before_start", Message: "due_at must not be before start_at"})
+	}
+	if taskReq.Timezone != nil {
+		if _, err := time.LoadLocation(*taskReq.Timezone); err != nil || *taskReq.Timezone == "Local" {
+			fields = append(fields, base.FieldError{Field: "timezone", Code: "invalid_timezone", Message: "timezone must be an IANA time zone"})
+		}
+	}
+	if taskReq.Recurrence != nil && *taskReq.Recurrence != "" {
+		rule, err := recurrence.Parse(*taskReq.Recurrence)
+		if err != nil {
+			fields = append(fields, base.FieldError{Field: "recurrence", Code: "invalid_recurrence", Message: "recurrence must be a valid rule"})
+		} else {
+			canonical := rule.String()
+			taskReq.Recurrence = &canonical
+		}
+	}
+	return fields
+}
+
+// Put replaces the task, what the body leaves out is cleared
 func (tc *TaskController) Put() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		id, _ := strconv.Atoi(c.Param("id"))
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
 		user_id := int(middlewares.ExtractTokenId(c))
 		upTask := request.TaskRequest{}
-		if err := c.Bind(&upTask); err != nil || upTask.Name == "" {
+		if err := c.Bind(&upTask); err != nil {
 			return c.JSON(http.StatusBadRequest, base.BadRequest(
 				http.StatusBadRequest,
 				"error in input task",
@@ -110,77 +275,451 @@ func (tc *TaskController) Put() echo.HandlerFunc {
 			))
 		}
 
-		res, err := tc.repo.UpdateById(id, user_id, upTask)
+		return tc.update(c, id, user_id, upTask)
+	}
+}
 
+// Patch changes only the fields of the merge patch, null clears one
+func (tc *TaskController) Patch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+		current, err := tc.repo.GetByIdResp(id, user_id)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(
-				http.StatusInternalServerError,
-				"error in database process",
+			return err
+		}
+		upTask := request.NewTaskRequest(current)
+		if err := validation.BindPatch(c, &upTask); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(
+				http.StatusBadRequest,
+				"error in input task",
 				nil,
 			))
 		}
 
+		return tc.update(c, id, user_id, upTask)
+	}
+}
+
+func (tc *TaskController) update(c echo.Context, id int, user_id int, upTask request.TaskRequest) error {
+	if err := validation.Check(c, &upTask, taskFields(&upTask)...); err != nil {
+		return err
+	}
+
+	res, err := tc.repo.UpdateById(id, user_id, upTask)
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, base.Success(
+		http.StatusOK,
+		"success to update task",
+		res.ToTaskResponse(),
+	))
+}
+
+func (tc *TaskController) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := tc.repo.DeleteById(id, user_id)
+
+		if err != nil {
+			return err
+		}
+
 		return c.JSON(http.StatusOK, base.Success(
 			http.StatusOK,
-			"success to update task",
-			res.ToTaskResponse(),
+			"success to delete task",
+			res,
 		))
 	}
 }
 
-func (tc *TaskController) Delete() echo.HandlerFunc {
+func (tc *TaskController) TaskCompleted() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		id, _ := strconv.Atoi(c.Param("id"))
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
 
+		cascade := false
+		if value := c.QueryParam("cascade"); value != "" {
+			var err error
+			if cascade, err = strconv.ParseBool(value); err != nil {
+				return c.JSON(http.StatusBadRequest, base.BadRequest(
+					http.StatusBadRequest,
+					"error in input cascade",
+					nil,
+				))
+			}
+		}
+
+		if _, err := tc.repo.TaskCompleted(id, user_id, cascade); err != nil {
+			return err
+		}
+
+		res, err := tc.repo.GetByIdResp(id, user_id)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to complete task",
+			res,
+		))
+	}
+}
+
+func (tc *TaskController) TaskReopened() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
 		user_id := int(middlewares.ExtractTokenId(c))
 
-		res, err := tc.repo.DeleteById(id, user_id)
+		if _, err := tc.repo.TaskReopened(id, user_id); err != nil {
+			return err
+		}
+
+		res, err := tc.repo.GetByIdResp(id, user_id)
 
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(
-				http.StatusInternalServerError,
-				"error in database process",
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to reopen task",
+			res,
+		))
+	}
+}
+
+func (tc *TaskController) Transition() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+		transition := wfReq.TransitionTask{}
+
+		if err := c.Bind(&transition); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(
+				http.StatusBadRequest,
+				"error in input status",
 				nil,
 			))
 		}
+		if err := c.Validate(&transition); err != nil {
+			return err
+		}
+
+		if _, err := tc.repo.Transition(id, user_id, transition.Status); err != nil {
+			return err
+		}
+
+		res, err := tc.repo.GetByIdResp(id, user_id)
+
+		if err != nil {
+			return err
+		}
 
 		return c.JSON(http.StatusOK, base.Success(
 			http.StatusOK,
-			"success to delete task",
+			"success to transition task",
 			res,
 		))
 	}
 }
 
-func (tc *TaskController) TaskCompleted() echo.HandlerFunc {
+// moveFields are the failing fields of a move the tags cannot check
+func moveFields(move *request.MoveRequest) []base.FieldError {
+	if move.Before_id != 0 && move.After_id != 0 {
+		return []base.FieldError{{Field: "after_id", Code: "excluded_with", Message: "after_id cannot be given with before_id"}}
+	}
+	return nil
+}
+
+// Move changes the column and the place of the task on its project board,
+// next to the task given by before_id or after_id
+func (tc *TaskController) Move() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		id, _ := strconv.Atoi(c.Param("id"))
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
 		user_id := int(middlewares.ExtractTokenId(c))
-		statusTask := request.TaskRequest{}
+		move := request.MoveRequest{}
 
-		if err := c.Bind(&statusTask); err != nil {
+		if err := c.Bind(&move); err != nil {
 			return c.JSON(http.StatusBadRequest, base.BadRequest(
 				http.StatusBadRequest,
-				"error in update status",
+				"error in input move",
 				nil,
 			))
 		}
+		if err := validation.Check(c, &move, moveFields(&move)...); err != nil {
+			return err
+		}
+
+		if _, err := tc.repo.Move(id, user_id, move); err != nil {
+			return err
+		}
 
-		res, err := tc.repo.TaskCompleted(id, user_id, statusTask.Status)
+		res, err := tc.repo.GetByIdResp(id, user_id)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to move task",
+			res,
+		))
+	}
+}
+
+func (tc *TaskController) GetAssignees() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := tc.repo.GetAssignees(id, user_id)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to get assignees",
+			res,
+		))
+	}
+}
 
+// AddAssignees only accepts members of the task's project
+func (tc *TaskController) AddAssignees() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(
-				http.StatusInternalServerError,
-				"error in database process",
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+		assignees := request.AssigneeRequest{}
+
+		if err := c.Bind(&assignees); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(
+				http.StatusBadRequest,
+				"error in input assignee",
 				nil,
 			))
 		}
+		if err := c.Validate(&assignees); err != nil {
+			return err
+		}
+
+		found, err := tc.repo.GetByIdResp(id, user_id)
+		if err != nil {
+			return base.NotFoundAs(err, task.ErrAssigneeNotFound)
+		}
+		members, err := tc.proLib.GetMembers(found.Project_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		isMember := map[uint]bool{}
+		for _, member := range members {
+			isMember[member.User_id] = true
+		}
+		for _, assignee := range assignees.User_ids {
+			if !isMember[assignee] {
+				return base.FieldErrors{{Field: "user_ids", Code: "not_member", Message: "user_ids must be members of the project"}}
+			}
+		}
+
+		res, err := tc.repo.AddAssignees(id, user_id, assignees.User_ids)
+
+		if err != nil {
+			return err
+		}
 
 		return c.JSON(http.StatusOK, base.Success(
 			http.StatusOK,
-			"success to update status",
+			"success to add assignees",
 			res,
 		))
 	}
+}
+
+func (tc *TaskController) DeleteAssignee() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		assignee_id, err := validation.ParamID(c, "user_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
 
+		if err := tc.repo.RemoveAssignee(id, user_id, assignee_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to remove assignee",
+			nil,
+		))
+	}
+}
+
+func (tc *TaskController) GetChecklist() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		res, err := tc.repo.GetChecklist(id, user_id)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to get checklist",
+			res,
+		))
+	}
+}
+
+// checklistFields needs a name to create an item, an update needs anything to change
+func checklistFields(itemReq *request.ChecklistRequest, create bool) []base.FieldError {
+	if create && itemReq.Name == "" {
+		return []base.FieldError{{Field: "name", Code: "required", Message: "name is required"}}
+	}
+	if !create && itemReq.Name == "" && itemReq.Done == nil && itemReq.Position == nil {
+		return []base.FieldError{{Field: "name", Code: "required", Message: "name, done or position is required"}}
+	}
+	return nil
+}
+
+func (tc *TaskController) AddChecklistItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+		newItem := request.ChecklistRequest{}
+
+		if err := c.Bind(&newItem); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(
+				http.StatusBadRequest,
+				"error in input checklist item",
+				nil,
+			))
+		}
+		if err := validation.Check(c, &newItem, checklistFields(&newItem, true)...); err != nil {
+			return err
+		}
+
+		res, err := tc.repo.AddChecklistItem(id, user_id, newItem.ToChecklistItem())
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, base.Success(
+			http.StatusCreated,
+			"success to add checklist item",
+			res.ToChecklistItemResponse(),
+		))
+	}
+}
+
+func (tc *TaskController) PutChecklistItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		item_id, err := validation.ParamID(c, "item_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+		upItem := request.ChecklistRequest{}
+
+		if err := c.Bind(&upItem); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(
+				http.StatusBadRequest,
+				"error in input checklist item",
+				nil,
+			))
+		}
+		if err := validation.Check(c, &upItem, checklistFields(&upItem, false)...); err != nil {
+			return err
+		}
+
+		res, err := tc.repo.UpdateChecklistItem(id, user_id, item_id, upItem)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to update checklist item",
+			res.ToChecklistItemResponse(),
+		))
+	}
+}
+
+func (tc *TaskController) DeleteChecklistItem() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		item_id, err := validation.ParamID(c, "item_id")
+		if err != nil {
+			return err
+		}
+		user_id := int(middlewares.ExtractTokenId(c))
+
+		if err := tc.repo.DeleteChecklistItem(id, user_id, item_id); err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(
+			http.StatusOK,
+			"success to remove checklist item",
+			nil,
+		))
+	}
 }
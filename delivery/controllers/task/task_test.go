@@ -5,20 +5,28 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"net/http/httptest"
 	"part3/delivery/controllers/auth"
 	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	"part3/lib/database/paginate"
+	_proLib "part3/lib/database/project"
+	_taskLib "part3/lib/database/task"
+	"part3/models/base"
 	proMod "part3/models/project"
 	proReq "part3/models/project/request"
 	proResp "part3/models/project/response"
+	"part3/models/session"
 	"part3/models/task"
 	"part3/models/task/request"
 	"part3/models/task/response"
 	"part3/models/user"
 	reqU "part3/models/user/request"
+	"part3/models/workflow"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -29,6 +37,7 @@ func TestCreate(t *testing.T) {
 	var jwtToken string
 	t.Run("success login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -47,8 +56,9 @@ func TestCreate(t *testing.T) {
 		assert.NotNil(t, response.Data["token"])
 	})
 
-	t.Run("error in input task", func(t *testing.T) {
+	t.Run("validation failed", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{})
 		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
 		res := httptest.NewRecorder()
@@ -60,18 +70,52 @@ func TestCreate(t *testing.T) {
 		taskController := New(&MockFailTaskLib{}, &MockFailProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
-		response := GetTaskResponFormat{}
+		response := ValidationRespFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		assert.Equal(t, 400, response.Code)
-		assert.Equal(t, "error in input task", response.Message)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, "validation failed", response.Message)
+		assert.Equal(t, "validation_failed", response.Error)
+		assert.Equal(t, []base.FieldError{
+			{Field: "name", Code: "required", Message: "name is required"},
+			{Field: "project_id", Code: "required", Message: "project_id is required"},
+		}, response.Data)
+	})
+
+	t.Run("validation failed name and priority", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"name":       strings.Repeat("a", 101),
+			"priority":   -1,
+			"project_id": 1,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{
+			{Field: "name", Code: "too_long", Message: "name must be at most 100 characters"},
+			{Field: "priority", Code: "too_small", Message: "priority must be at least 0"},
+		}, response.Data)
 	})
 
-	t.Run("error in database process", func(t *testing.T) {
+	t.Run("error in server", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{
 			"name":       "anonim",
 			"priority":   1,
@@ -87,18 +131,18 @@ func TestCreate(t *testing.T) {
 		taskController := New(&MockFailTaskLib{}, &MockFailProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetTaskResponFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in database process", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
-	t.Run("error in database process", func(t *testing.T) {
+	t.Run("error in server", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{
 			"name":       "anonim",
 			"priority":   1,
@@ -114,18 +158,18 @@ func TestCreate(t *testing.T) {
 		taskController := New(&MockFailTaskLib{}, &MockProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetTaskResponFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in database process", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
-	t.Run("error in database process", func(t *testing.T) {
+	t.Run("error in server", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{
 			"name":       "anonim",
 			"priority":   1,
@@ -141,18 +185,100 @@ func TestCreate(t *testing.T) {
 		taskController := New(&MockFailGetByIdRespTaskLib{}, &MockProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetTaskResponFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in database process", response.Message)
+		assert.Equal(t, "error in server", response.Message)
+	})
+
+	t.Run("validation failed dates", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"name":       "anonim",
+			"priority":   1,
+			"project_id": 1,
+			"start_at":   "2022-02-10T09:00:00+07:00",
+			"due_at":     "2022-02-09T09:00:00+07:00",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks")
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{{Field: "due_at", Code: "before_start", Message: "due_at must not be before start_at"}}, response.Data)
+	})
+
+	t.Run("validation failed recurrence", func(t *testing.T) {
+		for field, body := range map[string]map[string]interface{}{
+			"recurrence": {"name": "anonim", "priority": 1, "project_id": 1, "recurrence": "FREQ=HOURLY"},
+			"timezone":   {"name": "anonim", "priority": 1, "project_id": 1, "recurrence": "FREQ=DAILY", "timezone": "Mars/Olympus"},
+		} {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/todo/tasks")
+			taskController := New(&MockTaskLib{}, &MockProLib{})
+			if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := ValidationRespFormat{}
+
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, 422, response.Code)
+			assert.Equal(t, 1, len(response.Data))
+			assert.Equal(t, field, response.Data[0].Field)
+		}
+	})
+
+	t.Run("success to create recurring task", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"name":       "anonim",
+			"priority":   1,
+			"project_id": 1,
+			"due_at":     "2022-02-10T09:00:00+07:00",
+			"recurrence": "freq=weekly;byday=fr,mo",
+			"timezone":   "Asia/Jakarta",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks")
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 201, response.Code)
+		assert.Equal(t, "success to create task", response.Message)
 	})
 
 	t.Run("success to create task", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{
 			"name":       "anonim",
 			"priority":   1,
@@ -167,8 +293,7 @@ func TestCreate(t *testing.T) {
 		taskController := New(&MockTaskLib{}, &MockProLib{})
 		// taskController.Create()(context)
 		if err := middlewares.JwtMiddleware()(taskController.Create())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetTaskResponFormat{}
 
@@ -182,6 +307,7 @@ func TestGetAll(t *testing.T) {
 	var jwtToken string
 	t.Run("success login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -200,8 +326,9 @@ func TestGetAll(t *testing.T) {
 		assert.NotNil(t, response.Data["token"])
 	})
 
-	t.Run("error in database process", func(t *testing.T) {
+	t.Run("error in server", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
@@ -213,17 +340,17 @@ func TestGetAll(t *testing.T) {
 		taskController := New(&MockFailTaskLib{}, &MockProLib{})
 
 		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetTaskResponFormat{}
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in database process", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
 	t.Run("success to get all task", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
@@ -235,122 +362,155 @@ func TestGetAll(t *testing.T) {
 		taskController := New(&MockTaskLib{}, &MockProLib{})
 
 		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
-			log.Fatal(err)
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetTaskResponFormat{}
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 200, response.Code)
 		assert.Equal(t, "success to get all task", response.Message)
 	})
-}
 
-func TestPut(t *testing.T) {
-	var jwtToken string
-	t.Run("success login", func(t *testing.T) {
+	t.Run("error in input filter", func(t *testing.T) {
 		e := echo.New()
-		reqBody, _ := json.Marshal(map[string]string{
-			"email":    "anonim@123",
-			"password": "anonim123",
-		})
-		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		e.Validator = validation.New()
+
+		req := httptest.NewRequest(http.MethodGet, "/?due_before=tomorrow&priority_min=high&assignee=someone&parent_id=first", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
 		context := e.NewContext(req, res)
-		context.SetPath("/login")
-		authController := auth.New(&MockAuthLib{})
-		authController.Login()(context)
-		response := auth.LoginRespFormat{}
+		context.SetPath("/todo/tasks")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+
+		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		jwtToken = response.Data["token"].(string)
-		assert.Equal(t, 200, response.Code)
-		assert.NotNil(t, response.Data["token"])
+		assert.Equal(t, 400, response.Code)
+		assert.Equal(t, "error in input filter", response.Message)
 	})
 
-	t.Run("error in input task", func(t *testing.T) {
+	t.Run("error in input label mode", func(t *testing.T) {
 		e := echo.New()
-		reqBody, _ := json.Marshal(map[string]interface{}{})
-		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		e.Validator = validation.New()
+
+		req := httptest.NewRequest(http.MethodGet, "/?label=bug&label_mode=some", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
-		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
 		context := e.NewContext(req, res)
-		context.SetPath("/todo/tasks/1")
+		context.SetPath("/todo/tasks")
 
-		taskController := New(&MockFailTaskLib{}, &MockFailProLib{})
-		// taskController.Create()(context)
-		if err := middlewares.JwtMiddleware()(taskController.Put())(context); err != nil {
-			log.Fatal(err)
-			return
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+
+		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetTaskResponFormat{}
-
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 400, response.Code)
-		assert.Equal(t, "error in input task", response.Message)
+		assert.Equal(t, "error in input filter", response.Message)
+	})
+
+	t.Run("error in input page", func(t *testing.T) {
+		for _, query := range []string{"/?limit=0", "/?limit=abc", "/?offset=-1"} {
+			e := echo.New()
+			e.Validator = validation.New()
+
+			req := httptest.NewRequest(http.MethodGet, query, bytes.NewBuffer(nil))
+			res := httptest.NewRecorder()
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/todo/tasks")
+
+			taskController := New(&MockTaskLib{}, &MockProLib{})
+
+			if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetTaskResponFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, 400, response.Code)
+			assert.Equal(t, "error in input page", response.Message)
+		}
+	})
+
+	t.Run("invalid sort field", func(t *testing.T) {
+		for _, query := range []string{"/?sort=-password"} {
+			e := echo.New()
+			e.Validator = validation.New()
+
+			req := httptest.NewRequest(http.MethodGet, query, bytes.NewBuffer(nil))
+			res := httptest.NewRecorder()
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/todo/tasks")
+
+			taskController := New(&MockTaskLib{}, &MockProLib{})
+
+			if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetTaskResponFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, 422, response.Code)
+			assert.Equal(t, "invalid sort field", response.Message)
+		}
 	})
 
-	t.Run("error in database process", func(t *testing.T) {
+	t.Run("success to get all task with page links", func(t *testing.T) {
 		e := echo.New()
-		reqBody, _ := json.Marshal(map[string]interface{}{
-			"name":       "anonim",
-			"priority":   1,
-			"project_id": 5,
-		})
-		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		e.Validator = validation.New()
+
+		req := httptest.NewRequest(http.MethodGet, "/todo/tasks?status=todo&limit=20&offset=20", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
-		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
 		context := e.NewContext(req, res)
-		context.SetPath("/todo/tasks/1")
-		taskController := New(&MockFailTaskLib{}, &MockProLib{})
-		// taskController.Create()(context)
-		if err := middlewares.JwtMiddleware()(taskController.Put())(context); err != nil {
-			log.Fatal(err)
-			return
-		}
-		response := GetTaskResponFormat{}
+		context.SetPath("/todo/tasks")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
 
+		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskListFormat{}
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in database process", response.Message)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, 1, len(response.Data))
+		assert.Equal(t, 45, int(response.Meta["total"].(float64)))
+		assert.Equal(t, "/todo/tasks?limit=20&offset=40&status=todo", response.Meta["next"])
+		assert.Equal(t, "/todo/tasks?limit=20&offset=0&status=todo", response.Meta["prev"])
 	})
 
-	t.Run("success to update task", func(t *testing.T) {
+	t.Run("success to get all task with filter", func(t *testing.T) {
 		e := echo.New()
-		reqBody, _ := json.Marshal(map[string]interface{}{
-			"name":       "anonim",
-			"priority":   1,
-			"project_id": 5,
-		})
-		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		e.Validator = validation.New()
+
+		req := httptest.NewRequest(http.MethodGet, "/?due_after=2022-01-01T00:00:00%2B07:00&overdue=true&project_id=1&priority_min=1&priority_max=3&name=report&assignee=me&parent_id=none&label=bug,%20urgent,&label_mode=all", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
 		context := e.NewContext(req, res)
-		context.SetPath("/todo/tasks/:id")
-		context.SetParamNames("id")
-		context.SetParamValues("1")
+		context.SetPath("/todo/tasks")
 
 		taskController := New(&MockTaskLib{}, &MockProLib{})
-		// taskController.Create()(context)
-		if err := middlewares.JwtMiddleware()(taskController.Put())(context); err != nil {
-			log.Fatal(err)
-			return
-		}
 
+		if err := middlewares.JwtMiddleware()(taskController.GetAll())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
 		response := GetTaskResponFormat{}
-
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 200, response.Code)
-		assert.Equal(t, "success to update task", response.Message)
+		assert.Equal(t, "success to get all task", response.Message)
 	})
 }
 
-func TestDelete(t *testing.T) {
+func TestGetAgenda(t *testing.T) {
 	var jwtToken string
 	t.Run("success login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -369,63 +529,69 @@ func TestDelete(t *testing.T) {
 		assert.NotNil(t, response.Data["token"])
 	})
 
-	t.Run("error in database process", func(t *testing.T) {
+	t.Run("error in input timezone", func(t *testing.T) {
 		e := echo.New()
-		reqBody, _ := json.Marshal(map[string]interface{}{
-			"name_task": "anonim",
-			"priority":  1,
-		})
-		req := httptest.NewRequest(http.MethodDelete, "/", bytes.NewBuffer(reqBody))
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodGet, "/?tz=Mars/Olympus", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
-		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
 		context := e.NewContext(req, res)
-		context.SetPath("/todo/tasks/1")
-		taskController := New(&MockFailTaskLib{}, &MockProLib{})
-		// taskController.Create()(context)
-		if err := middlewares.JwtMiddleware()(taskController.Delete())(context); err != nil {
-			log.Fatal(err)
-			return
+		context.SetPath("/todo/tasks/agenda")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.GetAgenda())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetTaskResponFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 400, response.Code)
+		assert.Equal(t, "error in input timezone", response.Message)
+	})
+
+	t.Run("error in server", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodGet, "/?tz=Asia/Jakarta", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/agenda")
 
+		taskController := New(&MockFailTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.GetAgenda())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in database process", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
-	t.Run("success to delete task", func(t *testing.T) {
+	t.Run("success to get agenda", func(t *testing.T) {
 		e := echo.New()
-		reqBody, _ := json.Marshal(map[string]interface{}{
-			"name_task": "anonim123",
-			"priority":  1,
-		})
-		req := httptest.NewRequest(http.MethodDelete, "/", bytes.NewBuffer(reqBody))
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodGet, "/?tz=Asia/Jakarta&days=3", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
-		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
 		context := e.NewContext(req, res)
-		context.SetPath("/todo/tasks/1")
+		context.SetPath("/todo/tasks/agenda")
 
 		taskController := New(&MockTaskLib{}, &MockProLib{})
-		// taskController.Create()(context)
-		if err := middlewares.JwtMiddleware()(taskController.Delete())(context); err != nil {
-			log.Fatal(err)
-			return
+		if err := middlewares.JwtMiddleware()(taskController.GetAgenda())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetTaskResponFormat{}
-
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 200, response.Code)
-		assert.Equal(t, "success to delete task", response.Message)
+		assert.Equal(t, "success to get agenda", response.Message)
 	})
 }
 
-func TestUpdateStatus(t *testing.T) {
+func TestPut(t *testing.T) {
 	var jwtToken string
-
 	t.Run("success login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -444,59 +610,92 @@ func TestUpdateStatus(t *testing.T) {
 		assert.NotNil(t, response.Data["token"])
 	})
 
-	t.Run("error in update status", func(t *testing.T) {
+	t.Run("validation failed", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{})
 		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
 		res := httptest.NewRecorder()
-
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
-
 		context := e.NewContext(req, res)
-		context.SetPath("/todo/tasks/1")
+		context.SetPath("/todo/tasks/:id")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
 
 		taskController := New(&MockFailTaskLib{}, &MockFailProLib{})
-		if err := middlewares.JwtMiddleware()(taskController.UpdateStatus())(context); err != nil {
-			log.Fatal(err)
-			return
+		if err := middlewares.JwtMiddleware()(taskController.Put())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
 		}
-		response := GetTaskResponFormat{}
+		response := ValidationRespFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		assert.Equal(t, 400, response.Code)
-		assert.Equal(t, "error in update status", response.Message)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{
+			{Field: "name", Code: "required", Message: "name is required"},
+			{Field: "project_id", Code: "required", Message: "project_id is required"},
+		}, response.Data)
 	})
 
-	t.Run("error in database process", func(t *testing.T) {
+	t.Run("validation failed id", func(t *testing.T) {
 		e := echo.New()
-		reqBody, _ := json.Marshal(map[string]interface{}{})
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{"name": "anonim"})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id")
+		context.SetParamNames("id")
+		context.SetParamValues("abc")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.Put())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{{Field: "id", Code: "invalid_id", Message: "id must be a positive number"}}, response.Data)
+	})
+
+	t.Run("error in server", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"name":       "anonim",
+			"priority":   1,
+			"project_id": 5,
+		})
 		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
 		res := httptest.NewRecorder()
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
 		context := e.NewContext(req, res)
-		context.SetPath("/todo/tasks/1")
-		taskController := New(&MockFailGetByIdRespTaskLib{}, &MockFailProLib{})
+		context.SetPath("/todo/tasks/:id")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+		taskController := New(&MockFailTaskLib{}, &MockProLib{})
 		// taskController.Create()(context)
-		if err := middlewares.JwtMiddleware()(taskController.UpdateStatus())(context); err != nil {
-			log.Fatal(err)
-			return
+		if err := middlewares.JwtMiddleware()(taskController.Put())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
 		}
 		response := GetTaskResponFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in database process", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
-	t.Run("success to update status", func(t *testing.T) {
+	t.Run("success to update task", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]interface{}{
 			"name":       "anonim",
 			"priority":   1,
 			"project_id": 5,
-			"status":     true,
 		})
 		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
 		res := httptest.NewRecorder()
@@ -509,33 +708,761 @@ func TestUpdateStatus(t *testing.T) {
 
 		taskController := New(&MockTaskLib{}, &MockProLib{})
 		// taskController.Create()(context)
-		if err := middlewares.JwtMiddleware()(taskController.UpdateStatus())(context); err != nil {
-			log.Fatal(err)
-			return
+		if err := middlewares.JwtMiddleware()(taskController.Put())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetTaskResponFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 200, response.Code)
-		assert.Equal(t, "success to update status", response.Message)
+		assert.Equal(t, "success to update task", response.Message)
 	})
 }
 
-type MockTaskLib struct{}
+func TestPatch(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
 
-func (m *MockTaskLib) Create(user_id int, newTask task.Task) (task.Task, error) {
+	patch := func(body string, contentType string, taskLib _taskLib.Task) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
 
-	return task.Task{
-		User_ID:  uint(user_id),
-		Name:     newTask.Name,
-		Priority: newTask.Priority,
-	}, nil
-}
+		taskController := New(taskLib, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.Patch())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		return res
+	}
 
-func (m *MockTaskLib) GetAll(user_id int) ([]response.TaskResponse, error) {
+	t.Run("success to patch task", func(t *testing.T) {
+		res := patch(`{"priority":0,"due_at":null,"timezone":"Asia/Jakarta"}`, validation.MIMEMergePatch, &MockPatchTaskLib{})
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "success to update task", response.Message)
+		assert.Equal(t, "Weekly report", response.Data["name"])
+		assert.Equal(t, float64(0), response.Data["priority"])
+		assert.Equal(t, float64(5), response.Data["project_id"])
+		assert.Equal(t, float64(2), response.Data["parent_id"])
+		assert.Nil(t, response.Data["due_at"])
+		assert.Equal(t, "2022-03-01T09:00:00Z", response.Data["start_at"])
+		assert.Equal(t, "FREQ=WEEKLY", response.Data["recurrence"])
+		assert.Equal(t, "Asia/Jakarta", response.Data["timezone"])
+	})
+
+	t.Run("success to patch task clearing fields", func(t *testing.T) {
+		res := patch(`{"parent_id":null,"start_at":null,"recurrence":null}`, echo.MIMEApplicationJSON, &MockPatchTaskLib{})
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, float64(2), response.Data["priority"])
+		assert.Nil(t, response.Data["parent_id"])
+		assert.Nil(t, response.Data["start_at"])
+		assert.Equal(t, "", response.Data["recurrence"])
+	})
+
+	t.Run("validation failed", func(t *testing.T) {
+		res := patch(`{"name":null,"due_at":"2022-02-01T09:00:00Z"}`, validation.MIMEMergePatch, &MockPatchTaskLib{})
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{
+			{Field: "name", Code: "required", Message: "name is required"},
+			{Field: "due_at", Code: "before_start", Message: "due_at must not be before start_at"},
+		}, response.Data)
+	})
+
+	t.Run("error input task", func(t *testing.T) {
+		for _, tc := range []struct{ body, contentType string }{
+			{`["name"]`, validation.MIMEMergePatch},
+			{`{"priority":"high"}`, validation.MIMEMergePatch},
+			{`{"priority":0}`, echo.MIMETextPlain},
+		} {
+			res := patch(tc.body, tc.contentType, &MockPatchTaskLib{})
+			response := GetTaskResponFormat{}
+
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, 400, response.Code, tc.body)
+			assert.Equal(t, "error in input task", response.Message, tc.body)
+		}
+	})
+
+	t.Run("error in server", func(t *testing.T) {
+		res := patch(`{"priority":0}`, validation.MIMEMergePatch, &MockFailTaskLib{})
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	t.Run("error in server", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"name_task": "anonim",
+			"priority":  1,
+		})
+		req := httptest.NewRequest(http.MethodDelete, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+		taskController := New(&MockFailTaskLib{}, &MockProLib{})
+		// taskController.Create()(context)
+		if err := middlewares.JwtMiddleware()(taskController.Delete())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
+	})
+
+	t.Run("success to delete task", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"name_task": "anonim123",
+			"priority":  1,
+		})
+		req := httptest.NewRequest(http.MethodDelete, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		// taskController.Create()(context)
+		if err := middlewares.JwtMiddleware()(taskController.Delete())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "success to delete task", response.Message)
+	})
+}
+
+func TestTransition(t *testing.T) {
+	var jwtToken string
+
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	t.Run("error in input status", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/transition")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.Transition())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{
+			{Field: "status", Code: "required", Message: "status is required"},
+		}, response.Data)
+	})
+
+	t.Run("illegal status transition", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"status": "review",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/transition")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockFailTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.Transition())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 409, response.Code)
+		assert.Equal(t, "illegal status transition", response.Message)
+	})
+
+	t.Run("error in server", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"status": "in_progress",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/transition")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockFailGetByIdRespTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.Transition())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
+	})
+
+	t.Run("success to transition task", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]interface{}{
+			"status": "in_progress",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/transition")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.Transition())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "success to transition task", response.Message)
+	})
+}
+
+func TestMove(t *testing.T) {
+	var jwtToken string
+
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _taskLib.Task
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"error in input move", &MockTaskLib{}, map[string]interface{}{"before_id": 2, "after_id": 3}, 422, "validation failed"},
+		{"error in input position", &MockFailTaskLib{}, map[string]interface{}{"after_id": 1}, 422, "task can only be moved next to another task of its project"},
+		{"error in server", &MockFailGetByIdRespTaskLib{}, map[string]interface{}{"after_id": 2}, 500, "error in server"},
+		{"success to move task", &MockTaskLib{}, map[string]interface{}{"status": "in_progress", "section_id": 1, "after_id": 2}, 200, "success to move task"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/todo/tasks/:id/move")
+			context.SetParamNames("id")
+			context.SetParamValues("1")
+
+			taskController := New(tc.repo, &MockProLib{})
+			if err := middlewares.JwtMiddleware()(taskController.Move())(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetTaskResponFormat{}
+
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+}
+
+func TestTaskCompleted(t *testing.T) {
+	var jwtToken string
+
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	t.Run("error in input cascade", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPost, "/?cascade=maybe", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/complete")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.TaskCompleted())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 400, response.Code)
+		assert.Equal(t, "error in input cascade", response.Message)
+	})
+
+	t.Run("illegal status transition", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/complete")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockFailTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.TaskCompleted())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 409, response.Code)
+		assert.Equal(t, "illegal status transition", response.Message)
+	})
+
+	t.Run("error in server", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/complete")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockFailGetByIdRespTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.TaskCompleted())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
+	})
+
+	t.Run("success to complete task", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPost, "/?cascade=true", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/complete")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.TaskCompleted())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "success to complete task", response.Message)
+	})
+}
+
+func TestTaskReopened(t *testing.T) {
+	var jwtToken string
+
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	t.Run("illegal status transition", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/reopen")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockFailTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.TaskReopened())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 409, response.Code)
+		assert.Equal(t, "illegal status transition", response.Message)
+	})
+
+	t.Run("error in server", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/reopen")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockFailGetByIdRespTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.TaskReopened())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
+	})
+
+	t.Run("success to reopen task", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(nil))
+		res := httptest.NewRecorder()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/todo/tasks/:id/reopen")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		taskController := New(&MockTaskLib{}, &MockProLib{})
+		if err := middlewares.JwtMiddleware()(taskController.TaskReopened())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		response := GetTaskResponFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "success to reopen task", response.Message)
+	})
+}
+
+func TestAssignees(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _taskLib.Task
+		proLib  _proLib.Project
+		handler func(tc *TaskController) echo.HandlerFunc
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"success to get assignees", &MockTaskLib{}, &MockProLib{}, (*TaskController).GetAssignees, nil, 200, "success to get assignees"},
+		{"forbidden get assignees", &MockFailTaskLib{}, &MockProLib{}, (*TaskController).GetAssignees, nil, 403, "forbidden access"},
+		{"error in input assignee", &MockTaskLib{}, &MockProLib{}, (*TaskController).AddAssignees, map[string]interface{}{"user_ids": []uint{}}, 422, "validation failed"},
+		{"assignee is not a project member", &MockTaskLib{}, &MockProLib{}, (*TaskController).AddAssignees, map[string]interface{}{"user_ids": []uint{1, 2}}, 422, "validation failed"},
+		{"error in get members", &MockTaskLib{}, &MockFailProLib{}, (*TaskController).AddAssignees, map[string]interface{}{"user_ids": []uint{1}}, 500, "error in server"},
+		{"error in get task", &MockFailGetByIdRespTaskLib{}, &MockProLib{}, (*TaskController).AddAssignees, map[string]interface{}{"user_ids": []uint{1}}, 500, "error in server"},
+		{"success to add assignees", &MockTaskLib{}, &MockProLib{}, (*TaskController).AddAssignees, map[string]interface{}{"user_ids": []uint{1}}, 200, "success to add assignees"},
+		{"success to remove assignee", &MockTaskLib{}, &MockProLib{}, (*TaskController).DeleteAssignee, nil, 200, "success to remove assignee"},
+		{"assignee not found", &MockFailTaskLib{}, &MockProLib{}, (*TaskController).DeleteAssignee, nil, 404, "task or assignee not found"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/todo/tasks/:id/assignees/:user_id")
+			context.SetParamNames("id", "user_id")
+			context.SetParamValues("1", "2")
+
+			taskController := New(tc.repo, tc.proLib)
+			if err := middlewares.JwtMiddleware()(tc.handler(taskController))(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetTaskResponFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+}
+
+func TestChecklist(t *testing.T) {
+	var jwtToken string
+	t.Run("success login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		jwtToken = response.Data["token"].(string)
+		assert.Equal(t, 200, response.Code)
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	cases := []struct {
+		name    string
+		repo    _taskLib.Task
+		handler func(tc *TaskController) echo.HandlerFunc
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"success to get checklist", &MockTaskLib{}, (*TaskController).GetChecklist, nil, 200, "success to get checklist"},
+		{"forbidden get checklist", &MockFailTaskLib{}, (*TaskController).GetChecklist, nil, 403, "forbidden access"},
+		{"error in input checklist item", &MockTaskLib{}, (*TaskController).AddChecklistItem, map[string]interface{}{"done": true}, 422, "validation failed"},
+		{"success to add checklist item", &MockTaskLib{}, (*TaskController).AddChecklistItem, map[string]interface{}{"name": "anonim"}, 201, "success to add checklist item"},
+		{"error in add checklist item", &MockFailGetByIdRespTaskLib{}, (*TaskController).AddChecklistItem, map[string]interface{}{"name": "anonim"}, 500, "error in server"},
+		{"empty checklist update", &MockTaskLib{}, (*TaskController).PutChecklistItem, map[string]interface{}{}, 422, "validation failed"},
+		{"success to update checklist item", &MockTaskLib{}, (*TaskController).PutChecklistItem, map[string]interface{}{"done": true}, 200, "success to update checklist item"},
+		{"checklist item not found", &MockFailTaskLib{}, (*TaskController).PutChecklistItem, map[string]interface{}{"done": true}, 404, "task or checklist item not found"},
+		{"success to remove checklist item", &MockTaskLib{}, (*TaskController).DeleteChecklistItem, nil, 200, "success to remove checklist item"},
+		{"remove missing checklist item", &MockFailTaskLib{}, (*TaskController).DeleteChecklistItem, nil, 404, "task or checklist item not found"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+			context := e.NewContext(req, res)
+			context.SetPath("/todo/tasks/:id/checklist/:item_id")
+			context.SetParamNames("id", "item_id")
+			context.SetParamValues("1", "1")
+
+			taskController := New(tc.repo, &MockProLib{})
+			if err := middlewares.JwtMiddleware()(tc.handler(taskController))(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+			response := GetTaskResponFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, tc.code, response.Code)
+			assert.Equal(t, tc.message, response.Message)
+		})
+	}
+}
+
+type MockTaskLib struct{}
+
+func (m *MockTaskLib) Create(user_id int, newTask task.Task) (task.Task, error) {
+
+	return task.Task{
+		CreatedBy: uint(user_id),
+		Name:      newTask.Name,
+		Priority:  newTask.Priority,
+	}, nil
+}
 
-	return []response.TaskResponse{}, nil
+func (m *MockTaskLib) GetAll(user_id int, filter request.TaskFilter, page base.Page) ([]response.TaskResponse, base.Meta, error) {
+	if len(page.Sort) > 0 && page.Sort[0].Field == "password" {
+		return nil, base.Meta{}, paginate.ErrInvalidSort
+	}
+	return []response.TaskResponse{{ID: 1}}, base.Meta{Total: 45, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (m *MockTaskLib) GetAgenda(user_id int, now time.Time, loc *time.Location, days int) (response.AgendaResponse, error) {
+	return response.AgendaResponse{}, nil
 }
 
 func (m *MockTaskLib) UpdateById(id int, user_id int, taskReg request.TaskRequest) (task.Task, error) {
@@ -552,9 +1479,81 @@ func (m *MockTaskLib) GetByIdResp(id int, user_id int) (response.TaskResponse, e
 	return response.TaskResponse{}, nil
 }
 
-func (m *MockTaskLib) UpdateStatus(id int, user_id int, status bool) (bool, error) {
+func (m *MockTaskLib) TaskCompleted(id int, user_id int, cascade bool) (task.Task, error) {
+	return task.Task{Model: gorm.Model{ID: uint(id)}, CreatedBy: uint(user_id), Status: workflow.StatusDone}, nil
+}
+
+func (m *MockTaskLib) TaskReopened(id int, user_id int) (task.Task, error) {
+	return task.Task{Model: gorm.Model{ID: uint(id)}, CreatedBy: uint(user_id), Status: workflow.StatusTodo}, nil
+}
+
+func (m *MockTaskLib) Transition(id int, user_id int, status string) (task.Task, error) {
+	return task.Task{Model: gorm.Model{ID: uint(id)}, CreatedBy: uint(user_id), Status: status}, nil
+}
+
+func (m *MockTaskLib) Move(id int, user_id int, move request.MoveRequest) (task.Task, error) {
+	return task.Task{Model: gorm.Model{ID: uint(id)}, CreatedBy: uint(user_id), Section_id: move.Section_id}, nil
+}
+
+func (m *MockTaskLib) GetAssignees(id int, user_id int) ([]response.AssigneeResponse, error) {
+	return []response.AssigneeResponse{{User_id: uint(user_id)}}, nil
+}
+
+func (m *MockTaskLib) AddAssignees(id int, user_id int, assignees []uint) ([]response.AssigneeResponse, error) {
+	res := []response.AssigneeResponse{}
+	for _, assignee := range assignees {
+		res = append(res, response.AssigneeResponse{User_id: assignee})
+	}
+	return res, nil
+}
+
+func (m *MockTaskLib) RemoveAssignee(id int, user_id int, assignee_id int) error {
+	return nil
+}
+
+func (m *MockTaskLib) GetChecklist(id int, user_id int) ([]response.ChecklistItemResponse, error) {
+	return []response.ChecklistItemResponse{{ID: 1, Name: "anonim"}}, nil
+}
+
+func (m *MockTaskLib) AddChecklistItem(id int, user_id int, item task.ChecklistItem) (task.ChecklistItem, error) {
+	item.ID = 1
+	item.Task_ID = uint(id)
+	return item, nil
+}
+
+func (m *MockTaskLib) UpdateChecklistItem(id int, user_id int, item_id int, itemReq request.ChecklistRequest) (task.ChecklistItem, error) {
+	return itemReq.ToChecklistItem(), nil
+}
+
+func (m *MockTaskLib) DeleteChecklistItem(id int, user_id int, item_id int) error {
+	return nil
+}
+
+// MockPatchTaskLib has a task to patch and answers the update as it was asked
+type MockPatchTaskLib struct {
+	MockTaskLib
+}
+
+func (m *MockPatchTaskLib) GetByIdResp(id int, user_id int) (response.TaskResponse, error) {
+	parent := uint(2)
+	start := time.Date(2022, 3, 1, 9, 0, 0, 0, time.UTC)
+	due := start.Add(24 * time.Hour)
+	return response.TaskResponse{
+		ID:         uint(id),
+		Name:       "Weekly report",
+		Priority:   2,
+		Project_id: 5,
+		Parent_id:  &parent,
+		StartAt:    &start,
+		DueAt:      &due,
+		Recurrence: "FREQ=WEEKLY",
+	}, nil
+}
 
-	return status, nil
+func (m *MockPatchTaskLib) UpdateById(id int, user_id int, taskReg request.TaskRequest) (task.Task, error) {
+	upTask := taskReg.ToTask()
+	upTask.ID = uint(id)
+	return upTask, nil
 }
 
 type MockFailTaskLib struct{}
@@ -564,8 +1563,12 @@ func (mf *MockFailTaskLib) Create(user_id int, newTask task.Task) (task.Task, er
 	return task.Task{}, errors.New("error in database process")
 }
 
-func (mf *MockFailTaskLib) GetAll(user_id int) ([]response.TaskResponse, error) {
-	return []response.TaskResponse{}, errors.New("error in database process")
+func (mf *MockFailTaskLib) GetAll(user_id int, filter request.TaskFilter, page base.Page) ([]response.TaskResponse, base.Meta, error) {
+	return nil, base.Meta{}, errors.New("error in database process")
+}
+
+func (mf *MockFailTaskLib) GetAgenda(user_id int, now time.Time, loc *time.Location, days int) (response.AgendaResponse, error) {
+	return response.AgendaResponse{}, errors.New("error in database process")
 }
 
 func (mf *MockFailTaskLib) UpdateById(id int, user_id int, taskReg request.TaskRequest) (task.Task, error) {
@@ -582,8 +1585,48 @@ func (m *MockFailTaskLib) GetByIdResp(id int, user_id int) (response.TaskRespons
 	return response.TaskResponse{}, errors.New("error in database process")
 }
 
-func (m *MockFailTaskLib) UpdateStatus(id int, user_id int, status bool) (bool, error) {
-	return false, errors.New("error in database process")
+func (m *MockFailTaskLib) TaskCompleted(id int, user_id int, cascade bool) (task.Task, error) {
+	return task.Task{}, _taskLib.ErrIllegalTransition
+}
+
+func (m *MockFailTaskLib) TaskReopened(id int, user_id int) (task.Task, error) {
+	return task.Task{}, _taskLib.ErrIllegalTransition
+}
+
+func (m *MockFailTaskLib) Transition(id int, user_id int, status string) (task.Task, error) {
+	return task.Task{}, _taskLib.ErrIllegalTransition
+}
+
+func (m *MockFailTaskLib) Move(id int, user_id int, move request.MoveRequest) (task.Task, error) {
+	return task.Task{}, _taskLib.ErrInvalidPosition
+}
+
+func (m *MockFailTaskLib) GetAssignees(id int, user_id int) ([]response.AssigneeResponse, error) {
+	return nil, _proLib.ErrForbidden
+}
+
+func (m *MockFailTaskLib) AddAssignees(id int, user_id int, assignees []uint) ([]response.AssigneeResponse, error) {
+	return nil, _proLib.ErrForbidden
+}
+
+func (m *MockFailTaskLib) RemoveAssignee(id int, user_id int, assignee_id int) error {
+	return _taskLib.ErrAssigneeNotFound
+}
+
+func (m *MockFailTaskLib) GetChecklist(id int, user_id int) ([]response.ChecklistItemResponse, error) {
+	return nil, _proLib.ErrForbidden
+}
+
+func (m *MockFailTaskLib) AddChecklistItem(id int, user_id int, item task.ChecklistItem) (task.ChecklistItem, error) {
+	return task.ChecklistItem{}, _proLib.ErrForbidden
+}
+
+func (m *MockFailTaskLib) UpdateChecklistItem(id int, user_id int, item_id int, itemReq request.ChecklistRequest) (task.ChecklistItem, error) {
+	return task.ChecklistItem{}, _taskLib.ErrChecklistItemNotFound
+}
+
+func (m *MockFailTaskLib) DeleteChecklistItem(id int, user_id int, item_id int) error {
+	return _taskLib.ErrChecklistItemNotFound
 }
 
 type MockFailGetByIdRespTaskLib struct{}
@@ -593,8 +1636,12 @@ func (mf *MockFailGetByIdRespTaskLib) Create(user_id int, newTask task.Task) (ta
 	return task.Task{}, nil
 }
 
-func (mf *MockFailGetByIdRespTaskLib) GetAll(user_id int) ([]response.TaskResponse, error) {
-	return []response.TaskResponse{}, errors.New("error in database process")
+func (mf *MockFailGetByIdRespTaskLib) GetAll(user_id int, filter request.TaskFilter, page base.Page) ([]response.TaskResponse, base.Meta, error) {
+	return nil, base.Meta{}, errors.New("error in database process")
+}
+
+func (mf *MockFailGetByIdRespTaskLib) GetAgenda(user_id int, now time.Time, loc *time.Location, days int) (response.AgendaResponse, error) {
+	return response.AgendaResponse{}, errors.New("error in database process")
 }
 
 func (mf *MockFailGetByIdRespTaskLib) UpdateById(id int, user_id int, taskReg request.TaskRequest) (task.Task, error) {
@@ -611,8 +1658,48 @@ func (m *MockFailGetByIdRespTaskLib) GetByIdResp(id int, user_id int) (response.
 	return response.TaskResponse{}, errors.New("error in database process")
 }
 
-func (m *MockFailGetByIdRespTaskLib) UpdateStatus(id int, user_id int, status bool) (bool, error) {
-	return false, errors.New("error in database process")
+func (m *MockFailGetByIdRespTaskLib) TaskCompleted(id int, user_id int, cascade bool) (task.Task, error) {
+	return task.Task{}, errors.New("error in database process")
+}
+
+func (m *MockFailGetByIdRespTaskLib) TaskReopened(id int, user_id int) (task.Task, error) {
+	return task.Task{}, errors.New("error in database process")
+}
+
+func (m *MockFailGetByIdRespTaskLib) Transition(id int, user_id int, status string) (task.Task, error) {
+	return task.Task{}, errors.New("error in database process")
+}
+
+func (m *MockFailGetByIdRespTaskLib) Move(id int, user_id int, move request.MoveRequest) (task.Task, error) {
+	return task.Task{}, errors.New("error in database process")
+}
+
+func (m *MockFailGetByIdRespTaskLib) GetAssignees(id int, user_id int) ([]response.AssigneeResponse, error) {
+	return nil, errors.New("error in database process")
+}
+
+func (m *MockFailGetByIdRespTaskLib) AddAssignees(id int, user_id int, assignees []uint) ([]response.AssigneeResponse, error) {
+	return nil, errors.New("error in database process")
+}
+
+func (m *MockFailGetByIdRespTaskLib) RemoveAssignee(id int, user_id int, assignee_id int) error {
+	return errors.New("error in database process")
+}
+
+func (m *MockFailGetByIdRespTaskLib) GetChecklist(id int, user_id int) ([]response.ChecklistItemResponse, error) {
+	return nil, errors.New("error in database process")
+}
+
+func (m *MockFailGetByIdRespTaskLib) AddChecklistItem(id int, user_id int, item task.ChecklistItem) (task.ChecklistItem, error) {
+	return task.ChecklistItem{}, errors.New("error in database process")
+}
+
+func (m *MockFailGetByIdRespTaskLib) UpdateChecklistItem(id int, user_id int, item_id int, itemReq request.ChecklistRequest) (task.ChecklistItem, error) {
+	return task.ChecklistItem{}, errors.New("error in database process")
+}
+
+func (m *MockFailGetByIdRespTaskLib) DeleteChecklistItem(id int, user_id int, item_id int) error {
+	return errors.New("error in database process")
 }
 
 /* Moch authentification */
@@ -625,14 +1712,34 @@ func (m *MockAuthLib) Login(UserLogin reqU.Userlogin) (user.User, error) {
 	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
 }
 
+func (m *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (m *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (m *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (m *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
+
 type MockProLib struct{}
 
 func (m *MockProLib) Create(user_id int, newPro proMod.Project) (proMod.Project, error) {
 	return proMod.Project{User_ID: uint(user_id), Name: newPro.Name}, nil
 }
 
-func (m *MockProLib) GetAll(user_id int) ([]proResp.ProResponse, error) {
-	return []proResp.ProResponse{}, nil
+func (m *MockProLib) GetAll(user_id int, filter proReq.ProFilter, page base.Page) ([]proResp.ProResponse, base.Meta, error) {
+	return []proResp.ProResponse{}, base.Meta{}, nil
 }
 
 func (m *MockProLib) UpdateById(id int, user_id int, upPro proReq.ProRequest) (proMod.Project, error) {
@@ -648,14 +1755,38 @@ func (m *MockProLib) GetById(id int, user_id int) (proMod.Project, error) {
 	return proMod.Project{}, nil
 }
 
+func (m *MockProLib) GetWorkflow(id int, user_id int) (workflow.Workflow, error) {
+	return workflow.Default(), nil
+}
+
+func (m *MockProLib) UpdateWorkflow(id int, user_id int, wf workflow.Workflow) (workflow.Workflow, error) {
+	return wf, nil
+}
+
+func (m *MockProLib) GetMembers(id int, user_id int) ([]proResp.MemberResponse, error) {
+	return []proResp.MemberResponse{{User_id: uint(user_id), Role: proMod.MemberOwner}}, nil
+}
+
+func (m *MockProLib) AddMember(id int, user_id int, newMember proMod.Member) (proMod.Member, error) {
+	return newMember, nil
+}
+
+func (m *MockProLib) UpdateMember(id int, user_id int, member_id int, role string) (proMod.Member, error) {
+	return proMod.Member{User_ID: uint(member_id), Role: role}, nil
+}
+
+func (m *MockProLib) RemoveMember(id int, user_id int, member_id int) error {
+	return nil
+}
+
 type MockFailProLib struct{}
 
 func (m *MockFailProLib) Create(user_id int, newPro proMod.Project) (proMod.Project, error) {
 	return proMod.Project{}, errors.New("error in call database")
 }
 
-func (m *MockFailProLib) GetAll(user_id int) ([]proResp.ProResponse, error) {
-	return []proResp.ProResponse{}, errors.New("error in call database")
+func (m *MockFailProLib) GetAll(user_id int, filter proReq.ProFilter, page base.Page) ([]proResp.ProResponse, base.Meta, error) {
+	return []proResp.ProResponse{}, base.Meta{}, errors.New("error in call database")
 }
 
 func (m *MockFailProLib) UpdateById(id int, user_id int, upPro proReq.ProRequest) (proMod.Project, error) {
@@ -670,3 +1801,27 @@ func (m *MockFailProLib) DeleteById(id int, user_id int) (gorm.DeletedAt, error)
 func (m *MockFailProLib) GetById(id int, user_id int) (proMod.Project, error) {
 	return proMod.Project{}, errors.New("error in database process")
 }
+
+func (m *MockFailProLib) GetWorkflow(id int, user_id int) (workflow.Workflow, error) {
+	return workflow.Workflow{}, errors.New("error in database process")
+}
+
+func (m *MockFailProLib) UpdateWorkflow(id int, user_id int, wf workflow.Workflow) (workflow.Workflow, error) {
+	return workflow.Workflow{}, errors.New("error in database process")
+}
+
+func (m *MockFailProLib) GetMembers(id int, user_id int) ([]proResp.MemberResponse, error) {
+	return nil, errors.New("error in database process")
+}
+
+func (m *MockFailProLib) AddMember(id int, user_id int, newMember proMod.Member) (proMod.Member, error) {
+	return proMod.Member{}, errors.New("error in database process")
+}
+
+func (m *MockFailProLib) UpdateMember(id int, user_id int, member_id int, role string) (proMod.Member, error) {
+	return proMod.Member{}, errors.New("error in database process")
+}
+
+func (m *MockFailProLib) RemoveMember(id int, user_id int, member_id int) error {
+	return errors.New("error in database process")
+}
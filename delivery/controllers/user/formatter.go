@@ -1,9 +1,19 @@
 package user
 
-import "part3/models/user"
+import (
+	"part3/models/base"
+	"part3/models/user"
+)
 
 type GetUserResponseFormat struct {
 	Code    int       `json:"code"`
 	Message string    `json:"message"`
 	Data    user.User `json:"data"`
 }
+
+type ValidationRespFormat struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Error   string            `json:"error"`
+	Data    []base.FieldError `json:"data"`
+}
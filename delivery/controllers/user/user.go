@@ -4,15 +4,25 @@ import (
 	"net/http"
 
 	"part3/delivery/middlewares"
+	"part3/delivery/validation"
 	"part3/lib/database/user"
 	"part3/models/base"
+	"part3/models/project"
 	"part3/models/user/request"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
 )
 
+// InvitationAcceptor accepts the invitation a new account registers with, the
+// signed token proves the invitee read the mail sent to that email
+type InvitationAcceptor interface {
+	AcceptInvitation(token string, user_id int) (project.Member, error)
+}
+
 type UserController struct {
-	repo user.User
+	repo        user.User
+	invitations InvitationAcceptor
 }
 
 func New(repository user.User) *UserController {
@@ -21,16 +31,30 @@ func New(repository user.User) *UserController {
 	}
 }
 
+func (uc *UserController) SetInvitations(invitations InvitationAcceptor) {
+	uc.invitations = invitations
+}
+
 func (uc *UserController) Create() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		newUser := request.UserRegister{}
-		if err := c.Bind(&newUser); err != nil || newUser.Email == "" || newUser.Password == "" {
+		if err := c.Bind(&newUser); err != nil {
 			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in request Create", nil))
 		}
+		if err := c.Validate(&newUser); err != nil {
+			return err
+		}
 		res, err := uc.repo.Create(newUser.ToUser())
 
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(nil, "error in access Create", nil))
+			return err
+		}
+
+		// the account exists at this point, a failed invitation lookup must not fail the registration
+		if uc.invitations != nil && newUser.Invitation != "" {
+			if _, err := uc.invitations.AcceptInvitation(newUser.Invitation, int(res.ID)); err != nil {
+				log.Warn("error in accept invitation ", err)
+			}
 		}
 		return c.JSON(http.StatusCreated, base.Success(http.StatusCreated, "Success Create", res.ToUserResponse()))
 	}
@@ -44,30 +68,57 @@ func (uc *UserController) GetById() echo.HandlerFunc {
 		res, err := uc.repo.GetById(userid)
 
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(nil, "error in access Get By id", nil))
+			return err
 		}
 
 		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "Success Get By Id", res))
 	}
 }
 
+// UpdateById replaces the profile, an empty name is kept empty
 func (uc *UserController) UpdateById() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		userid := int(middlewares.ExtractTokenId(c))
-		upUser := request.UserRegister{}
+		upUser := request.UserUpdate{}
 
-		if err := c.Bind(&upUser); err != nil || upUser.Name == "" {
+		if err := c.Bind(&upUser); err != nil {
 			return c.JSON(http.StatusBadRequest, base.BadRequest(http.StatusBadRequest, "error in request Update", nil))
 		}
 
-		res, err := uc.repo.UpdateById(userid, upUser)
+		return uc.update(c, userid, upUser)
+	}
+}
 
+// PatchById changes only the fields of the merge patch
+func (uc *UserController) PatchById() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userid := int(middlewares.ExtractTokenId(c))
+		current, err := uc.repo.GetById(userid)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(http.StatusInternalServerError, "error in access Update", nil))
+			return err
+		}
+		upUser := request.UserUpdate{Name: current.Name, Email: current.Email}
+
+		if err := validation.BindPatch(c, &upUser); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(http.StatusBadRequest, "error in request Update", nil))
 		}
 
-		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "Success Update By Id", res))
+		return uc.update(c, userid, upUser)
+	}
+}
+
+func (uc *UserController) update(c echo.Context, userid int, upUser request.UserUpdate) error {
+	if err := c.Validate(&upUser); err != nil {
+		return err
+	}
+
+	res, err := uc.repo.UpdateById(userid, request.UserRegister{Name: upUser.Name, Email: upUser.Email, Password: upUser.Password})
+
+	if err != nil {
+		return err
 	}
+
+	return c.JSON(http.StatusOK, base.Success(http.StatusOK, "Success Update By Id", res))
 }
 
 func (uc *UserController) DeleteById() echo.HandlerFunc {
@@ -78,7 +129,7 @@ func (uc *UserController) DeleteById() echo.HandlerFunc {
 		res, err := uc.repo.DeleteById(userid)
 
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, base.InternalServerError(http.StatusInternalServerError, "error in access Delete", nil))
+			return err
 		}
 
 		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "Success Delete By Id", res))
@@ -89,14 +140,37 @@ func (uc *UserController) GetAll() echo.HandlerFunc {
 	return func(c echo.Context) error {
 
 		res, err := uc.repo.GetAll()
-		email := middlewares.ExtractTokenAdmin(c)[0]
-		password := middlewares.ExtractTokenAdmin(c)[1]
 
-		if err != nil || email != "admin" && password != "admin" {
-			return c.JSON(http.StatusBadRequest, base.BadRequest(nil, "error in request Get", nil))
+		if err != nil {
+			return err
 		}
 
 		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "Success Get All User", res))
 
 	}
 }
+
+func (uc *UserController) UpdateRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := validation.ParamID(c, "id")
+		if err != nil {
+			return err
+		}
+		upRole := request.UserRole{}
+
+		if err := c.Bind(&upRole); err != nil {
+			return c.JSON(http.StatusBadRequest, base.BadRequest(http.StatusBadRequest, "error in request Update Role", nil))
+		}
+		if err := c.Validate(&upRole); err != nil {
+			return err
+		}
+
+		res, err := uc.repo.UpdateRole(id, upRole.Role)
+
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, base.Success(http.StatusOK, "Success Update Role", res.ToUserResponse()))
+	}
+}
@@ -9,9 +9,15 @@ import (
 	"net/http/httptest"
 	"part3/delivery/controllers/auth"
 	"part3/delivery/middlewares"
+	"part3/delivery/validation"
+	_userLib "part3/lib/database/user"
+	"part3/models/base"
+	proMod "part3/models/project"
+	"part3/models/session"
 	"part3/models/user"
 	"part3/models/user/request"
 	"part3/models/user/response"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
@@ -31,6 +37,7 @@ func TestCreate(t *testing.T) {
 
 	t.Run("Success Login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
@@ -56,6 +63,7 @@ func TestCreate(t *testing.T) {
 
 	t.Run("Failed to Create", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		reqBody, _ := json.Marshal(UserRegister{
 			Name:     0,
@@ -72,7 +80,9 @@ func TestCreate(t *testing.T) {
 		context.SetPath("/users")
 
 		userController := New(&MockUserLib{})
-		userController.Create()(context)
+		if err := userController.Create()(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
 
 		response := GetUserResponseFormat{}
 
@@ -83,11 +93,81 @@ func TestCreate(t *testing.T) {
 
 	})
 
+	t.Run("Failed validation", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+
+		reqBody, _ := json.Marshal(map[string]string{
+			"name":  strings.Repeat("a", 101),
+			"email": "anonim",
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+
+		req.Header.Set("Content-Type", "application/json")
+
+		context := e.NewContext(req, res)
+		context.SetPath("/users")
+
+		userController := New(&MockUserLib{})
+		if err := userController.Create()(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, "validation_failed", response.Error)
+		assert.Equal(t, []base.FieldError{
+			{Field: "name", Code: "too_long", Message: "name must be at most 100 characters"},
+			{Field: "email", Code: "invalid_email", Message: "email must be a valid email address"},
+			{Field: "password", Code: "required", Message: "password is required"},
+		}, response.Data)
+	})
+
+	t.Run("Failed validation password bytes", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@mail.com",
+			"password": strings.Repeat("é", 37),
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+
+		req.Header.Set("Content-Type", "application/json")
+
+		context := e.NewContext(req, res)
+		context.SetPath("/users")
+
+		userController := New(&MockUserLib{})
+		if err := userController.Create()(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{
+			{Field: "name", Code: "required", Message: "name is required"},
+			{Field: "password", Code: "too_long", Message: "password must be at most 72 bytes"},
+		}, response.Data)
+	})
+
 	t.Run("Failed to Access", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		reqBody, _ := json.Marshal(map[string]string{
-			"email":    "anonim",
+			"name":     "anonim",
+			"email":    "anonim@mail.com",
 			"password": "anonim",
 		})
 
@@ -101,22 +181,25 @@ func TestCreate(t *testing.T) {
 		context.SetPath("/users")
 
 		userController := New(&MockUserLib{})
-		userController.Create()(context)
+		if err := userController.Create()(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
 
 		response := GetUserResponseFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in access Create", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 
 	})
 
 	t.Run("Success Create", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"name":     "anonim123",
-			"email":    "anonim@123",
+			"email":    "anonim@123.com",
 			"password": "anonim123",
 		})
 		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
@@ -129,7 +212,9 @@ func TestCreate(t *testing.T) {
 		context.SetPath("/users")
 
 		userController := New(&MockUserLib{})
-		userController.Create()(context)
+		if err := userController.Create()(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
 
 		response := GetUserResponseFormat{}
 
@@ -137,6 +222,43 @@ func TestCreate(t *testing.T) {
 		assert.Equal(t, 201, response.Code)
 		assert.Equal(t, "anonim123", response.Data.Name)
 	})
+
+	t.Run("Success Create accepts the invitation", func(t *testing.T) {
+		for _, tc := range []struct {
+			invitations *MockInvitationLib
+			token       string
+		}{
+			{&MockInvitationLib{}, "1.signature"},
+			{&MockInvitationLib{fail: true}, "1.signature"},
+			{&MockInvitationLib{}, ""},
+		} {
+			invitations := tc.invitations
+			e := echo.New()
+			e.Validator = validation.New()
+			reqBody, _ := json.Marshal(map[string]string{
+				"name":       "anonim123",
+				"email":      "anonim@123.com",
+				"password":   "anonim123",
+				"invitation": tc.token,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+			res := httptest.NewRecorder()
+			req.Header.Set("Content-Type", "application/json")
+			context := e.NewContext(req, res)
+			context.SetPath("/users")
+
+			userController := New(&MockUserLib{})
+			userController.SetInvitations(invitations)
+			if err := userController.Create()(context); err != nil {
+				middlewares.ErrorHandler(err, context)
+			}
+
+			response := GetUserResponseFormat{}
+			json.Unmarshal([]byte(res.Body.Bytes()), &response)
+			assert.Equal(t, 201, response.Code)
+			assert.Equal(t, tc.token, invitations.token)
+		}
+	})
 }
 
 func TestGetById(t *testing.T) {
@@ -144,6 +266,7 @@ func TestGetById(t *testing.T) {
 
 	t.Run("Success Login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -170,6 +293,7 @@ func TestGetById(t *testing.T) {
 	t.Run("Fail to Get By Id", func(t *testing.T) {
 
 		e := echo.New()
+		e.Validator = validation.New()
 		// userid := int(middlewares.ExtractTokenId(c))
 
 		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
@@ -183,7 +307,7 @@ func TestGetById(t *testing.T) {
 
 		userController := New(&MockFalseLib{})
 		if err := middlewares.JwtMiddleware()(userController.GetById())(context); err != nil {
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetUserResponseFormat{}
@@ -191,12 +315,13 @@ func TestGetById(t *testing.T) {
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		// log.Info(response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in access Get By id", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
 	t.Run("Success Get By Id", func(t *testing.T) {
 
 		e := echo.New()
+		e.Validator = validation.New()
 		// userid := int(middlewares.ExtractTokenId(c))
 
 		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
@@ -210,7 +335,7 @@ func TestGetById(t *testing.T) {
 
 		userController := New(&MockUserLib{})
 		if err := middlewares.JwtMiddleware()(userController.GetById())(context); err != nil {
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetUserResponseFormat{}
@@ -227,6 +352,7 @@ func TestUpdateByID(t *testing.T) {
 
 	t.Run("Success Login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -252,6 +378,7 @@ func TestUpdateByID(t *testing.T) {
 
 	t.Run("Error input Update", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		reqBody, _ := json.Marshal(map[string]string{})
 		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
@@ -265,22 +392,23 @@ func TestUpdateByID(t *testing.T) {
 
 		userController := New(&MockFalseLib{})
 		if err := middlewares.JwtMiddleware()(userController.UpdateById())(context); err != nil {
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetUserResponseFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		assert.Equal(t, 400, response.Code)
-		assert.Equal(t, "error in request Update", response.Message)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, "validation failed", response.Message)
 	})
 
 	t.Run("Error access Update", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		reqBody, _ := json.Marshal(map[string]interface{}{
 			"name":     "anonim123",
-			"email":    "anonim@123",
+			"email":    "anonim@123.com",
 			"password": "anonim123",
 		})
 		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
@@ -294,22 +422,23 @@ func TestUpdateByID(t *testing.T) {
 
 		userController := New(&MockFalseLib{})
 		if err := middlewares.JwtMiddleware()(userController.UpdateById())(context); err != nil {
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetUserResponseFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in access Update", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 	})
 
 	t.Run("Success Update", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		reqBody, _ := json.Marshal(map[string]string{
 			"name":     "anonim123",
-			"email":    "anonim@123",
+			"email":    "anonim@123.com",
 			"password": "anonim123",
 		})
 		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
@@ -323,7 +452,7 @@ func TestUpdateByID(t *testing.T) {
 
 		userController := New(&MockUserLib{})
 		if err := middlewares.JwtMiddleware()(userController.UpdateById())(context); err != nil {
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetUserResponseFormat{}
@@ -334,6 +463,142 @@ func TestUpdateByID(t *testing.T) {
 		log.Info(response.Data)
 	})
 
+	t.Run("Failed validation Update without name", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+
+		reqBody, _ := json.Marshal(map[string]string{
+			"email": "anonim@123.com",
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+
+		context := e.NewContext(req, res)
+		context.SetPath("/users/me")
+
+		userController := New(&MockUserLib{})
+		if err := middlewares.JwtMiddleware()(userController.UpdateById())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{{Field: "name", Code: "required", Message: "name is required"}}, response.Data)
+	})
+
+}
+
+func TestPatchByID(t *testing.T) {
+	var jwtToken string
+
+	t.Run("Success Login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+
+		response := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+
+		jwtToken = response.Data["token"].(string)
+
+		assert.Equal(t, response.Message, "success login")
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	patch := func(body string, userLib _userLib.User) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.Validator = validation.New()
+
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		res := httptest.NewRecorder()
+
+		req.Header.Set("Content-Type", validation.MIMEMergePatch)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+
+		context := e.NewContext(req, res)
+		context.SetPath("/users/me")
+
+		userController := New(userLib)
+		if err := middlewares.JwtMiddleware()(userController.PatchById())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+		return res
+	}
+
+	t.Run("Success Patch", func(t *testing.T) {
+		res := patch(`{"email":"anonim@321.com"}`, &MockPatchUserLib{})
+		response := GetUserResponseFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "anonim123", response.Data.Name)
+		assert.Equal(t, "anonim@321.com", response.Data.Email)
+		assert.Equal(t, "", response.Data.Password)
+	})
+
+	t.Run("Success Patch password", func(t *testing.T) {
+		res := patch(`{"password":"anonim321"}`, &MockPatchUserLib{})
+		response := GetUserResponseFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "anonim123", response.Data.Name)
+		assert.Equal(t, "anonim@123.com", response.Data.Email)
+		assert.Equal(t, "anonim321", response.Data.Password)
+	})
+
+	t.Run("Failed validation Patch clears name", func(t *testing.T) {
+		res := patch(`{"name":null}`, &MockPatchUserLib{})
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{{Field: "name", Code: "required", Message: "name is required"}}, response.Data)
+	})
+
+	t.Run("Failed validation Patch", func(t *testing.T) {
+		res := patch(`{"email":null}`, &MockPatchUserLib{})
+		response := ValidationRespFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{{Field: "email", Code: "required", Message: "email is required"}}, response.Data)
+	})
+
+	t.Run("Error input Patch", func(t *testing.T) {
+		res := patch(`{"email":`, &MockPatchUserLib{})
+		response := GetUserResponseFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 400, response.Code)
+		assert.Equal(t, "error in request Update", response.Message)
+	})
+
+	t.Run("Error access Patch", func(t *testing.T) {
+		res := patch(`{"email":"anonim@321.com"}`, &MockFalseLib{})
+		response := GetUserResponseFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
+	})
 }
 
 func TestDeleteByID(t *testing.T) {
@@ -341,6 +606,7 @@ func TestDeleteByID(t *testing.T) {
 
 	t.Run("Success Login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"email":    "anonim@123",
 			"password": "anonim123",
@@ -366,6 +632,7 @@ func TestDeleteByID(t *testing.T) {
 
 	t.Run("Fail to Delete", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{})
 		req := httptest.NewRequest(http.MethodDelete, "/", bytes.NewBuffer(reqBody))
 		res := httptest.NewRecorder()
@@ -378,19 +645,20 @@ func TestDeleteByID(t *testing.T) {
 
 		userController := New(&MockFalseLib{})
 		if err := middlewares.JwtMiddleware()(userController.DeleteById())(context); err != nil {
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetUserResponseFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		assert.Equal(t, 400, response.Code)
-		assert.Equal(t, "error in request Delete", response.Message)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
 
 	})
 
 	t.Run("Fail to access Delete", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"name":     "anonim123",
 			"email":    "anonim@123.com",
@@ -407,19 +675,20 @@ func TestDeleteByID(t *testing.T) {
 
 		userController := New(&MockFalseLib{})
 		if err := middlewares.JwtMiddleware()(userController.DeleteById())(context); err != nil {
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetUserResponseFormat{}
 
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
 		assert.Equal(t, 500, response.Code)
-		assert.Equal(t, "error in access Delete", response.Message)
+		assert.Equal(t, "error in server", response.Message)
 
 	})
 
 	t.Run("Success Delete", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"name":     "anonim123",
 			"email":    "anonim@123.com",
@@ -436,7 +705,7 @@ func TestDeleteByID(t *testing.T) {
 
 		userController := New(&MockUserLib{})
 		if err := middlewares.JwtMiddleware()(userController.DeleteById())(context); err != nil {
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetUserResponseFormat{}
@@ -454,6 +723,7 @@ func TestGetAll(t *testing.T) {
 
 	t.Run("Success Login", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 		reqBody, _ := json.Marshal(map[string]string{
 			"name":     "admin",
 			"email":    "admin@admin.com",
@@ -480,6 +750,7 @@ func TestGetAll(t *testing.T) {
 
 	t.Run("Success Get All User", func(t *testing.T) {
 		e := echo.New()
+		e.Validator = validation.New()
 
 		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
@@ -492,7 +763,7 @@ func TestGetAll(t *testing.T) {
 
 		userController := New(&MockUserLib{})
 		if err := middlewares.JwtMiddleware()(userController.GetAll())(context); err != nil {
-			return
+			middlewares.ErrorHandler(err, context)
 		}
 
 		response := GetUserResponseFormat{}
@@ -505,6 +776,7 @@ func TestGetAll(t *testing.T) {
 	t.Run("Failed Get All User", func(t *testing.T) {
 		// token := string(jwtToken)
 		e := echo.New()
+		e.Validator = validation.New()
 
 		req := httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
 		res := httptest.NewRecorder()
@@ -519,21 +791,202 @@ func TestGetAll(t *testing.T) {
 		userController := New(&MockFalseLib{})
 
 		if err := middlewares.JwtMiddleware()(userController.GetAll())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+
+		response := GetUserResponseFormat{}
+
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
+	})
+
+	t.Run("Forbidden Get All User", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "anonim@123",
+			"password": "anonim123",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+		auth.New(&MockAuthLib{}).Login()(context)
+		loginResp := auth.LoginRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &loginResp)
+		memberToken := loginResp.Data["token"].(string)
+
+		req = httptest.NewRequest(http.MethodGet, "/", bytes.NewBuffer(nil))
+		res = httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", memberToken))
+		context = e.NewContext(req, res)
+		context.SetPath("/admin/users")
+
+		userController := New(&MockUserLib{})
+		if err := middlewares.JwtMiddleware()(middlewares.RequireRole(user.RoleAdmin)(userController.GetAll()))(context); err != nil {
 			return
 		}
 
 		response := GetUserResponseFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 403, response.Code)
+		assert.Equal(t, "forbidden access", response.Message)
+	})
+}
+
+func TestUpdateRole(t *testing.T) {
+	var jwtToken string
 
+	t.Run("Success Login", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"email":    "admin@admin.com",
+			"password": "admin",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+		context.SetPath("/login")
+
+		authController := auth.New(&MockAuthLib{})
+		authController.Login()(context)
+
+		response := auth.LoginRespFormat{}
 		json.Unmarshal([]byte(res.Body.Bytes()), &response)
-		assert.Equal(t, 400, response.Code)
-		assert.Equal(t, "error in request Get", response.Message)
+
+		jwtToken = response.Data["token"].(string)
+
+		assert.Equal(t, response.Message, "success login")
+		assert.NotNil(t, response.Data["token"])
+	})
+
+	t.Run("Failed Update Role", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"role": "superuser",
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/admin/users/:id/role")
+		context.SetParamNames("id")
+		context.SetParamValues("2")
+
+		userController := New(&MockUserLib{})
+		if err := middlewares.JwtMiddleware()(userController.UpdateRole())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+
+		response := ValidationRespFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 422, response.Code)
+		assert.Equal(t, []base.FieldError{
+			{Field: "role", Code: "invalid_choice", Message: "role must be one of admin manager member"},
+		}, response.Data)
+	})
+
+	t.Run("Failed access Update Role", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"role": user.RoleManager,
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/admin/users/:id/role")
+		context.SetParamNames("id")
+		context.SetParamValues("2")
+
+		userController := New(&MockFalseLib{})
+		if err := middlewares.JwtMiddleware()(userController.UpdateRole())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+
+		response := GetUserResponseFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 500, response.Code)
+		assert.Equal(t, "error in server", response.Message)
+	})
+
+	t.Run("Success Update Role", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = validation.New()
+		reqBody, _ := json.Marshal(map[string]string{
+			"role": user.RoleManager,
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(reqBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", jwtToken))
+		context := e.NewContext(req, res)
+		context.SetPath("/admin/users/:id/role")
+		context.SetParamNames("id")
+		context.SetParamValues("2")
+
+		userController := New(&MockUserLib{})
+		if err := middlewares.JwtMiddleware()(userController.UpdateRole())(context); err != nil {
+			middlewares.ErrorHandler(err, context)
+		}
+
+		response := GetUserResponseFormat{}
+		json.Unmarshal([]byte(res.Body.Bytes()), &response)
+		assert.Equal(t, 200, response.Code)
+		assert.Equal(t, "Success Update Role", response.Message)
+		assert.Equal(t, user.RoleManager, response.Data.Role)
 	})
 }
 
 type MockAuthLib struct{}
 
 func (ma *MockAuthLib) Login(UserLogin request.Userlogin) (user.User, error) {
-	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password}, nil
+	if UserLogin.Email == "admin@admin.com" {
+		return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password, Role: user.RoleAdmin}, nil
+	}
+	return user.User{Model: gorm.Model{ID: 1}, Email: UserLogin.Email, Password: UserLogin.Password, Role: user.RoleMember}, nil
+}
+
+func (ma *MockAuthLib) CreateSession(user_id int) (session.Session, string, error) {
+	return session.Session{Model: gorm.Model{ID: 1}, User_ID: uint(user_id)}, "refresh", nil
+}
+
+func (ma *MockAuthLib) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	return user.User{Model: gorm.Model{ID: 1}}, session.Session{Model: gorm.Model{ID: 1}, User_ID: 1}, "refresh", nil
+}
+
+func (ma *MockAuthLib) RevokeSession(session_id int, user_id int) error {
+	return nil
+}
+
+func (ma *MockAuthLib) RevokeAllSessions(user_id int) error {
+	return nil
+}
+
+func (ma *MockAuthLib) IsSessionActive(session_id int, user_id int) bool {
+	return true
+}
+
+type MockInvitationLib struct {
+	fail  bool
+	token string
+}
+
+func (m *MockInvitationLib) AcceptInvitation(token string, user_id int) (proMod.Member, error) {
+	m.token = token
+	if m.fail {
+		return proMod.Member{}, errors.New("invalid or expired invitation")
+	}
+	return proMod.Member{User_ID: uint(user_id)}, nil
 }
 
 type MockUserLib struct{}
@@ -553,6 +1006,10 @@ func (m *MockUserLib) UpdateById(id int, userReg request.UserRegister) (user.Use
 	return user.User{Name: userReg.Name, Email: userReg.Email, Password: userReg.Password}, nil
 }
 
+func (m *MockUserLib) UpdateRole(id int, role string) (user.User, error) {
+	return user.User{Model: gorm.Model{ID: uint(id)}, Role: role}, nil
+}
+
 func (m *MockUserLib) DeleteById(id int) (gorm.DeletedAt, error) {
 	user := user.User{}
 	return user.DeletedAt, nil
@@ -562,6 +1019,15 @@ func (m *MockUserLib) GetAll() ([]response.UserResponse, error) {
 	return []response.UserResponse{}, nil
 }
 
+// MockPatchUserLib has a profile to patch
+type MockPatchUserLib struct {
+	MockUserLib
+}
+
+func (m *MockPatchUserLib) GetById(id int) (response.UserResponse, error) {
+	return response.UserResponse{ID: uint(id), Name: "anonim123", Email: "anonim@123.com"}, nil
+}
+
 type MockFalseLib struct{}
 
 func (mf *MockFalseLib) Create(newUser user.User) (user.User, error) {
@@ -579,6 +1045,10 @@ func (mf *MockFalseLib) UpdateById(id int, userReg request.UserRegister) (user.U
 	return user.User{}, errors.New("False Object")
 }
 
+func (mf *MockFalseLib) UpdateRole(id int, role string) (user.User, error) {
+	return user.User{}, errors.New("False Object")
+}
+
 func (mf *MockFalseLib) DeleteById(id int) (gorm.DeletedAt, error) {
 	user := user.User{}
 	return user.DeletedAt, errors.New("False Object")
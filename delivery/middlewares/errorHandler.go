@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"part3/models/base"
+	"part3/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+// ErrorHandler is the echo HTTPErrorHandler, handlers return the errors they
+// do not answer themselves and this maps them to a status and a machine
+// readable code: failing fields to 422 listing them, typed domain errors by
+// their kind, a missing row to 404, a unique constraint to 409 and anything
+// else to 500
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status, res := ErrorResponse(err)
+	if status == http.StatusInternalServerError {
+		log.Error(err)
+	}
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, res)
+	}
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// ErrorResponse is the status and the body ErrorHandler answers err with
+func ErrorResponse(err error) (int, base.Response) {
+	var fields base.FieldErrors
+	var domain *base.Error
+	var httpErr *echo.HTTPError
+
+	switch {
+	case errors.As(err, &fields):
+		return http.StatusUnprocessableEntity, base.UnprocessableEntity(nil, nil, fields)
+	case errors.As(err, &domain):
+		status := domain.Status()
+		return status, base.Response{Code: status, Message: domain.Message, Error: domain.Code}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound, base.NotFound(nil, nil, nil)
+	case utils.IsDuplicate(err):
+		return http.StatusConflict, base.Conflict(nil, nil, nil)
+	case errors.As(err, &httpErr):
+		msg := http.StatusText(httpErr.Code)
+		if text, ok := httpErr.Message.(string); ok && text != "" {
+			msg = text
+		}
+		return httpErr.Code, base.Response{Code: httpErr.Code, Message: msg, Error: statusCode(httpErr.Code)}
+	}
+	return http.StatusInternalServerError, base.InternalServerError(nil, nil, nil)
+}
+
+// statusCode is the machine readable code of a status echo answered with
+func statusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return base.CodeBadRequest
+	case http.StatusUnauthorized:
+		return base.CodeUnauthorized
+	case http.StatusForbidden:
+		return base.CodeForbidden
+	case http.StatusNotFound:
+		return base.CodeNotFound
+	case http.StatusConflict:
+		return base.CodeConflict
+	case http.StatusUnprocessableEntity:
+		return base.CodeValidation
+	case http.StatusInternalServerError:
+		return base.CodeInternal
+	}
+	return fmt.Sprintf("http_%d", status)
+}
@@ -0,0 +1,83 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"part3/models/base"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestErrorHandler(t *testing.T) {
+	cases := []struct {
+		name    string
+		method  string
+		err     error
+		code    int
+		message string
+		errCode string
+	}{
+		{"not found domain error", http.MethodGet, base.NotFoundError("invalid_invitation", "invalid invitation"), 404, "invalid invitation", "invalid_invitation"},
+		{"conflict domain error", http.MethodPost, base.ConflictError("email_taken", "email is already registered"), 409, "email is already registered", "email_taken"},
+		{"forbidden domain error", http.MethodPut, base.ForbiddenError("forbidden", "forbidden access"), 403, "forbidden access", "forbidden"},
+		{"validation domain error", http.MethodGet, base.ValidationError("invalid_sort", "invalid sort field"), 422, "invalid sort field", "invalid_sort"},
+		{"too large domain error", http.MethodPost, base.TooLargeError("attachment_too_large", "attachment is too large"), 413, "attachment is too large", "attachment_too_large"},
+		{"media type domain error", http.MethodPost, base.MediaTypeError("unsupported_media_type", "unsupported attachment type"), 415, "unsupported attachment type", "unsupported_media_type"},
+		{"wrapped domain error", http.MethodGet, fmt.Errorf("list tasks: %w", base.ValidationError("invalid_cursor", "invalid cursor")), 422, "invalid cursor", "invalid_cursor"},
+		{"record not found", http.MethodGet, gorm.ErrRecordNotFound, 404, "not found", base.CodeNotFound},
+		{"duplicate key", http.MethodPost, &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, 409, "conflict with current state", base.CodeConflict},
+		{"echo error", http.MethodGet, echo.NewHTTPError(http.StatusUnauthorized, "token has been revoked"), 401, "token has been revoked", base.CodeUnauthorized},
+		{"echo error without message", http.MethodGet, echo.ErrMethodNotAllowed, 405, "Method Not Allowed", "http_405"},
+		{"unknown error", http.MethodGet, errors.New("connection refused"), 500, "error in server", base.CodeInternal},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(tc.method, "/", nil)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+
+			ErrorHandler(tc.err, context)
+
+			response := base.Response{}
+			json.Unmarshal(res.Body.Bytes(), &response)
+			assert.Equal(t, tc.code, res.Code)
+			assert.Equal(t, float64(tc.code), response.Code)
+			assert.Equal(t, tc.message, response.Message)
+			assert.Equal(t, tc.errCode, response.Error)
+		})
+	}
+
+	t.Run("head request has no body", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodHead, "/", nil)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+
+		ErrorHandler(gorm.ErrRecordNotFound, context)
+
+		assert.Equal(t, 404, res.Code)
+		assert.Equal(t, 0, res.Body.Len())
+	})
+
+	t.Run("committed response is left alone", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.String(http.StatusOK, "partial")
+
+		ErrorHandler(errors.New("write failed"), context)
+
+		assert.Equal(t, 200, res.Code)
+		assert.Equal(t, "partial", res.Body.String())
+	})
+}
@@ -1,13 +1,35 @@
 package middlewares
 
 import (
+	"net/http"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
+type SessionChecker interface {
+	IsSessionActive(session_id int, user_id int) bool
+}
+
+var sessionChecker SessionChecker
+
+// SetSessionChecker makes JwtMiddleware reject tokens whose session was revoked,
+// without a checker only the signature and expiry are verified
+func SetSessionChecker(checker SessionChecker) {
+	sessionChecker = checker
+}
+
 func JwtMiddleware() echo.MiddlewareFunc {
-	return middleware.JWTWithConfig(middleware.JWTConfig{
-		SigningMethod: "HS256",
-		SigningKey: []byte("secret"),
+	jwtMiddleware := middleware.JWTWithConfig(middleware.JWTConfig{
+		KeyFunc: keyFunc,
 	})
-}
\ No newline at end of file
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return jwtMiddleware(func(c echo.Context) error {
+			if sessionChecker != nil && !sessionChecker.IsSessionActive(int(ExtractTokenSessionId(c)), int(ExtractTokenId(c))) {
+				return echo.NewHTTPError(http.StatusUnauthorized, "token has been revoked")
+			}
+			return next(c)
+		})
+	}
+}
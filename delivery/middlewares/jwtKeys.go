@@ -0,0 +1,215 @@
+package middlewares
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"part3/configs"
+	"sort"
+	"sync"
+
+	"github.com/golang-jwt/jwt"
+)
+
+type jwtKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+type jwtKeySet struct {
+	issuer   string
+	audience string
+	signing  *jwtKey
+	verify   map[string]*jwtKey
+}
+
+var keysLock = &sync.Mutex{}
+var keySet *jwtKeySet
+
+// InitKeys loads the signing and verification keys, main calls it so a bad key fails at boot
+func InitKeys(config *configs.AppConfig) error {
+	set, err := loadKeySet(config)
+	if err != nil {
+		return err
+	}
+	keysLock.Lock()
+	defer keysLock.Unlock()
+	keySet = set
+	return nil
+}
+
+func getKeySet() (*jwtKeySet, error) {
+	keysLock.Lock()
+	defer keysLock.Unlock()
+	if keySet == nil {
+		set, err := loadKeySet(configs.GetConfig())
+		if err != nil {
+			return nil, err
+		}
+		keySet = set
+	}
+	return keySet, nil
+}
+
+func loadKeySet(config *configs.AppConfig) (*jwtKeySet, error) {
+	if len(config.JWT.Keys) == 0 {
+		return nil, errors.New("no jwt key configured")
+	}
+
+	set := &jwtKeySet{
+		issuer:   config.JWT.Issuer,
+		audience: config.JWT.Audience,
+		verify:   map[string]*jwtKey{},
+	}
+	for i, conf := range config.JWT.Keys {
+		key, err := loadKey(conf, i == 0)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", conf.ID, err)
+		}
+		if _, ok := set.verify[key.id]; ok {
+			return nil, fmt.Errorf("jwt key %q: duplicate id", conf.ID)
+		}
+		set.verify[key.id] = key
+		if i == 0 {
+			set.signing = key
+		}
+	}
+	return set, nil
+}
+
+func loadKey(conf configs.JWTKey, signing bool) (*jwtKey, error) {
+	if conf.ID == "" {
+		return nil, errors.New("missing id")
+	}
+	key := &jwtKey{id: conf.ID}
+
+	switch conf.Algorithm {
+	case "HS256", "":
+		if conf.Secret == "" {
+			return nil, errors.New("missing secret")
+		}
+		key.method = jwt.SigningMethodHS256
+		key.private = []byte(conf.Secret)
+		key.public = []byte(conf.Secret)
+		return key, nil
+	case "RS256":
+		key.method = jwt.SigningMethodRS256
+	case "EdDSA":
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s", conf.Algorithm)
+	}
+
+	if conf.PrivateKeyFile != "" {
+		pem, err := ioutil.ReadFile(conf.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if key.method == jwt.SigningMethodRS256 {
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.private, key.public = private, &private.PublicKey
+		} else {
+			private, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			key.private, key.public = private, private.(ed25519.PrivateKey).Public()
+		}
+		return key, nil
+	}
+
+	if signing {
+		return nil, errors.New("signing key needs private_key_file")
+	}
+	if conf.PublicKeyFile == "" {
+		return nil, errors.New("missing public_key_file")
+	}
+	pem, err := ioutil.ReadFile(conf.PublicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if key.method == jwt.SigningMethodRS256 {
+		key.public, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+	} else {
+		key.public, err = jwt.ParseEdPublicKeyFromPEM(pem)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// keyFunc picks the verification key from the kid header and rejects
+// tokens minted for another issuer or audience
+func keyFunc(token *jwt.Token) (interface{}, error) {
+	set, err := getKeySet()
+	if err != nil {
+		return nil, err
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := set.verify[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !claims.VerifyIssuer(set.issuer, true) || !claims.VerifyAudience(set.audience, true) {
+		return nil, errors.New("invalid issuer or audience")
+	}
+	return key.public, nil
+}
+
+// JWKS publishes the asymmetric verification keys so other services can check our tokens,
+// HMAC secrets are never exposed
+func JWKS() (map[string]interface{}, error) {
+	set, err := getKeySet()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for id := range set.verify {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	jwks := []map[string]interface{}{}
+	for _, id := range ids {
+		key := set.verify[id]
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwks = append(jwks, map[string]interface{}{
+				"kty": "RSA",
+				"use": "sig",
+				"alg": key.method.Alg(),
+				"kid": key.id,
+				"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks = append(jwks, map[string]interface{}{
+				"kty": "OKP",
+				"crv": "Ed25519",
+				"use": "sig",
+				"alg": key.method.Alg(),
+				"kid": key.id,
+				"x":   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	return map[string]interface{}{"keys": jwks}, nil
+}
@@ -0,0 +1,144 @@
+package middlewares
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"part3/configs"
+	"part3/models/user"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func writePrivateKey(t *testing.T, dir string, name string, key interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func keyConfig(keys ...configs.JWTKey) *configs.AppConfig {
+	config := &configs.AppConfig{}
+	config.JWT.Issuer = "issuer"
+	config.JWT.Audience = "audience"
+	config.JWT.Keys = keys
+	return config
+}
+
+func callWithToken(token string) (echo.Context, error) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	res := httptest.NewRecorder()
+	context := e.NewContext(req, res)
+	err := JwtMiddleware()(func(c echo.Context) error { return nil })(context)
+	return context, err
+}
+
+func TestKeyRotation(t *testing.T) {
+	defer InitKeys(configs.GetConfig())
+
+	dir := t.TempDir()
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaFile := writePrivateKey(t, dir, "rsa.pem", rsaKey)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edFile := writePrivateKey(t, dir, "ed.pem", edKey)
+
+	oldKey := configs.JWTKey{ID: "old", Algorithm: "HS256", Secret: "old-secret"}
+	rsaConf := configs.JWTKey{ID: "rsa", Algorithm: "RS256", PrivateKeyFile: rsaFile}
+	edConf := configs.JWTKey{ID: "ed", Algorithm: "EdDSA", PrivateKeyFile: edFile}
+	mockUser := user.User{Model: gorm.Model{ID: 7}, Role: user.RoleMember, Password: "anonim123"}
+
+	var oldToken string
+
+	t.Run("token claims", func(t *testing.T) {
+		assert.Nil(t, InitKeys(keyConfig(oldKey)))
+		token, err := GenerateToken(mockUser, 3)
+		assert.Nil(t, err)
+		oldToken = token
+
+		parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+		assert.Nil(t, err)
+		claims := parsed.Claims.(jwt.MapClaims)
+		assert.Equal(t, "old", parsed.Header["kid"])
+		assert.Equal(t, "7", claims["sub"])
+		assert.Equal(t, "issuer", claims["iss"])
+		assert.Equal(t, "audience", claims["aud"])
+		assert.NotNil(t, claims["jti"])
+		assert.Nil(t, claims["password"])
+		assert.Nil(t, claims["email"])
+	})
+
+	t.Run("rotated key keeps old tokens valid", func(t *testing.T) {
+		assert.Nil(t, InitKeys(keyConfig(rsaConf, oldKey)))
+
+		context, err := callWithToken(oldToken)
+		assert.Nil(t, err)
+		assert.Equal(t, 7, ExtractTokenId(context))
+
+		token, err := GenerateToken(mockUser, 3)
+		assert.Nil(t, err)
+		context, err = callWithToken(token)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, int(ExtractTokenSessionId(context)))
+	})
+
+	t.Run("retired key rejects old tokens", func(t *testing.T) {
+		assert.Nil(t, InitKeys(keyConfig(rsaConf)))
+		_, err := callWithToken(oldToken)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("EdDSA key", func(t *testing.T) {
+		assert.Nil(t, InitKeys(keyConfig(edConf)))
+		token, err := GenerateToken(mockUser, 3)
+		assert.Nil(t, err)
+		_, err = callWithToken(token)
+		assert.Nil(t, err)
+	})
+
+	t.Run("wrong audience rejected", func(t *testing.T) {
+		other := keyConfig(oldKey)
+		other.JWT.Audience = "other"
+		assert.Nil(t, InitKeys(other))
+		token, _ := GenerateToken(mockUser, 3)
+
+		assert.Nil(t, InitKeys(keyConfig(oldKey)))
+		_, err := callWithToken(token)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("JWKS publishes asymmetric keys only", func(t *testing.T) {
+		assert.Nil(t, InitKeys(keyConfig(rsaConf, edConf, oldKey)))
+		jwks, err := JWKS()
+		assert.Nil(t, err)
+		keys := jwks["keys"].([]map[string]interface{})
+		assert.Equal(t, 2, len(keys))
+		assert.Equal(t, "ed", keys[0]["kid"])
+		assert.Equal(t, "OKP", keys[0]["kty"])
+		assert.Equal(t, "rsa", keys[1]["kid"])
+		assert.Equal(t, "RSA", keys[1]["kty"])
+	})
+
+	t.Run("invalid key config", func(t *testing.T) {
+		assert.NotNil(t, InitKeys(keyConfig()))
+		assert.NotNil(t, InitKeys(keyConfig(configs.JWTKey{ID: "rsa", Algorithm: "RS256"})))
+		assert.NotNil(t, InitKeys(keyConfig(oldKey, oldKey)))
+	})
+}
@@ -1,50 +1,79 @@
 package middlewares
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"part3/configs"
 	"part3/models/user"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 )
 
-func GenerateToken(u user.User) (string, error) {
+func GenerateToken(u user.User, session_id uint) (string, error) {
 	if u.ID == 0 {
 		return "cannot Generate token", errors.New("id == 0")
 	}
 
+	set, err := getKeySet()
+	if err != nil {
+		return "", err
+	}
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
 	codes := jwt.MapClaims{
-		"id":       u.ID,
-		"email":     u.Email,
-		"password": u.Password,
-		"exp":      time.Now().Add(time.Hour * 1).Unix(),
-		"auth":     true,
+		"sub":  strconv.Itoa(int(u.ID)),
+		"role": u.Role,
+		"sid":  session_id,
+		"iss":  set.issuer,
+		"aud":  set.audience,
+		"jti":  hex.EncodeToString(jti),
+		"iat":  now.Unix(),
+		"exp":  now.Add(configs.ACCESS_TOKEN_TTL).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, codes)
-	// fmt.Println(token)
-	return token.SignedString([]byte(configs.JWT_SECRET))
+	token := jwt.NewWithClaims(set.signing.method, codes)
+	token.Header["kid"] = set.signing.id
+	return token.SignedString(set.signing.private)
 }
 
-func ExtractTokenId(e echo.Context) float64 {
+func ExtractTokenId(e echo.Context) int {
 	user := e.Get("user").(*jwt.Token) //convert to jwt token from interface
 	if user.Valid {
 		codes := user.Claims.(jwt.MapClaims)
-		id := codes["id"].(float64)
+		sub, _ := codes["sub"].(string)
+		id, _ := strconv.Atoi(sub)
 		return id
 	}
 	return 0
 }
 
-func ExtractTokenAdmin(e echo.Context) (result [2]string) {
+func ExtractTokenSessionId(e echo.Context) float64 {
+	user := e.Get("user").(*jwt.Token) //convert to jwt token from interface
+	if user.Valid {
+		codes := user.Claims.(jwt.MapClaims)
+		if sid, ok := codes["sid"].(float64); ok {
+			return sid
+		}
+	}
+	return 0
+}
+
+func ExtractTokenRole(e echo.Context) string {
 	user := e.Get("user").(*jwt.Token) //convert to jwt token from interface
 	if user.Valid {
 		codes := user.Claims.(jwt.MapClaims)
-		result[0] = codes["email"].(string)
-		result[1] = codes["password"].(string)
-		return result
+		if role, ok := codes["role"].(string); ok {
+			return role
+		}
 	}
-	return [2]string{}
+	return ""
 }
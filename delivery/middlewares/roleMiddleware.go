@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"net/http"
+	"part3/models/base"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireRole must be chained after JwtMiddleware, it rejects tokens whose role claim is not listed
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role := ExtractTokenRole(c)
+			for _, r := range roles {
+				if r == role {
+					return next(c)
+				}
+			}
+			return c.JSON(http.StatusForbidden, base.Forbidden(nil, "forbidden access", nil))
+		}
+	}
+}
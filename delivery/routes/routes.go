@@ -1,16 +1,28 @@
 package routes
 
 import (
+	"part3/delivery/controllers/attachment"
 	"part3/delivery/controllers/auth"
+	"part3/delivery/controllers/board"
+	"part3/delivery/controllers/comment"
+	"part3/delivery/controllers/dependency"
+	"part3/delivery/controllers/invitation"
+	"part3/delivery/controllers/label"
+	"part3/delivery/controllers/notification"
 	"part3/delivery/controllers/project"
+	"part3/delivery/controllers/search"
 	"part3/delivery/controllers/task"
 	"part3/delivery/controllers/user"
 	"part3/delivery/middlewares"
+	_user "part3/models/user"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
+// read-only users can browse but not change tasks and projects
+var writeRoles = []string{_user.RoleAdmin, _user.RoleManager, _user.RoleMember}
+
 func UserPath(e *echo.Echo, uc *user.UserController, ac *auth.AuthController) {
 	e.Pre(middleware.RemoveTrailingSlash())
 	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
@@ -19,8 +31,13 @@ func UserPath(e *echo.Echo, uc *user.UserController, ac *auth.AuthController) {
 
 	e.POST("/users", uc.Create())
 	e.POST("/login", ac.Login())
+	e.POST("/token/refresh", ac.Refresh())
+	e.GET("/.well-known/jwks.json", ac.JWKS())
+	e.POST("/logout", ac.Logout(), middlewares.JwtMiddleware())
+	e.POST("/logout/all", ac.LogoutAll(), middlewares.JwtMiddleware())
 	e.GET("/users/me", uc.GetById(), middlewares.JwtMiddleware())
 	e.PUT("/users/me", uc.UpdateById(), middlewares.JwtMiddleware())
+	e.PATCH("/users/me", uc.PatchById(), middlewares.JwtMiddleware())
 	e.DELETE("/users/me", uc.DeleteById(), middlewares.JwtMiddleware())
 }
 
@@ -31,11 +48,23 @@ func TaskPath(e *echo.Echo, tc *task.TaskController) {
 	}))
 
 	// etask := e.Group("/todo",  middlewares.JwtMiddleware())
-	e.POST("/todo/tasks", tc.Create(), middlewares.JwtMiddleware())
+	e.POST("/todo/tasks", tc.Create(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
 	e.GET("/todo/tasks", tc.GetAll(), middlewares.JwtMiddleware())
-	e.PUT("/todo/tasks/:id", tc.Put(), middlewares.JwtMiddleware())
-	e.PUT("/todo/tasks/:id", tc.UpdateStatus(), middlewares.JwtMiddleware())
-	e.DELETE("/todo/tasks/:id", tc.Delete(), middlewares.JwtMiddleware())
+	e.GET("/todo/tasks/agenda", tc.GetAgenda(), middlewares.JwtMiddleware())
+	e.PUT("/todo/tasks/:id", tc.Put(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.PATCH("/todo/tasks/:id", tc.Patch(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.DELETE("/todo/tasks/:id", tc.Delete(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.POST("/todo/tasks/:id/complete", tc.TaskCompleted(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.POST("/todo/tasks/:id/reopen", tc.TaskReopened(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.POST("/todo/tasks/:id/transition", tc.Transition(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.POST("/todo/tasks/:id/move", tc.Move(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.GET("/todo/tasks/:id/assignees", tc.GetAssignees(), middlewares.JwtMiddleware())
+	e.POST("/todo/tasks/:id/assignees", tc.AddAssignees(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.DELETE("/todo/tasks/:id/assignees/:user_id", tc.DeleteAssignee(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.GET("/todo/tasks/:id/checklist", tc.GetChecklist(), middlewares.JwtMiddleware())
+	e.POST("/todo/tasks/:id/checklist", tc.AddChecklistItem(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.PUT("/todo/tasks/:id/checklist/:item_id", tc.PutChecklistItem(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.DELETE("/todo/tasks/:id/checklist/:item_id", tc.DeleteChecklistItem(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
 }
 
 func ProjectPath(e *echo.Echo, pc *project.ProController) {
@@ -44,10 +73,75 @@ func ProjectPath(e *echo.Echo, pc *project.ProController) {
 		Format: "method=${method}, uri=${uri}, status=${status}",
 	}))
 
-	e.POST("/projects", pc.Create(), middlewares.JwtMiddleware())
+	e.POST("/projects", pc.Create(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
 	e.GET("/projects", pc.GetAll(), middlewares.JwtMiddleware())
-	e.PUT("/projects/:id", pc.Put(), middlewares.JwtMiddleware())
-	e.DELETE("/projects/:id", pc.Delete(), middlewares.JwtMiddleware())
+	e.PUT("/projects/:id", pc.Put(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.PATCH("/projects/:id", pc.Patch(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.DELETE("/projects/:id", pc.Delete(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.GET("/projects/:id/workflow", pc.GetWorkflow(), middlewares.JwtMiddleware())
+	e.PUT("/projects/:id/workflow", pc.PutWorkflow(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.GET("/projects/:id/members", pc.GetMembers(), middlewares.JwtMiddleware())
+	e.POST("/projects/:id/members", pc.AddMember(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.PUT("/projects/:id/members/:user_id", pc.PutMember(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.DELETE("/projects/:id/members/:user_id", pc.DeleteMember(), middlewares.JwtMiddleware())
+}
+
+func InvitationPath(e *echo.Echo, ic *invitation.InvitationController) {
+	e.POST("/projects/:id/invitations", ic.Create(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.GET("/projects/:id/invitations", ic.GetAll(), middlewares.JwtMiddleware())
+	e.DELETE("/projects/:id/invitations/:invitation_id", ic.Revoke(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.GET("/invitations", ic.GetMine(), middlewares.JwtMiddleware())
+	e.POST("/invitations/:token/accept", ic.Accept(), middlewares.JwtMiddleware())
+	e.POST("/invitations/:token/decline", ic.Decline())
+}
+
+func CommentPath(e *echo.Echo, cc *comment.CommentController) {
+	e.GET("/todo/tasks/:id/comments", cc.GetAll(), middlewares.JwtMiddleware())
+	e.POST("/todo/tasks/:id/comments", cc.Create(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.PUT("/todo/tasks/:id/comments/:comment_id", cc.Put(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.DELETE("/todo/tasks/:id/comments/:comment_id", cc.Delete(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.GET("/todo/tasks/:id/comments/:comment_id/history", cc.GetHistory(), middlewares.JwtMiddleware())
+}
+
+func AttachmentPath(e *echo.Echo, ac *attachment.AttachmentController) {
+	e.POST("/todo/tasks/:id/attachments", ac.Create(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.GET("/todo/tasks/:id/attachments", ac.GetAll(), middlewares.JwtMiddleware())
+	e.GET("/todo/tasks/:id/attachments/:attachment_id", ac.Download(), middlewares.JwtMiddleware())
+	e.DELETE("/todo/tasks/:id/attachments/:attachment_id", ac.Delete(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+}
+
+func LabelPath(e *echo.Echo, lc *label.LabelController) {
+	e.GET("/projects/:id/labels", lc.GetAll(), middlewares.JwtMiddleware())
+	e.POST("/projects/:id/labels", lc.Create(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.PUT("/projects/:id/labels/:label_id", lc.Put(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.DELETE("/projects/:id/labels/:label_id", lc.Delete(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.POST("/todo/tasks/:id/labels", lc.Attach(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.DELETE("/todo/tasks/:id/labels/:label_id", lc.Detach(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+}
+
+func DependencyPath(e *echo.Echo, dc *dependency.DependencyController) {
+	e.GET("/todo/tasks/:id/dependencies", dc.GetAll(), middlewares.JwtMiddleware())
+	e.POST("/todo/tasks/:id/dependencies", dc.Create(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.DELETE("/todo/tasks/:id/dependencies/:blocker_id", dc.Delete(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.GET("/projects/:id/graph", dc.GetGraph(), middlewares.JwtMiddleware())
+}
+
+func BoardPath(e *echo.Echo, bc *board.BoardController) {
+	e.GET("/projects/:id/board", bc.GetBoard(), middlewares.JwtMiddleware())
+	e.GET("/projects/:id/sections", bc.GetSections(), middlewares.JwtMiddleware())
+	e.POST("/projects/:id/sections", bc.CreateSection(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.PUT("/projects/:id/sections/:section_id", bc.PutSection(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+	e.DELETE("/projects/:id/sections/:section_id", bc.DeleteSection(), middlewares.JwtMiddleware(), middlewares.RequireRole(writeRoles...))
+}
+
+func SearchPath(e *echo.Echo, sc *search.SearchController) {
+	e.GET("/search", sc.Search(), middlewares.JwtMiddleware())
+}
+
+func NotificationPath(e *echo.Echo, nc *notification.NotificationController) {
+	e.GET("/notifications", nc.GetAll(), middlewares.JwtMiddleware())
+	e.POST("/notifications/read", nc.ReadAll(), middlewares.JwtMiddleware())
+	e.POST("/notifications/:id/read", nc.Read(), middlewares.JwtMiddleware())
 }
 
 func AdminPath(e *echo.Echo, uc *user.UserController, ac *auth.AuthController) {
@@ -56,5 +150,6 @@ func AdminPath(e *echo.Echo, uc *user.UserController, ac *auth.AuthController) {
 		Format: "method=${method}, uri=${uri}, status=${status}",
 	}))
 
-	e.GET("/admin/users", uc.GetAll(), middlewares.JwtMiddleware())
+	e.GET("/admin/users", uc.GetAll(), middlewares.JwtMiddleware(), middlewares.RequireRole(_user.RoleAdmin))
+	e.PUT("/admin/users/:id/role", uc.UpdateRole(), middlewares.JwtMiddleware(), middlewares.RequireRole(_user.RoleAdmin))
 }
@@ -0,0 +1,26 @@
+package validation
+
+import (
+	"io"
+	"mime"
+	"part3/lib/mergepatch"
+
+	"github.com/labstack/echo/v4"
+)
+
+const MIMEMergePatch = "application/merge-patch+json"
+
+// BindPatch applies the JSON Merge Patch body of a PATCH request to req, which
+// holds the current state of the resource. A member set to null is cleared
+func BindPatch(c echo.Context, req interface{}) error {
+	media, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil || (media != MIMEMergePatch && media != echo.MIMEApplicationJSON) {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	patch, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	return mergepatch.Merge(req, patch)
+}
@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"part3/models/base"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// Validator is the echo Validator, it checks the validate tags of a request
+// and answers every failing field at once
+type Validator struct {
+	validate *validator.Validate
+}
+
+func New() *Validator {
+	validate := validator.New()
+	// failing fields are named like the client sent them
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+	// bcrypt only reads the first 72 bytes of a password, max counts characters
+	validate.RegisterValidation("maxbytes", func(fl validator.FieldLevel) bool {
+		limit, err := strconv.Atoi(fl.Param())
+		return err == nil && len(fl.Field().String()) <= limit
+	})
+	return &Validator{validate: validate}
+}
+
+// Validate returns base.FieldErrors when the request breaks its tags
+func (v *Validator) Validate(i interface{}) error {
+	err := v.validate.Struct(i)
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return err
+	}
+
+	fields := base.FieldErrors{}
+	for _, fe := range invalid {
+		fields = append(fields, fieldError(fe))
+	}
+	return fields
+}
+
+// fieldError names the broken rule, lengths are counted in characters unless
+// the rule says bytes and in items for lists
+func fieldError(fe validator.FieldError) base.FieldError {
+	field := fe.Field()
+	text := fe.Kind() == reflect.String
+
+	switch fe.Tag() {
+	case "required":
+		return base.FieldError{Field: field, Code: "required", Message: field + " is required"}
+	case "email":
+		return base.FieldError{Field: field, Code: "invalid_email", Message: field + " must be a valid email address"}
+	case "oneof":
+		return base.FieldError{Field: field, Code: "invalid_choice", Message: fmt.Sprintf("%s must be one of %s", field, fe.Param())}
+	case "max", "lte":
+		if text {
+			return base.FieldError{Field: field, Code: "too_long", Message: fmt.Sprintf("%s must be at most %s characters", field, fe.Param())}
+		}
+		return base.FieldError{Field: field, Code: "too_large", Message: fmt.Sprintf("%s must be at most %s", field, fe.Param())}
+	case "maxbytes":
+		return base.FieldError{Field: field, Code: "too_long", Message: fmt.Sprintf("%s must be at most %s bytes", field, fe.Param())}
+	case "min", "gte":
+		if fe.Kind() == reflect.Slice {
+			return base.FieldError{Field: field, Code: "too_few", Message: fmt.Sprintf("%s must have at least %s items", field, fe.Param())}
+		}
+		if text {
+			return base.FieldError{Field: field, Code: "too_short", Message: fmt.Sprintf("%s must be at least %s characters", field, fe.Param())}
+		}
+		return base.FieldError{Field: field, Code: "too_small", Message: fmt.Sprintf("%s must be at least %s", field, fe.Param())}
+	}
+	return base.FieldError{Field: field, Code: "invalid", Message: field + " is invalid"}
+}
+
+// Check validates a bound request, extra are the failing fields the tags
+// cannot check so they are answered together with the others
+func Check(c echo.Context, req interface{}, extra ...base.FieldError) error {
+	fields := base.FieldErrors{}
+	if err := c.Validate(req); err != nil && !errors.As(err, &fields) {
+		return err
+	}
+	fields = append(fields, extra...)
+	if len(fields) > 0 {
+		return fields
+	}
+	return nil
+}
+
+// ParamID reads a path parameter holding an id
+func ParamID(c echo.Context, name string) (int, error) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id <= 0 {
+		return 0, base.FieldErrors{{Field: name, Code: "invalid_id", Message: name + " must be a positive number"}}
+	}
+	return id, nil
+}
@@ -0,0 +1,146 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"part3/models/base"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type signup struct {
+	Name     string  `json:"name" validate:"required,max=5"`
+	Email    string  `json:"email" validate:"omitempty,email"`
+	Age      int     `json:"age" validate:"gte=0,lte=150"`
+	Role     string  `json:"role" validate:"omitempty,oneof=admin member"`
+	Nickname *string `json:"nickname,omitempty" validate:"omitempty,min=2"`
+	Plain    string  `validate:"max=1"`
+	Secret   string  `json:"secret" validate:"maxbytes=4"`
+	Tags     []uint  `json:"tags" validate:"omitempty,min=1"`
+}
+
+func TestValidate(t *testing.T) {
+	v := New()
+
+	t.Run("success run Validate", func(t *testing.T) {
+		assert.Nil(t, v.Validate(&signup{Name: "anna", Email: "anna@example.com", Age: 30, Role: "admin"}))
+	})
+
+	t.Run("fail run Validate", func(t *testing.T) {
+		short := "a"
+		err := v.Validate(&signup{Name: "annabelle", Email: "anna", Age: -1, Role: "owner", Nickname: &short, Plain: "ab"})
+
+		assert.True(t, errors.Is(err, base.ErrValidation))
+		assert.Equal(t, base.FieldErrors{
+			{Field: "name", Code: "too_long", Message: "name must be at most 5 characters"},
+			{Field: "email", Code: "invalid_email", Message: "email must be a valid email address"},
+			{Field: "age", Code: "too_small", Message: "age must be at least 0"},
+			{Field: "role", Code: "invalid_choice", Message: "role must be one of admin member"},
+			{Field: "nickname", Code: "too_short", Message: "nickname must be at least 2 characters"},
+			{Field: "Plain", Code: "too_long", Message: "Plain must be at most 1 characters"},
+		}, err)
+	})
+
+	t.Run("fail run Validate counts characters", func(t *testing.T) {
+		assert.Nil(t, v.Validate(&signup{Name: "ééééé"}))
+		assert.NotNil(t, v.Validate(&signup{Name: strings.Repeat("é", 6)}))
+	})
+
+	t.Run("fail run Validate counts items", func(t *testing.T) {
+		assert.Nil(t, v.Validate(&signup{Name: "anna", Tags: []uint{1}}))
+
+		err := v.Validate(&signup{Name: "anna", Tags: []uint{}})
+		assert.Equal(t, base.FieldErrors{
+			{Field: "tags", Code: "too_few", Message: "tags must have at least 1 items"},
+		}, err)
+	})
+
+	t.Run("fail run Validate counts bytes", func(t *testing.T) {
+		assert.Nil(t, v.Validate(&signup{Name: "anna", Secret: "éé"}))
+
+		err := v.Validate(&signup{Name: "anna", Secret: "ééé"})
+		assert.Equal(t, base.FieldErrors{
+			{Field: "secret", Code: "too_long", Message: "secret must be at most 4 bytes"},
+		}, err)
+	})
+}
+
+func newContext(v *Validator, param string) echo.Context {
+	e := echo.New()
+	e.Validator = v
+	context := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	context.SetParamNames("id")
+	context.SetParamValues(param)
+	return context
+}
+
+func TestCheck(t *testing.T) {
+	extra := base.FieldError{Field: "due_at", Code: "before_start", Message: "due_at must not be before start_at"}
+
+	t.Run("success run Check", func(t *testing.T) {
+		assert.Nil(t, Check(newContext(New(), "1"), &signup{Name: "anna"}))
+	})
+
+	t.Run("fail run Check", func(t *testing.T) {
+		err := Check(newContext(New(), "1"), &signup{}, extra)
+		assert.Equal(t, base.FieldErrors{
+			{Field: "name", Code: "required", Message: "name is required"},
+			extra,
+		}, err)
+
+		err = Check(newContext(New(), "1"), &signup{Name: "anna"}, extra)
+		assert.Equal(t, base.FieldErrors{extra}, err)
+	})
+
+	t.Run("fail run Check without validator", func(t *testing.T) {
+		e := echo.New()
+		context := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		assert.Equal(t, echo.ErrValidatorNotRegistered, Check(context, &signup{Name: "anna"}))
+	})
+}
+
+func TestParamID(t *testing.T) {
+	t.Run("success run ParamID", func(t *testing.T) {
+		id, err := ParamID(newContext(New(), "12"), "id")
+		assert.Nil(t, err)
+		assert.Equal(t, 12, id)
+	})
+
+	t.Run("fail run ParamID", func(t *testing.T) {
+		for _, param := range []string{"", "abc", "0", "-3", "1.5"} {
+			_, err := ParamID(newContext(New(), param), "id")
+			assert.Equal(t, base.FieldErrors{{Field: "id", Code: "invalid_id", Message: "id must be a positive number"}}, err, param)
+		}
+	})
+}
+
+func patchContext(contentType string, body string) echo.Context {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	return e.NewContext(req, httptest.NewRecorder())
+}
+
+func TestBindPatch(t *testing.T) {
+	t.Run("success run BindPatch", func(t *testing.T) {
+		nickname := "ann"
+		for _, contentType := range []string{MIMEMergePatch, echo.MIMEApplicationJSONCharsetUTF8} {
+			req := signup{Name: "anna", Email: "anna@example.com", Age: 30, Nickname: &nickname}
+			err := BindPatch(patchContext(contentType, `{"age":0,"nickname":null}`), &req)
+			assert.Nil(t, err)
+			assert.Equal(t, signup{Name: "anna", Email: "anna@example.com"}, req)
+		}
+	})
+
+	t.Run("fail run BindPatch", func(t *testing.T) {
+		req := signup{Name: "anna"}
+		assert.Equal(t, echo.ErrUnsupportedMediaType, BindPatch(patchContext(echo.MIMEApplicationForm, "age=0"), &req))
+		assert.Equal(t, echo.ErrUnsupportedMediaType, BindPatch(patchContext("", `{"age":0}`), &req))
+		assert.NotNil(t, BindPatch(patchContext(MIMEMergePatch, `{"age":`), &req))
+		assert.Equal(t, signup{Name: "anna"}, req)
+	})
+}
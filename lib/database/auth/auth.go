@@ -1,8 +1,10 @@
 package auth
 
 import (
+	"errors"
 	"part3/models/user"
 	"part3/models/user/request"
+	"part3/utils"
 
 	"gorm.io/gorm"
 )
@@ -19,9 +21,27 @@ func New(db *gorm.DB) *AuthDb {
 
 func (ad *AuthDb) Login(UserLogin request.Userlogin) (user.User, error) {
 	user := user.User{}
-	if err := ad.db.Model(&user).Where("email = ? AND password = ?", UserLogin.Email, UserLogin.Password).First(&user).Error; err != nil {
+	if err := ad.db.Model(&user).Where("email = ?", UserLogin.Email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.CheckMissingPassword(UserLogin.Password)
+		}
 		return user, err
 	}
 
+	if !utils.CheckPassword(user.Password, UserLogin.Password) {
+		return user, gorm.ErrRecordNotFound
+	}
+
+	// legacy rows still hold plaintext, rehash them on the first successful login
+	if !utils.IsHashedPassword(user.Password) {
+		hashed, err := utils.HashPassword(UserLogin.Password)
+		if err != nil {
+			return user, err
+		}
+		if err := ad.db.Model(&user).Update("password", hashed).Error; err != nil {
+			return user, err
+		}
+	}
+
 	return user, nil
 }
@@ -15,8 +15,9 @@ func TestLogin(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run login", func(t *testing.T) {
 		mockUser := user.User{Name: "anonim123", Email: "anonim@123", Password: "anonim123"}
@@ -28,7 +29,7 @@ func TestLogin(t *testing.T) {
 		res, err := repo.Login(mockLogin)
 		assert.Nil(t, err)
 		assert.Equal(t, "anonim@123", res.Email)
-		assert.Equal(t, "anonim123", res.Password)
+		assert.True(t, utils.CheckPassword(res.Password, "anonim123"))
 	})
 
 	t.Run("fail run login", func(t *testing.T) {
@@ -37,4 +38,100 @@ func TestLogin(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 
+	t.Run("fail run login wrong password", func(t *testing.T) {
+		mockLogin := request.Userlogin{Email: "anonim@123", Password: "anonim456"}
+		_, err := repo.Login(mockLogin)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("success run login rehash plaintext", func(t *testing.T) {
+		mockUser := user.User{Name: "anonim789", Email: "anonim@789", Password: "anonim789"}
+		if err := db.Create(&mockUser).Error; err != nil {
+			t.Fail()
+		}
+		mockLogin := request.Userlogin{Email: "anonim@789", Password: "anonim789"}
+		_, err := repo.Login(mockLogin)
+		assert.Nil(t, err)
+
+		stored := user.User{}
+		db.Where("email = ?", "anonim@789").First(&stored)
+		assert.True(t, utils.IsHashedPassword(stored.Password))
+		assert.True(t, utils.CheckPassword(stored.Password, "anonim789"))
+	})
+
+}
+
+func TestSession(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	mockUser := user.User{Name: "anonim123", Email: "anonim@123", Password: "anonim123"}
+	if _, err := _lib.New(db).Create(mockUser); err != nil {
+		t.Fatal()
+	}
+
+	var firstToken, secondToken string
+	var sessionId int
+
+	t.Run("success run CreateSession", func(t *testing.T) {
+		sess, token, err := repo.CreateSession(1)
+		assert.Nil(t, err)
+		assert.NotEqual(t, "", token)
+		assert.True(t, repo.IsSessionActive(int(sess.ID), 1))
+		firstToken = token
+		sessionId = int(sess.ID)
+	})
+
+	t.Run("success run RefreshSession", func(t *testing.T) {
+		res, sess, token, err := repo.RefreshSession(firstToken)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, int(res.ID))
+		assert.Equal(t, sessionId, int(sess.ID))
+		assert.NotEqual(t, firstToken, token)
+		secondToken = token
+	})
+
+	t.Run("fail run RefreshSession reused token", func(t *testing.T) {
+		_, _, _, err := repo.RefreshSession(firstToken)
+		assert.Equal(t, ErrRefreshTokenReused, err)
+		assert.False(t, repo.IsSessionActive(sessionId, 1))
+
+		_, _, _, err = repo.RefreshSession(secondToken)
+		assert.Equal(t, ErrInvalidRefreshToken, err)
+	})
+
+	t.Run("fail run RefreshSession unknown token", func(t *testing.T) {
+		_, _, _, err := repo.RefreshSession("unknown")
+		assert.Equal(t, ErrInvalidRefreshToken, err)
+	})
+
+	t.Run("success run RevokeSession", func(t *testing.T) {
+		sess, _, err := repo.CreateSession(1)
+		if err != nil {
+			t.Fatal()
+		}
+		assert.Nil(t, repo.RevokeSession(int(sess.ID), 1))
+		assert.False(t, repo.IsSessionActive(int(sess.ID), 1))
+		assert.NotNil(t, repo.RevokeSession(int(sess.ID), 1))
+	})
+
+	t.Run("success run RevokeAllSessions", func(t *testing.T) {
+		sessA, _, _ := repo.CreateSession(1)
+		sessB, _, _ := repo.CreateSession(1)
+		assert.Nil(t, repo.RevokeAllSessions(1))
+		assert.False(t, repo.IsSessionActive(int(sessA.ID), 1))
+		assert.False(t, repo.IsSessionActive(int(sessB.ID), 1))
+	})
+
+	t.Run("deleted user session inactive", func(t *testing.T) {
+		sess, _, _ := repo.CreateSession(1)
+		if _, err := _lib.New(db).DeleteById(1); err != nil {
+			t.Fatal()
+		}
+		assert.False(t, repo.IsSessionActive(int(sess.ID), 1))
+	})
 }
@@ -1,10 +1,16 @@
 package auth
 
 import (
+	"part3/models/session"
 	"part3/models/user"
 	"part3/models/user/request"
 )
 
 type Auth interface {
 	Login(UserLogin request.Userlogin) (user.User, error)
+	CreateSession(user_id int) (session.Session, string, error)
+	RefreshSession(refreshToken string) (user.User, session.Session, string, error)
+	RevokeSession(session_id int, user_id int) error
+	RevokeAllSessions(user_id int) error
+	IsSessionActive(session_id int, user_id int) bool
 }
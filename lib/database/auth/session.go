@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"part3/configs"
+	"part3/models/session"
+	"part3/models/user"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+)
+
+func newRefreshToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func issueRefreshToken(tx *gorm.DB, sess session.Session) (string, error) {
+	token, hash, err := newRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	refresh := session.RefreshToken{
+		Session_ID: sess.ID,
+		TokenHash:  hash,
+		ExpiresAt:  sess.ExpiresAt,
+	}
+	if err := tx.Create(&refresh).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (ad *AuthDb) CreateSession(user_id int) (session.Session, string, error) {
+	sess := session.Session{
+		User_ID:   uint(user_id),
+		ExpiresAt: time.Now().Add(configs.REFRESH_TOKEN_TTL),
+	}
+	token := ""
+
+	err := ad.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&sess).Error; err != nil {
+			return err
+		}
+		var err error
+		token, err = issueRefreshToken(tx, sess)
+		return err
+	})
+	if err != nil {
+		return session.Session{}, "", err
+	}
+
+	return sess, token, nil
+}
+
+// RefreshSession rotates the refresh token, presenting an already rotated
+// token revokes the whole session since it may have been stolen
+func (ad *AuthDb) RefreshSession(refreshToken string) (user.User, session.Session, string, error) {
+	usr := user.User{}
+	sess := session.Session{}
+	token := ""
+	reused := false
+
+	err := ad.db.Transaction(func(tx *gorm.DB) error {
+		refresh := session.RefreshToken{}
+		if err := tx.Where("token_hash = ?", hashRefreshToken(refreshToken)).First(&refresh).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+		if err := tx.First(&sess, refresh.Session_ID).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		now := time.Now()
+		if refresh.UsedAt != nil {
+			reused = true
+			return tx.Model(&sess).Where("revoked_at IS NULL").Update("revoked_at", now).Error
+		}
+		if sess.RevokedAt != nil || now.After(refresh.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+		if err := tx.First(&usr, sess.User_ID).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		res := tx.Model(&refresh).Where("used_at IS NULL").Update("used_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+
+		var err error
+		token, err = issueRefreshToken(tx, sess)
+		return err
+	})
+	if reused {
+		return user.User{}, session.Session{}, "", ErrRefreshTokenReused
+	}
+	if err != nil {
+		return user.User{}, session.Session{}, "", err
+	}
+
+	return usr, sess, token, nil
+}
+
+func (ad *AuthDb) RevokeSession(session_id int, user_id int) error {
+	res := ad.db.Model(&session.Session{}).Where("id = ? AND user_id = ? AND revoked_at IS NULL", session_id, user_id).Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (ad *AuthDb) RevokeAllSessions(user_id int) error {
+	return ad.db.Model(&session.Session{}).Where("user_id = ? AND revoked_at IS NULL", user_id).Update("revoked_at", time.Now()).Error
+}
+
+func (ad *AuthDb) IsSessionActive(session_id int, user_id int) bool {
+	var count int64
+	ad.db.Model(&session.Session{}).
+		Joins("inner join users on users.id = sessions.user_id AND users.deleted_at IS NULL").
+		Where("sessions.id = ? AND sessions.user_id = ? AND sessions.revoked_at IS NULL AND sessions.expires_at > ?", session_id, user_id, time.Now()).
+		Count(&count)
+	return count > 0
+}
@@ -0,0 +1,253 @@
+package comment
+
+import (
+	"part3/lib/database/project"
+	"part3/models/base"
+	"part3/models/comment"
+	"part3/models/comment/response"
+	"part3/models/notification"
+	_project "part3/models/project"
+	"part3/models/task"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrCommentNotFound = base.NotFoundError("comment_not_found", "task or comment not found")
+
+const commentSelect = "comments.id as id, comments.created_at as created_at, comments.updated_at as updated_at, comments.edited_at as edited_at, comments.task_id as task_id, comments.user_id as user_id, users.name as user_name, comments.body as body"
+
+type CommentDb struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *CommentDb {
+	return &CommentDb{db: db}
+}
+
+// member loads the task and checks the user belongs to its project, any role may discuss
+func member(db *gorm.DB, task_id int, user_id int) (task.Task, _project.Member, error) {
+	found := task.Task{}
+	if err := db.Where("id = ?", task_id).First(&found).Error; err != nil {
+		return found, _project.Member{}, base.NotFoundAs(err, ErrCommentNotFound)
+	}
+	member, err := project.Authorize(db, found.Project_id, user_id, _project.MemberRoles...)
+	return found, member, base.NotFoundAs(err, ErrCommentNotFound)
+}
+
+func (cd *CommentDb) GetAll(task_id int, user_id int) ([]response.CommentResponse, error) {
+	if _, _, err := member(cd.db, task_id, user_id); err != nil {
+		return nil, err
+	}
+
+	comments := []response.CommentResponse{}
+	if err := cd.find(cd.db.Where("comments.task_id = ?", task_id), &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (cd *CommentDb) GetById(task_id int, id int, user_id int) (response.CommentResponse, error) {
+	if _, _, err := member(cd.db, task_id, user_id); err != nil {
+		return response.CommentResponse{}, err
+	}
+
+	comments := []response.CommentResponse{}
+	if err := cd.find(cd.db.Where("comments.task_id = ? AND comments.id = ?", task_id, id), &comments); err != nil {
+		return response.CommentResponse{}, err
+	}
+	if len(comments) == 0 {
+		return response.CommentResponse{}, ErrCommentNotFound
+	}
+	return comments[0], nil
+}
+
+// find loads the comments with their mentions, one query for all the mentions
+func (cd *CommentDb) find(query *gorm.DB, comments *[]response.CommentResponse) error {
+	err := query.Model(&comment.Comment{}).
+		Select(commentSelect).
+		Joins("inner join users on users.id = comments.user_id").
+		Order("comments.id").
+		Find(comments).Error
+	if err != nil || len(*comments) == 0 {
+		return err
+	}
+
+	ids := []uint{}
+	index := map[uint]int{}
+	for i := range *comments {
+		(*comments)[i].Mentions = []response.MentionResponse{}
+		ids = append(ids, (*comments)[i].Id)
+		index[(*comments)[i].Id] = i
+	}
+
+	rows := []struct {
+		Comment_id uint
+		response.MentionResponse
+	}{}
+	err = cd.db.Model(&comment.Mention{}).
+		Select("comment_mentions.comment_id as comment_id, users.id as user_id, users.name as name").
+		Joins("inner join users on users.id = comment_mentions.user_id AND users.deleted_at IS NULL").
+		Where("comment_mentions.comment_id IN ?", ids).
+		Order("comment_mentions.id").
+		Find(&rows).Error
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		i := index[row.Comment_id]
+		(*comments)[i].Mentions = append((*comments)[i].Mentions, row.MentionResponse)
+	}
+	return nil
+}
+
+// mention resolves the @handles of the body against the project members and
+// records them, only members not already mentioned in the comment are notified
+func mention(tx *gorm.DB, found task.Task, c comment.Comment, author int) error {
+	handles := comment.Mentions(c.Body)
+	if len(handles) == 0 {
+		return nil
+	}
+
+	members := []struct {
+		User_id uint
+		Name    string
+		Email   string
+	}{}
+	err := tx.Model(&_project.Member{}).
+		Select("users.id as user_id, users.name as name, users.email as email").
+		Joins("inner join users on users.id = project_members.user_id AND users.deleted_at IS NULL").
+		Where("project_members.project_id = ?", found.Project_id).
+		Order("project_members.id").
+		Find(&members).Error
+	if err != nil {
+		return err
+	}
+
+	wanted := map[string]bool{}
+	for _, handle := range handles {
+		wanted[handle] = true
+	}
+	for _, m := range members {
+		named := false
+		for _, handle := range comment.Handles(m.Name, m.Email) {
+			named = named || wanted[handle]
+		}
+		if !named {
+			continue
+		}
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&comment.Mention{Comment_ID: c.ID, User_ID: m.User_id})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 || int(m.User_id) == author {
+			continue
+		}
+		comment_id := c.ID
+		err := tx.Create(&notification.Notification{
+			User_ID:    m.User_id,
+			Kind:       notification.KindMention,
+			Actor_ID:   uint(author),
+			Task_ID:    found.ID,
+			Comment_ID: &comment_id,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cd *CommentDb) Create(task_id int, user_id int, body string) (response.CommentResponse, error) {
+	newComment := comment.Comment{Task_ID: uint(task_id), User_ID: uint(user_id), Body: body}
+
+	err := cd.db.Transaction(func(tx *gorm.DB) error {
+		found, _, err := member(tx, task_id, user_id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&newComment).Error; err != nil {
+			return err
+		}
+		return mention(tx, found, newComment, user_id)
+	})
+	if err != nil {
+		return response.CommentResponse{}, err
+	}
+
+	return cd.GetById(task_id, int(newComment.ID), user_id)
+}
+
+// UpdateById is for the author only, the replaced body goes to the history
+func (cd *CommentDb) UpdateById(task_id int, id int, user_id int, body string) (response.CommentResponse, error) {
+	err := cd.db.Transaction(func(tx *gorm.DB) error {
+		found, _, err := member(tx, task_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		upComment := comment.Comment{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND task_id = ?", id, task_id).First(&upComment).Error; err != nil {
+			return base.NotFoundAs(err, ErrCommentNotFound)
+		}
+		if upComment.User_ID != uint(user_id) {
+			return project.ErrForbidden
+		}
+		if upComment.Body == body {
+			return nil
+		}
+
+		if err := tx.Create(&comment.Revision{Comment_ID: upComment.ID, Body: upComment.Body, EditedBy: uint(user_id)}).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		upComment.Body = body
+		upComment.EditedAt = &now
+		if err := tx.Model(&upComment).Updates(map[string]interface{}{"body": body, "edited_at": &now}).Error; err != nil {
+			return err
+		}
+		return mention(tx, found, upComment, user_id)
+	})
+	if err != nil {
+		return response.CommentResponse{}, err
+	}
+
+	return cd.GetById(task_id, id, user_id)
+}
+
+// DeleteById lets the author or a project owner remove a comment
+func (cd *CommentDb) DeleteById(task_id int, id int, user_id int) error {
+	_, role, err := member(cd.db, task_id, user_id)
+	if err != nil {
+		return err
+	}
+
+	found := comment.Comment{}
+	if err := cd.db.Where("id = ? AND task_id = ?", id, task_id).First(&found).Error; err != nil {
+		return base.NotFoundAs(err, ErrCommentNotFound)
+	}
+	if found.User_ID != uint(user_id) && role.Role != _project.MemberOwner {
+		return project.ErrForbidden
+	}
+
+	return cd.db.Delete(&found).Error
+}
+
+func (cd *CommentDb) GetHistory(task_id int, id int, user_id int) ([]response.RevisionResponse, error) {
+	if _, err := cd.GetById(task_id, id, user_id); err != nil {
+		return nil, err
+	}
+
+	revisions := []comment.Revision{}
+	if err := cd.db.Where("comment_id = ?", id).Order("id").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	history := []response.RevisionResponse{}
+	for _, revision := range revisions {
+		history = append(history, revision.ToRevisionResponse())
+	}
+	return history, nil
+}
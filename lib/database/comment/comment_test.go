@@ -0,0 +1,95 @@
+package comment
+
+import (
+	"part3/configs"
+	_libNotification "part3/lib/database/notification"
+	_libPro "part3/lib/database/project"
+	_libTask "part3/lib/database/task"
+	_lib "part3/lib/database/user"
+	"part3/models/notification"
+	"part3/models/project"
+	"part3/models/task"
+	"part3/models/user"
+	"part3/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComments(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mocUserP := range []user.User{
+		{Name: "anonim one", Email: "anonim@1", Password: "anonim1"},
+		{Name: "anonim two", Email: "anonim2@2", Password: "anonim2"},
+		{Name: "anonim three", Email: "anonim3@3", Password: "anonim3"},
+	} {
+		if _, err := _lib.New(db).Create(mocUserP); err != nil {
+			t.Fatal()
+		}
+	}
+	if _, err := _libPro.New(db).Create(1, project.Project{Name: "Proanonim"}); err != nil {
+		t.Fatal()
+	}
+	if _, err := _libPro.New(db).AddMember(1, 1, project.Member{User_ID: 2, Role: project.MemberViewer}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := _libTask.New(db).Create(1, task.Task{Name: "Taskanonim", Priority: 1, Project_id: 1}); err != nil {
+		t.Fatal()
+	}
+
+	t.Run("success run Create with mentions", func(t *testing.T) {
+		res, err := repo.Create(1, 1, "@anonimtwo and @anonim3 please look, cc @anonim@1")
+		assert.Nil(t, err)
+		assert.Equal(t, "anonim one", res.User_name)
+		assert.Equal(t, 2, len(res.Mentions))
+
+		unread, err := _libNotification.New(db).GetAll(2, true)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(unread))
+		assert.Equal(t, notification.KindMention, unread[0].Kind)
+
+		mine, err := _libNotification.New(db).GetAll(1, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(mine))
+	})
+
+	t.Run("fail run Create not a member", func(t *testing.T) {
+		_, err := repo.Create(1, 3, "hello")
+		assert.Equal(t, ErrCommentNotFound, err)
+	})
+
+	t.Run("success run UpdateById keeps history", func(t *testing.T) {
+		_, err := repo.UpdateById(1, 1, 2, "hijack")
+		assert.Equal(t, _libPro.ErrForbidden, err)
+
+		res, err := repo.UpdateById(1, 1, 1, "@anonim2 please look")
+		assert.Nil(t, err)
+		assert.NotNil(t, res.Edited_at)
+
+		history, err := repo.GetHistory(1, 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(history))
+		assert.Equal(t, "@anonimtwo and @anonim3 please look, cc @anonim@1", history[0].Body)
+
+		// anonim2 was already mentioned, editing does not notify again
+		unread, err := _libNotification.New(db).GetAll(2, true)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(unread))
+		assert.Nil(t, _libNotification.New(db).MarkAllRead(2))
+	})
+
+	t.Run("success run DeleteById", func(t *testing.T) {
+		assert.Equal(t, _libPro.ErrForbidden, repo.DeleteById(1, 1, 2))
+		assert.Nil(t, repo.DeleteById(1, 1, 1))
+
+		res, err := repo.GetAll(1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+	})
+}
@@ -0,0 +1,11 @@
+package comment
+
+import "part3/models/comment/response"
+
+type Comment interface {
+	GetAll(task_id int, user_id int) ([]response.CommentResponse, error)
+	Create(task_id int, user_id int, body string) (response.CommentResponse, error)
+	UpdateById(task_id int, id int, user_id int, body string) (response.CommentResponse, error)
+	DeleteById(task_id int, id int, user_id int) error
+	GetHistory(task_id int, id int, user_id int) ([]response.RevisionResponse, error)
+}
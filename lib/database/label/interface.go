@@ -0,0 +1,15 @@
+package label
+
+import (
+	"part3/models/label"
+	"part3/models/label/response"
+)
+
+type Label interface {
+	GetAll(project_id int, user_id int) ([]response.LabelResponse, error)
+	Create(project_id int, user_id int, newLabel label.Label) (label.Label, error)
+	UpdateById(project_id int, id int, user_id int, upLabel label.Label) (label.Label, error)
+	DeleteById(project_id int, id int, user_id int) error
+	Attach(task_id int, user_id int, label_ids []uint) ([]response.LabelResponse, error)
+	Detach(task_id int, label_id int, user_id int) error
+}
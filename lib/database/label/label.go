@@ -0,0 +1,199 @@
+package label
+
+import (
+	"part3/lib/database/project"
+	"part3/models/base"
+	"part3/models/label"
+	"part3/models/label/response"
+	_project "part3/models/project"
+	"part3/models/task"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrLabelExists   = base.ConflictError("label_exists", "label already exists")
+	ErrInvalidLabel  = base.ValidationError("invalid_label", "label must belong to the task's project")
+	ErrLabelNotFound = base.NotFoundError("label_not_found", "task or label not found")
+)
+
+type LabelDb struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *LabelDb {
+	return &LabelDb{db: db}
+}
+
+func (ld *LabelDb) GetAll(project_id int, user_id int) ([]response.LabelResponse, error) {
+	if _, err := project.Authorize(ld.db, uint(project_id), user_id, _project.MemberRoles...); err != nil {
+		return nil, base.NotFoundAs(err, ErrLabelNotFound)
+	}
+
+	rows := []label.Label{}
+	if err := ld.db.Where("project_id = ?", project_id).Order("name").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	labels := []response.LabelResponse{}
+	for _, row := range rows {
+		labels = append(labels, row.ToLabelResponse())
+	}
+	return labels, nil
+}
+
+// taken reports whether another label of the project has the name, whatever the case
+func taken(db *gorm.DB, project_id int, id int, name string) error {
+	var count int64
+	err := db.Model(&label.Label{}).Where("project_id = ? AND id <> ? AND LOWER(name) = ?", project_id, id, strings.ToLower(name)).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrLabelExists
+	}
+	return nil
+}
+
+func (ld *LabelDb) Create(project_id int, user_id int, newLabel label.Label) (label.Label, error) {
+	newLabel.Project_ID = uint(project_id)
+
+	err := ld.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := project.Authorize(tx, uint(project_id), user_id, _project.EditRoles...); err != nil {
+			return base.NotFoundAs(err, ErrLabelNotFound)
+		}
+		if err := taken(tx, project_id, 0, newLabel.Name); err != nil {
+			return err
+		}
+		return tx.Create(&newLabel).Error
+	})
+	if err != nil {
+		return label.Label{}, err
+	}
+	return newLabel, nil
+}
+
+// UpdateById changes the name and colour, empty values keep the current ones
+func (ld *LabelDb) UpdateById(project_id int, id int, user_id int, upLabel label.Label) (label.Label, error) {
+	found := label.Label{}
+
+	err := ld.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := project.Authorize(tx, uint(project_id), user_id, _project.EditRoles...); err != nil {
+			return base.NotFoundAs(err, ErrLabelNotFound)
+		}
+		if err := tx.Where("id = ? AND project_id = ?", id, project_id).First(&found).Error; err != nil {
+			return base.NotFoundAs(err, ErrLabelNotFound)
+		}
+		if upLabel.Name != "" {
+			if err := taken(tx, project_id, id, upLabel.Name); err != nil {
+				return err
+			}
+			found.Name = upLabel.Name
+		}
+		if upLabel.Colour != "" {
+			found.Colour = upLabel.Colour
+		}
+		return tx.Model(&found).Updates(map[string]interface{}{"name": found.Name, "colour": found.Colour}).Error
+	})
+	if err != nil {
+		return label.Label{}, err
+	}
+	return found, nil
+}
+
+// DeleteById also takes the label off every task
+func (ld *LabelDb) DeleteById(project_id int, id int, user_id int) error {
+	return ld.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := project.Authorize(tx, uint(project_id), user_id, _project.EditRoles...); err != nil {
+			return base.NotFoundAs(err, ErrLabelNotFound)
+		}
+		found := label.Label{}
+		if err := tx.Where("id = ? AND project_id = ?", id, project_id).First(&found).Error; err != nil {
+			return base.NotFoundAs(err, ErrLabelNotFound)
+		}
+		if err := tx.Where("label_id = ?", found.ID).Delete(&label.TaskLabel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&found).Error
+	})
+}
+
+// editable loads the task and checks the user may change it
+func editable(db *gorm.DB, task_id int, user_id int) (task.Task, error) {
+	found := task.Task{}
+	if err := db.Where("id = ?", task_id).First(&found).Error; err != nil {
+		return found, base.NotFoundAs(err, ErrLabelNotFound)
+	}
+	if _, err := project.Authorize(db, found.Project_id, user_id, _project.EditRoles...); err != nil {
+		return found, base.NotFoundAs(err, ErrLabelNotFound)
+	}
+	return found, nil
+}
+
+// Attach puts the labels on the task, labels already there are kept as they are
+func (ld *LabelDb) Attach(task_id int, user_id int, label_ids []uint) ([]response.LabelResponse, error) {
+	labels := []response.LabelResponse{}
+
+	err := ld.db.Transaction(func(tx *gorm.DB) error {
+		found, err := editable(tx, task_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&label.Label{}).Where("id IN ? AND project_id = ?", label_ids, found.Project_id).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) != len(uniqueIds(label_ids)) {
+			return ErrInvalidLabel
+		}
+
+		rows := []label.TaskLabel{}
+		for _, label_id := range uniqueIds(label_ids) {
+			rows = append(rows, label.TaskLabel{Task_ID: found.ID, Label_ID: label_id})
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&label.Label{}).
+			Select("labels.id as id, labels.name as name, labels.colour as colour").
+			Joins("inner join task_labels on task_labels.label_id = labels.id").
+			Where("task_labels.task_id = ?", found.ID).
+			Order("labels.name").
+			Find(&labels).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (ld *LabelDb) Detach(task_id int, label_id int, user_id int) error {
+	if _, err := editable(ld.db, task_id, user_id); err != nil {
+		return err
+	}
+
+	res := ld.db.Where("task_id = ? AND label_id = ?", task_id, label_id).Delete(&label.TaskLabel{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrLabelNotFound
+	}
+	return nil
+}
+
+func uniqueIds(ids []uint) []uint {
+	unique := []uint{}
+	seen := map[uint]bool{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
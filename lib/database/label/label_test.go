@@ -0,0 +1,129 @@
+package label
+
+import (
+	"part3/configs"
+	_libPro "part3/lib/database/project"
+	_libTask "part3/lib/database/task"
+	_lib "part3/lib/database/user"
+	"part3/models/base"
+	"part3/models/label"
+	"part3/models/project"
+	"part3/models/task"
+	"part3/models/task/request"
+	"part3/models/user"
+	"part3/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabels(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mocUserP := range []user.User{
+		{Name: "anonim1", Email: "anonim@1", Password: "anonim1"},
+		{Name: "anonim2", Email: "anonim@2", Password: "anonim2"},
+	} {
+		if _, err := _lib.New(db).Create(mocUserP); err != nil {
+			t.Fatal()
+		}
+	}
+	for _, name := range []string{"Proanonim1", "Proanonim2"} {
+		if _, err := _libPro.New(db).Create(1, project.Project{Name: name}); err != nil {
+			t.Fatal()
+		}
+	}
+	if _, err := _libPro.New(db).AddMember(1, 1, project.Member{User_ID: 2, Role: project.MemberViewer}); err != nil {
+		t.Fatal(err)
+	}
+	tasks := _libTask.New(db)
+	for _, name := range []string{"Taskanonim1", "Taskanonim2"} {
+		if _, err := tasks.Create(1, task.Task{Name: name, Priority: 1, Project_id: 1}); err != nil {
+			t.Fatal()
+		}
+	}
+
+	t.Run("success run Create", func(t *testing.T) {
+		for _, name := range []string{"bug", "urgent"} {
+			_, err := repo.Create(1, 1, label.Label{Name: name, Colour: label.DefaultColour})
+			assert.Nil(t, err)
+		}
+		_, err := repo.Create(2, 1, label.Label{Name: "bug", Colour: label.DefaultColour})
+		assert.Nil(t, err)
+	})
+
+	t.Run("fail run Create", func(t *testing.T) {
+		_, err := repo.Create(1, 1, label.Label{Name: "BUG", Colour: label.DefaultColour})
+		assert.Equal(t, ErrLabelExists, err)
+
+		_, err = repo.Create(1, 2, label.Label{Name: "docs", Colour: label.DefaultColour})
+		assert.Equal(t, _libPro.ErrForbidden, err)
+	})
+
+	t.Run("success run GetAll", func(t *testing.T) {
+		res, err := repo.GetAll(1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.Equal(t, "bug", res[0].Name)
+	})
+
+	t.Run("success run UpdateById", func(t *testing.T) {
+		res, err := repo.UpdateById(1, 2, 1, label.Label{Colour: "#ff0000"})
+		assert.Nil(t, err)
+		assert.Equal(t, "urgent", res.Name)
+		assert.Equal(t, "#ff0000", res.Colour)
+
+		_, err = repo.UpdateById(1, 2, 1, label.Label{Name: "Bug"})
+		assert.Equal(t, ErrLabelExists, err)
+	})
+
+	t.Run("success run Attach", func(t *testing.T) {
+		res, err := repo.Attach(1, 1, []uint{1, 2, 1})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+
+		res, err = repo.Attach(2, 1, []uint{1})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+	})
+
+	t.Run("fail run Attach", func(t *testing.T) {
+		_, err := repo.Attach(1, 1, []uint{3})
+		assert.Equal(t, ErrInvalidLabel, err)
+
+		_, err = repo.Attach(1, 2, []uint{1})
+		assert.Equal(t, _libPro.ErrForbidden, err)
+	})
+
+	t.Run("success run GetAll tasks by label", func(t *testing.T) {
+		list, meta, err := tasks.GetAll(1, request.TaskFilter{Labels: []string{"Bug", "urgent"}, LabelMode: request.LabelModeAny}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, int(meta.Total))
+		assert.Equal(t, 2, len(list))
+
+		list, meta, err = tasks.GetAll(1, request.TaskFilter{Labels: []string{"bug", "urgent"}, LabelMode: request.LabelModeAll}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, int(meta.Total))
+		assert.Equal(t, "Taskanonim1", list[0].Name)
+		assert.Equal(t, 2, len(list[0].Labels))
+	})
+
+	t.Run("success run Detach", func(t *testing.T) {
+		assert.Nil(t, repo.Detach(1, 2, 1))
+		assert.Equal(t, ErrLabelNotFound, repo.Detach(1, 2, 1))
+	})
+
+	t.Run("success run DeleteById", func(t *testing.T) {
+		assert.Nil(t, repo.DeleteById(1, 1, 1))
+		assert.Equal(t, ErrLabelNotFound, repo.DeleteById(1, 1, 1))
+
+		var count int64
+		db.Model(&label.TaskLabel{}).Count(&count)
+		assert.Equal(t, 0, int(count))
+	})
+}
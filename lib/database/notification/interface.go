@@ -0,0 +1,9 @@
+package notification
+
+import "part3/models/notification/response"
+
+type Notification interface {
+	GetAll(user_id int, unread bool) ([]response.NotificationResponse, error)
+	MarkRead(id int, user_id int) error
+	MarkAllRead(user_id int) error
+}
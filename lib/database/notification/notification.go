@@ -0,0 +1,51 @@
+package notification
+
+import (
+	"part3/models/notification"
+	"part3/models/notification/response"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationDb struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *NotificationDb {
+	return &NotificationDb{db: db}
+}
+
+// GetAll lists the newest notifications of the user first, unread keeps the ones not read yet
+func (nd *NotificationDb) GetAll(user_id int, unread bool) ([]response.NotificationResponse, error) {
+	query := nd.db.Where("user_id = ?", user_id)
+	if unread {
+		query = query.Where("read_at IS NULL")
+	}
+
+	rows := []notification.Notification{}
+	if err := query.Order("id desc").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	notifications := []response.NotificationResponse{}
+	for _, row := range rows {
+		notifications = append(notifications, row.ToNotificationResponse())
+	}
+	return notifications, nil
+}
+
+func (nd *NotificationDb) MarkRead(id int, user_id int) error {
+	found := notification.Notification{}
+	if err := nd.db.Where("id = ? AND user_id = ?", id, user_id).First(&found).Error; err != nil {
+		return err
+	}
+	if found.ReadAt != nil {
+		return nil
+	}
+	return nd.db.Model(&found).Update("read_at", time.Now()).Error
+}
+
+func (nd *NotificationDb) MarkAllRead(user_id int) error {
+	return nd.db.Model(&notification.Notification{}).Where("user_id = ? AND read_at IS NULL", user_id).Update("read_at", time.Now()).Error
+}
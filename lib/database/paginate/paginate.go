@@ -0,0 +1,206 @@
+package paginate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"part3/models/base"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidSort   = base.ValidationError("invalid_sort", "invalid sort field")
+	ErrInvalidCursor = base.ValidationError("invalid_cursor", "invalid cursor")
+)
+
+// Column maps a public sort name to its sql expression and the response field holding its value,
+// nullable columns can be sorted on but only with offset pagination
+type Column struct {
+	Expr     string
+	Field    string
+	Nullable bool
+}
+
+// Columns must always hold an "id" entry, it is the tie breaker of every sort
+type Columns map[string]Column
+
+type cursor struct {
+	Values []json.RawMessage `json:"v"`
+	Before bool              `json:"b,omitempty"`
+}
+
+// Find runs the filtered query one page at a time into dest (a pointer to a slice of rows),
+// the total is counted on the same filters before the window is applied
+func Find(query *gorm.DB, sel string, page base.Page, columns Columns, dest interface{}) (base.Meta, error) {
+	meta := base.Meta{Limit: page.Limit, Offset: page.Offset}
+
+	sorts, keyset, err := resolve(page.Sort, columns)
+	if err != nil {
+		return meta, err
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&meta.Total).Error; err != nil {
+		return meta, err
+	}
+
+	find := query.Session(&gorm.Session{}).Select(sel)
+	rowType := reflect.TypeOf(dest).Elem().Elem()
+
+	before := false
+	if page.Cursor != "" {
+		if !keyset {
+			return meta, ErrInvalidCursor
+		}
+		cur, values, err := decode(page.Cursor, sorts, columns, rowType)
+		if err != nil {
+			return meta, err
+		}
+		before = cur.Before
+		sql, vars := after(sorts, columns, values, before)
+		find = find.Where(sql, vars...)
+		meta.Offset = 0
+	} else {
+		find = find.Offset(page.Offset)
+	}
+
+	for _, sort := range sorts {
+		find = find.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: columns[sort.Field].Expr, Raw: true},
+			Desc:   sort.Desc != before,
+		})
+	}
+
+	if err := find.Limit(page.Limit + 1).Find(dest).Error; err != nil {
+		return meta, err
+	}
+
+	rows := reflect.ValueOf(dest).Elem()
+	more := rows.Len() > page.Limit
+	if more {
+		rows.Set(rows.Slice(0, page.Limit))
+	}
+	if before {
+		for i, j := 0, rows.Len()-1; i < j; i, j = i+1, j-1 {
+			first, last := rows.Index(i).Interface(), rows.Index(j).Interface()
+			rows.Index(i).Set(reflect.ValueOf(last))
+			rows.Index(j).Set(reflect.ValueOf(first))
+		}
+	}
+
+	if keyset && rows.Len() > 0 {
+		hasNext, hasPrev := more, page.Cursor != "" || page.Offset > 0
+		if before {
+			hasNext, hasPrev = true, more
+		}
+		if hasNext {
+			if meta.NextCursor, err = encode(rows.Index(rows.Len()-1), sorts, columns, false); err != nil {
+				return meta, err
+			}
+		}
+		if hasPrev {
+			if meta.PrevCursor, err = encode(rows.Index(0), sorts, columns, true); err != nil {
+				return meta, err
+			}
+		}
+	}
+
+	return meta, nil
+}
+
+func resolve(sorts []base.Sort, columns Columns) ([]base.Sort, bool, error) {
+	resolved := []base.Sort{}
+	keyset := true
+	hasId := false
+
+	for _, sort := range sorts {
+		column, ok := columns[sort.Field]
+		if !ok {
+			return nil, false, ErrInvalidSort
+		}
+		keyset = keyset && !column.Nullable
+		hasId = hasId || sort.Field == "id"
+		resolved = append(resolved, sort)
+	}
+	if !hasId {
+		resolved = append(resolved, base.Sort{Field: "id"})
+	}
+
+	return resolved, keyset, nil
+}
+
+// after builds the keyset condition (a > ?) OR (a = ? AND b > ?) ... for the sort fields
+func after(sorts []base.Sort, columns Columns, values []interface{}, before bool) (string, []interface{}) {
+	ors := []string{}
+	vars := []interface{}{}
+
+	for i, sort := range sorts {
+		ands := []string{}
+		for j := 0; j < i; j++ {
+			ands = append(ands, columns[sorts[j].Field].Expr+" = ?")
+			vars = append(vars, values[j])
+		}
+		op := " > ?"
+		if sort.Desc != before {
+			op = " < ?"
+		}
+		ands = append(ands, columns[sort.Field].Expr+op)
+		vars = append(vars, values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+
+	return "(" + strings.Join(ors, " OR ") + ")", vars
+}
+
+func encode(row reflect.Value, sorts []base.Sort, columns Columns, before bool) (string, error) {
+	cur := cursor{Before: before}
+	for _, sort := range sorts {
+		raw, err := json.Marshal(row.FieldByName(columns[sort.Field].Field).Interface())
+		if err != nil {
+			return "", err
+		}
+		cur.Values = append(cur.Values, raw)
+	}
+
+	data, err := json.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decode(token string, sorts []base.Sort, columns Columns, rowType reflect.Type) (cursor, []interface{}, error) {
+	cur := cursor{}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cur, nil, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cur); err != nil || len(cur.Values) != len(sorts) {
+		return cur, nil, ErrInvalidCursor
+	}
+
+	values := []interface{}{}
+	for i, sort := range sorts {
+		field, ok := rowType.FieldByName(columns[sort.Field].Field)
+		if !ok {
+			return cur, nil, ErrInvalidCursor
+		}
+		value := reflect.New(field.Type)
+		if err := json.Unmarshal(cur.Values[i], value.Interface()); err != nil {
+			return cur, nil, ErrInvalidCursor
+		}
+		values = append(values, value.Elem().Interface())
+	}
+
+	return cur, values, nil
+}
+
+// Contains returns the condition and pattern for a case-insensitive "contains" match,
+// wildcards typed by the client are escaped so they match literally
+func Contains(expr string, value string) (string, string) {
+	escaped := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+	return "LOWER(" + expr + ") LIKE ? ESCAPE '!'", "%" + strings.ToLower(escaped) + "%"
+}
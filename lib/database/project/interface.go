@@ -1,9 +1,11 @@
 package project
 
 import (
+	"part3/models/base"
 	"part3/models/project"
 	"part3/models/project/request"
 	"part3/models/project/response"
+	"part3/models/workflow"
 
 	"gorm.io/gorm"
 )
@@ -13,5 +15,20 @@ type Project interface {
 	GetById(id int, user_id int) (project.Project, error)
 	UpdateById(id int, user_id int, upPro request.ProRequest) (project.Project, error)
 	DeleteById(id int, user_id int) (gorm.DeletedAt, error)
-	GetAll(user_id int) ([]response.ProResponse, error)
+	GetAll(user_id int, filter request.ProFilter, page base.Page) ([]response.ProResponse, base.Meta, error)
+	GetWorkflow(id int, user_id int) (workflow.Workflow, error)
+	UpdateWorkflow(id int, user_id int, wf workflow.Workflow) (workflow.Workflow, error)
+	GetMembers(id int, user_id int) ([]response.MemberResponse, error)
+	AddMember(id int, user_id int, newMember project.Member) (project.Member, error)
+	UpdateMember(id int, user_id int, member_id int, role string) (project.Member, error)
+	RemoveMember(id int, user_id int, member_id int) error
+}
+
+type Invitation interface {
+	CreateInvitation(id int, user_id int, inv project.Invitation) (response.InvitationResponse, string, error)
+	GetInvitations(id int, user_id int) ([]response.InvitationResponse, error)
+	GetMyInvitations(user_id int) ([]response.InvitationResponse, error)
+	AcceptInvitation(token string, user_id int) (project.Member, error)
+	DeclineInvitation(token string) error
+	RevokeInvitation(id int, user_id int, invitation_id int) error
 }
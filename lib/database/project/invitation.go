@@ -0,0 +1,233 @@
+package project
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"part3/configs"
+	"part3/models/base"
+	"part3/models/project"
+	"part3/models/project/response"
+	"part3/models/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidInvitation  = base.NotFoundError("invalid_invitation", "invalid or expired invitation")
+	ErrInvitationEmail    = base.ForbiddenError("invitation_email", "invitation was sent to another email")
+	ErrInvitationNotFound = base.NotFoundError("invitation_not_found", "invitation not found")
+)
+
+type invitationRow struct {
+	project.Invitation
+	ProjectName string
+}
+
+const invitationSelect = "project_invitations.*, projects.name as project_name"
+
+func invitationSignature(inv project.Invitation) []byte {
+	mac := hmac.New(sha256.New, []byte(configs.GetConfig().Invitation.Secret))
+	fmt.Fprintf(mac, "%d|%d|%s|%s|%d", inv.ID, inv.Project_ID, inv.Email, inv.Role, inv.ExpiresAt.Unix())
+	return mac.Sum(nil)
+}
+
+// InvitationToken is what the invitee receives, the invitation id followed by its signature
+func InvitationToken(inv project.Invitation) string {
+	return strconv.FormatUint(uint64(inv.ID), 10) + "." + base64.RawURLEncoding.EncodeToString(invitationSignature(inv))
+}
+
+// pendingInvitation locks the invitation named by the token and checks it can still be answered
+func pendingInvitation(tx *gorm.DB, token string) (project.Invitation, error) {
+	inv := project.Invitation{}
+
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return inv, ErrInvalidInvitation
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return inv, ErrInvalidInvitation
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return inv, ErrInvalidInvitation
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&inv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return inv, ErrInvalidInvitation
+		}
+		return inv, err
+	}
+	if !hmac.Equal(signature, invitationSignature(inv)) || inv.Status(time.Now()) != project.InvitationPending {
+		return inv, ErrInvalidInvitation
+	}
+	return inv, nil
+}
+
+func acceptInvitation(tx *gorm.DB, inv project.Invitation, user_id int) (project.Member, error) {
+	member := project.Member{}
+	now := time.Now()
+
+	if err := tx.Model(&inv).Update("accepted_at", &now).Error; err != nil {
+		return member, err
+	}
+
+	err := tx.Where("project_id = ? AND user_id = ?", inv.Project_ID, user_id).First(&member).Error
+	if err == nil {
+		return member, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return member, err
+	}
+
+	member = project.Member{Project_ID: inv.Project_ID, User_ID: uint(user_id), Role: inv.Role}
+	if err := tx.Create(&member).Error; err != nil {
+		return member, err
+	}
+	return member, nil
+}
+
+// CreateInvitation replaces any pending invitation for the same email, so resending works
+func (pd *ProDb) CreateInvitation(id int, user_id int, inv project.Invitation) (response.InvitationResponse, string, error) {
+	pro := project.Project{}
+
+	err := pd.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := Authorize(tx, uint(id), user_id, project.MemberOwner); err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", id).First(&pro).Error; err != nil {
+			return err
+		}
+
+		var members int64
+		err := tx.Model(&project.Member{}).
+			Joins("inner join users on users.id = project_members.user_id AND users.deleted_at IS NULL").
+			Where("project_members.project_id = ? AND LOWER(users.email) = ?", id, inv.Email).
+			Count(&members).Error
+		if err != nil {
+			return err
+		}
+		if members > 0 {
+			return ErrMemberExists
+		}
+
+		now := time.Now()
+		err = tx.Model(&project.Invitation{}).
+			Where("project_id = ? AND email = ? AND accepted_at IS NULL AND declined_at IS NULL AND revoked_at IS NULL", id, inv.Email).
+			Update("revoked_at", &now).Error
+		if err != nil {
+			return err
+		}
+
+		inv.Project_ID = uint(id)
+		inv.InvitedBy = uint(user_id)
+		inv.ExpiresAt = now.Add(configs.GetConfig().Invitation.TTL).Truncate(time.Second)
+		return tx.Create(&inv).Error
+	})
+	if err != nil {
+		return response.InvitationResponse{}, "", base.NotFoundAs(err, ErrInvitationNotFound)
+	}
+
+	return inv.ToInvitationResponse(pro.Name), InvitationToken(inv), nil
+}
+
+func (pd *ProDb) GetInvitations(id int, user_id int) ([]response.InvitationResponse, error) {
+	if _, err := Authorize(pd.db, uint(id), user_id, project.MemberOwner); err != nil {
+		return nil, base.NotFoundAs(err, ErrInvitationNotFound)
+	}
+
+	return pd.findInvitations(pd.db.Where("project_invitations.project_id = ?", id))
+}
+
+// GetMyInvitations lists the pending invitations sent to the user's email
+func (pd *ProDb) GetMyInvitations(user_id int) ([]response.InvitationResponse, error) {
+	invitee := user.User{}
+	if err := pd.db.Where("id = ?", user_id).First(&invitee).Error; err != nil {
+		return nil, base.NotFoundAs(err, ErrInvitationNotFound)
+	}
+
+	query := pd.db.Where("project_invitations.email = ? AND project_invitations.accepted_at IS NULL AND project_invitations.declined_at IS NULL AND project_invitations.revoked_at IS NULL AND project_invitations.expires_at > ?", strings.ToLower(invitee.Email), time.Now())
+	return pd.findInvitations(query)
+}
+
+func (pd *ProDb) findInvitations(query *gorm.DB) ([]response.InvitationResponse, error) {
+	rows := []invitationRow{}
+
+	err := query.Model(&project.Invitation{}).
+		Select(invitationSelect).
+		Joins("inner join projects on projects.id = project_invitations.project_id AND projects.deleted_at IS NULL").
+		Order("project_invitations.id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := []response.InvitationResponse{}
+	for _, row := range rows {
+		invitations = append(invitations, row.ToInvitationResponse(row.ProjectName))
+	}
+	return invitations, nil
+}
+
+func (pd *ProDb) AcceptInvitation(token string, user_id int) (project.Member, error) {
+	member := project.Member{}
+
+	err := pd.db.Transaction(func(tx *gorm.DB) error {
+		inv, err := pendingInvitation(tx, token)
+		if err != nil {
+			return err
+		}
+
+		invitee := user.User{}
+		if err := tx.Where("id = ?", user_id).First(&invitee).Error; err != nil {
+			return err
+		}
+		if strings.ToLower(invitee.Email) != inv.Email {
+			return ErrInvitationEmail
+		}
+
+		member, err = acceptInvitation(tx, inv, user_id)
+		return err
+	})
+	if err != nil {
+		return project.Member{}, base.NotFoundAs(err, ErrInvitationNotFound)
+	}
+
+	return member, nil
+}
+
+func (pd *ProDb) DeclineInvitation(token string) error {
+	err := pd.db.Transaction(func(tx *gorm.DB) error {
+		inv, err := pendingInvitation(tx, token)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&inv).Update("declined_at", time.Now()).Error
+	})
+	return base.NotFoundAs(err, ErrInvitationNotFound)
+}
+
+func (pd *ProDb) RevokeInvitation(id int, user_id int, invitation_id int) error {
+	if _, err := Authorize(pd.db, uint(id), user_id, project.MemberOwner); err != nil {
+		return base.NotFoundAs(err, ErrInvitationNotFound)
+	}
+
+	res := pd.db.Model(&project.Invitation{}).
+		Where("id = ? AND project_id = ? AND accepted_at IS NULL AND declined_at IS NULL AND revoked_at IS NULL", invitation_id, id).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
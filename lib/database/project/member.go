@@ -0,0 +1,153 @@
+package project
+
+import (
+	"part3/models/base"
+	"part3/models/project"
+	"part3/models/project/response"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrForbidden      = base.ForbiddenError("forbidden", "forbidden access")
+	ErrMemberExists   = base.ConflictError("member_exists", "user is already a member")
+	ErrLastOwner      = base.ConflictError("last_owner", "project needs at least one owner")
+	ErrMemberNotFound = base.NotFoundError("member_not_found", "member not found")
+)
+
+// MemberJoin restricts a query on tasks or projects to the rows the user is a member of,
+// the project id column is passed in so both tables can use it
+func MemberJoin(db *gorm.DB, project_column string, user_id int) *gorm.DB {
+	return db.Joins("inner join project_members on project_members.project_id = "+project_column+" AND project_members.user_id = ?", user_id)
+}
+
+// Authorize checks the user is a member of the project with one of the roles,
+// non members get gorm.ErrRecordNotFound so they cannot probe which projects exist
+func Authorize(db *gorm.DB, project_id uint, user_id int, roles ...string) (project.Member, error) {
+	member := project.Member{}
+
+	if err := db.Where("project_id = ? AND user_id = ?", project_id, user_id).First(&member).Error; err != nil {
+		return member, err
+	}
+	for _, role := range roles {
+		if member.Role == role {
+			return member, nil
+		}
+	}
+	return member, ErrForbidden
+}
+
+func (pd *ProDb) GetMembers(id int, user_id int) ([]response.MemberResponse, error) {
+	if _, err := Authorize(pd.db, uint(id), user_id, project.MemberRoles...); err != nil {
+		return nil, base.NotFoundAs(err, ErrMemberNotFound)
+	}
+
+	members := []response.MemberResponse{}
+	err := pd.db.Model(&project.Member{}).
+		Select("project_members.user_id as user_id, users.name as name, users.email as email, project_members.role as role, project_members.created_at as created_at").
+		Joins("inner join users on users.id = project_members.user_id AND users.deleted_at IS NULL").
+		Where("project_members.project_id = ?", id).
+		Order("project_members.id").
+		Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (pd *ProDb) AddMember(id int, user_id int, newMember project.Member) (project.Member, error) {
+	newMember.Project_ID = uint(id)
+
+	err := pd.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := Authorize(tx, uint(id), user_id, project.MemberOwner); err != nil {
+			return err
+		}
+
+		var exists int64
+		if err := tx.Model(&project.Member{}).Where("project_id = ? AND user_id = ?", id, newMember.User_ID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists > 0 {
+			return ErrMemberExists
+		}
+
+		var users int64
+		if err := tx.Table("users").Where("id = ? AND deleted_at IS NULL", newMember.User_ID).Count(&users).Error; err != nil {
+			return err
+		}
+		if users == 0 {
+			return ErrMemberNotFound
+		}
+
+		return tx.Create(&newMember).Error
+	})
+	if err != nil {
+		return project.Member{}, base.NotFoundAs(err, ErrMemberNotFound)
+	}
+
+	return newMember, nil
+}
+
+func (pd *ProDb) UpdateMember(id int, user_id int, member_id int, role string) (project.Member, error) {
+	member := project.Member{}
+
+	err := pd.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := Authorize(tx, uint(id), user_id, project.MemberOwner); err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("project_id = ? AND user_id = ?", id, member_id).First(&member).Error; err != nil {
+			return err
+		}
+		if member.Role == project.MemberOwner && role != project.MemberOwner {
+			if err := lastOwner(tx, id); err != nil {
+				return err
+			}
+		}
+
+		member.Role = role
+		return tx.Model(&member).Update("role", role).Error
+	})
+	if err != nil {
+		return project.Member{}, base.NotFoundAs(err, ErrMemberNotFound)
+	}
+
+	return member, nil
+}
+
+// RemoveMember is open to owners, and to any member removing themselves to leave the project
+func (pd *ProDb) RemoveMember(id int, user_id int, member_id int) error {
+	err := pd.db.Transaction(func(tx *gorm.DB) error {
+		roles := []string{project.MemberOwner}
+		if member_id == user_id {
+			roles = project.MemberRoles
+		}
+		if _, err := Authorize(tx, uint(id), user_id, roles...); err != nil {
+			return err
+		}
+
+		member := project.Member{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("project_id = ? AND user_id = ?", id, member_id).First(&member).Error; err != nil {
+			return err
+		}
+		if member.Role == project.MemberOwner {
+			if err := lastOwner(tx, id); err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&member).Error
+	})
+	return base.NotFoundAs(err, ErrMemberNotFound)
+}
+
+func lastOwner(tx *gorm.DB, id int) error {
+	var owners int64
+	if err := tx.Model(&project.Member{}).Where("project_id = ? AND role = ?", id, project.MemberOwner).Count(&owners).Error; err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
@@ -1,7 +1,8 @@
 package project
 
 import (
-	"errors"
+	"part3/lib/database/paginate"
+	"part3/models/base"
 	"part3/models/project"
 	"part3/models/project/request"
 	"part3/models/project/response"
@@ -17,9 +18,17 @@ func New(db *gorm.DB) *ProDb {
 	return &ProDb{db: db}
 }
 
+// Create stores the project and makes its creator the first owner
 func (pd *ProDb) Create(user_id int, newPro project.Project) (project.Project, error) {
 	newPro.User_ID = uint(user_id)
-	if err := pd.db.Create(&newPro).Error; err != nil {
+
+	err := pd.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newPro).Error; err != nil {
+			return err
+		}
+		return tx.Create(&project.Member{Project_ID: newPro.ID, User_ID: uint(user_id), Role: project.MemberOwner}).Error
+	})
+	if err != nil {
 		return newPro, err
 	}
 	return newPro, nil
@@ -28,43 +37,81 @@ func (pd *ProDb) Create(user_id int, newPro project.Project) (project.Project, e
 func (pd *ProDb) GetById(id int, user_id int) (project.Project, error) {
 	pro := project.Project{}
 
-	if err := pd.db.Model(&pro).Where("id = ? AND user_id = ?", id, user_id).First(&pro).Error; err != nil {
+	if err := MemberJoin(pd.db.Model(&pro), "projects.id", user_id).Where("projects.id = ?", id).First(&pro).Error; err != nil {
 		return pro, err
 	}
 	return pro, nil
 }
 
+// UpdateById returns the project as stored, it is read back rather than
+// counting the rows changed since saving the name it already has changes none
 func (pd *ProDb) UpdateById(id int, user_id int, upPro request.ProRequest) (project.Project, error) {
-
-	res := pd.db.Model(project.Project{Model: gorm.Model{ID: uint(id)}, User_ID: uint(user_id)}).Updates(project.Project{Name: upPro.Name})
-
-	if res.RowsAffected == 0 {
-		return project.Project{}, errors.New(gorm.ErrRecordNotFound.Error())
+	if _, err := Authorize(pd.db, uint(id), user_id, project.EditRoles...); err != nil {
+		return project.Project{}, err
 	}
 
-	pro := upPro.ToProject()
+	// a map so the zero values are written too
+	res := pd.db.Model(&project.Project{}).Where("id = ?", id).Updates(map[string]interface{}{"name": upPro.Name})
+	if res.Error != nil {
+		return project.Project{}, res.Error
+	}
 
+	pro := project.Project{}
+	if err := pd.db.Where("id = ?", id).First(&pro).Error; err != nil {
+		return project.Project{}, err
+	}
 	return pro, nil
 }
 
 func (pd *ProDb) DeleteById(id int, user_id int) (gorm.DeletedAt, error) {
 	pro := project.Project{}
 
-	res := pd.db.Model(&pro).Where("id = ? AND user_id = ?", id, user_id).Delete(&pro)
+	if _, err := Authorize(pd.db, uint(id), user_id, project.MemberOwner); err != nil {
+		return pro.DeletedAt, err
+	}
 
-	if res.RowsAffected == 0 {
-		return pro.DeletedAt, errors.New(gorm.ErrRecordNotFound.Error())
+	err := pd.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&pro).Where("id = ?", id).Delete(&pro)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("project_id = ?", id).Delete(&project.Member{}).Error
+	})
+	if err != nil {
+		return pro.DeletedAt, err
 	}
 
 	return pro.DeletedAt, nil
 }
 
-func (pd *ProDb) GetAll(user_id int) ([]response.ProResponse, error) {
+var proColumns = paginate.Columns{
+	"id":         {Expr: "projects.id", Field: "Id"},
+	"name":       {Expr: "projects.name", Field: "Name"},
+	"created_at": {Expr: "projects.created_at", Field: "Created_at"},
+	"updated_at": {Expr: "projects.updated_at", Field: "Updated_at"},
+}
+
+func (pd *ProDb) GetAll(user_id int, filter request.ProFilter, page base.Page) ([]response.ProResponse, base.Meta, error) {
 	proRespArr := []response.ProResponse{}
 
-	if pd.db.Model(project.Project{}).Where("user_id = ?", user_id).Find(&proRespArr).RowsAffected == 0 {
-		return nil, errors.New(gorm.ErrRecordNotFound.Error())
+	query := MemberJoin(pd.db.Model(project.Project{}), "projects.id", user_id)
+	if filter.Name != "" {
+		cond, pattern := paginate.Contains("projects.name", filter.Name)
+		query = query.Where(cond, pattern)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("projects.created_at < ?", filter.CreatedBefore.UTC())
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("projects.created_at >= ?", filter.CreatedAfter.UTC())
 	}
 
-	return proRespArr, nil
+	meta, err := paginate.Find(query, "projects.id, projects.created_at, projects.updated_at, projects.name", page, proColumns, &proRespArr)
+	if err != nil {
+		return nil, meta, err
+	}
+	return proRespArr, meta, nil
 }
@@ -3,10 +3,12 @@ package project
 import (
 	"part3/configs"
 	_lib "part3/lib/database/user"
+	"part3/models/base"
 	"part3/models/project"
 	"part3/models/project/request"
 	"part3/models/task"
 	"part3/models/user"
+	"part3/models/workflow"
 	"part3/utils"
 	"testing"
 
@@ -18,11 +20,9 @@ func TestCreate(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run Create", func(t *testing.T) {
 		mockUser := user.User{Name: "Useranonim1", Email: "anonim@1", Password: "anonim1"}
@@ -54,11 +54,9 @@ func TestGetById(t *testing.T) {
 	confg := configs.GetConfig()
 	db := utils.InitDB(confg)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run GetById", func(t *testing.T) {
 
@@ -90,11 +88,9 @@ func TestUpdateById(t *testing.T) {
 	confg := configs.GetConfig()
 	db := utils.InitDB(confg)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run UpdateById", func(t *testing.T) {
 		mockUser := user.User{Name: "Useranonim123", Email: "anonim@123", Password: "anonim123"}
@@ -112,6 +108,15 @@ func TestUpdateById(t *testing.T) {
 		res, err := repo.UpdateById(1, 1, mockPro)
 		assert.Nil(t, err)
 		assert.Equal(t, "anonim321", res.Name)
+		assert.Equal(t, uint(1), res.ID)
+		assert.False(t, res.CreatedAt.IsZero())
+	})
+
+	t.Run("success run UpdateById without change", func(t *testing.T) {
+		res, err := repo.UpdateById(1, 1, request.ProRequest{Name: "anonim321"})
+		assert.Nil(t, err)
+		assert.Equal(t, "anonim321", res.Name)
+		assert.Equal(t, uint(1), res.ID)
 	})
 
 	t.Run("fail run UpdateById", func(t *testing.T) {
@@ -125,11 +130,9 @@ func TestDeleteById(t *testing.T) {
 	confg := configs.GetConfig()
 	db := utils.InitDB(confg)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run DeleteById", func(t *testing.T) {
 		mockUser := user.User{Name: "Useranonim123", Email: "anonim@123", Password: "anonim123"}
@@ -158,11 +161,9 @@ func TestGetAll(t *testing.T) {
 	confg := configs.GetConfig()
 	db := utils.InitDB(confg)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run GetAll", func(t *testing.T) {
 		mockUser := user.User{Name: "Useranonim123", Email: "anonim@123", Password: "anonim123"}
@@ -176,16 +177,248 @@ func TestGetAll(t *testing.T) {
 		if err != nil {
 			t.Fatal()
 		}
-		res, err := repo.GetAll(1)
+		res, meta, err := repo.GetAll(1, request.ProFilter{Name: "non"}, base.Page{Limit: 20})
 		assert.Nil(t, err)
-		assert.NotNil(t, res)
+		assert.Equal(t, 1, len(res))
+		assert.Equal(t, 1, int(meta.Total))
 	})
-	t.Run("fail run GetAll", func(t *testing.T) {
+	t.Run("success run GetAll empty", func(t *testing.T) {
 		if _, err := repo.DeleteById(1, 1); err != nil {
 			t.Fatal()
 		}
-		_, err := repo.GetAll(1)
+		res, meta, err := repo.GetAll(1, request.ProFilter{}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+		assert.Equal(t, 0, int(meta.Total))
+	})
+	t.Run("fail run GetAll", func(t *testing.T) {
+		_, _, err := repo.GetAll(1, request.ProFilter{}, base.Page{Limit: 20, Sort: []base.Sort{{Field: "user_id"}}})
 		assert.NotNil(t, err)
 	})
 
 }
+
+func TestWorkflow(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	mockUser := user.User{Name: "Useranonim1", Email: "anonim@1", Password: "anonim1"}
+	if _, err := _lib.New(db).Create(mockUser); err != nil {
+		t.Fatal()
+	}
+	if _, err := repo.Create(1, project.Project{Name: "Proanonim"}); err != nil {
+		t.Fatal()
+	}
+
+	t.Run("success run GetWorkflow default", func(t *testing.T) {
+		res, err := repo.GetWorkflow(1, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, workflow.StatusTodo, res.Initial())
+		assert.Equal(t, workflow.StatusDone, res.DoneStatus())
+	})
+
+	t.Run("fail run GetWorkflow", func(t *testing.T) {
+		_, err := repo.GetWorkflow(1, 2)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("success run UpdateWorkflow", func(t *testing.T) {
+		wf := workflow.Workflow{
+			Statuses: []workflow.Status{
+				{Name: workflow.StatusTodo, Position: 0},
+				{Name: "closed", Position: 1, IsDone: true},
+			},
+			Transitions: []workflow.Transition{{From: workflow.StatusTodo, To: "closed"}},
+		}
+		_, err := repo.UpdateWorkflow(1, 1, wf)
+		assert.Nil(t, err)
+
+		res, err := repo.GetWorkflow(1, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res.Statuses))
+		assert.True(t, res.CanTransition(workflow.StatusTodo, "closed"))
+	})
+
+	t.Run("fail run UpdateWorkflow status in use", func(t *testing.T) {
+		if err := db.Create(&task.Task{CreatedBy: 1, Name: "Taskanonim", Status: workflow.StatusTodo, Project_id: 1}).Error; err != nil {
+			t.Fatal()
+		}
+		wf := workflow.Workflow{
+			Statuses: []workflow.Status{
+				{Name: "open", Position: 0},
+				{Name: "closed", Position: 1, IsDone: true},
+			},
+		}
+		_, err := repo.UpdateWorkflow(1, 1, wf)
+		assert.Equal(t, ErrStatusInUse, err)
+	})
+}
+
+func TestMembers(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mockUser := range []user.User{
+		{Name: "Useranonim1", Email: "anonim@1", Password: "anonim1"},
+		{Name: "Useranonim2", Email: "anonim@2", Password: "anonim2"},
+		{Name: "Useranonim3", Email: "anonim@3", Password: "anonim3"},
+	} {
+		if _, err := _lib.New(db).Create(mockUser); err != nil {
+			t.Fatal()
+		}
+	}
+	if _, err := repo.Create(1, project.Project{Name: "Proanonim"}); err != nil {
+		t.Fatal()
+	}
+
+	t.Run("success run AddMember", func(t *testing.T) {
+		res, err := repo.AddMember(1, 1, project.Member{User_ID: 2, Role: project.MemberViewer})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, int(res.User_ID))
+
+		members, err := repo.GetMembers(1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(members))
+		assert.Equal(t, project.MemberOwner, members[0].Role)
+		assert.Equal(t, "anonim@2", members[1].Email)
+	})
+
+	t.Run("fail run AddMember", func(t *testing.T) {
+		_, err := repo.AddMember(1, 1, project.Member{User_ID: 2, Role: project.MemberEditor})
+		assert.Equal(t, ErrMemberExists, err)
+
+		_, err = repo.AddMember(1, 2, project.Member{User_ID: 3, Role: project.MemberViewer})
+		assert.Equal(t, ErrForbidden, err)
+
+		_, err = repo.AddMember(1, 1, project.Member{User_ID: 9, Role: project.MemberViewer})
+		assert.Equal(t, ErrMemberNotFound, err)
+	})
+
+	t.Run("shared project access", func(t *testing.T) {
+		_, err := repo.GetById(1, 2)
+		assert.Nil(t, err)
+
+		_, err = repo.UpdateById(1, 2, request.ProRequest{Name: "anonim321"})
+		assert.Equal(t, ErrForbidden, err)
+
+		_, err = repo.GetById(1, 3)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("fail run UpdateMember last owner", func(t *testing.T) {
+		_, err := repo.UpdateMember(1, 1, 1, project.MemberEditor)
+		assert.Equal(t, ErrLastOwner, err)
+
+		err = repo.RemoveMember(1, 1, 1)
+		assert.Equal(t, ErrLastOwner, err)
+	})
+
+	t.Run("success run UpdateMember", func(t *testing.T) {
+		res, err := repo.UpdateMember(1, 1, 2, project.MemberOwner)
+		assert.Nil(t, err)
+		assert.Equal(t, project.MemberOwner, res.Role)
+
+		_, err = repo.UpdateMember(1, 1, 1, project.MemberEditor)
+		assert.Nil(t, err)
+
+		_, err = repo.DeleteById(1, 1)
+		assert.Equal(t, ErrForbidden, err)
+	})
+
+	t.Run("success run RemoveMember", func(t *testing.T) {
+		assert.Nil(t, repo.RemoveMember(1, 1, 1))
+
+		_, err := repo.GetById(1, 1)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestInvitations(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mockUser := range []user.User{
+		{Name: "Useranonim1", Email: "anonim@1", Password: "anonim1"},
+		{Name: "Useranonim2", Email: "Anonim@2", Password: "anonim2"},
+	} {
+		if _, err := _lib.New(db).Create(mockUser); err != nil {
+			t.Fatal()
+		}
+	}
+	if _, err := repo.Create(1, project.Project{Name: "Proanonim"}); err != nil {
+		t.Fatal()
+	}
+
+	var token string
+
+	t.Run("success run CreateInvitation", func(t *testing.T) {
+		res, first, err := repo.CreateInvitation(1, 1, project.Invitation{Email: "anonim@2", Role: project.MemberEditor})
+		assert.Nil(t, err)
+		assert.Equal(t, "Proanonim", res.Project_name)
+		assert.Equal(t, project.InvitationPending, res.Status)
+
+		_, token, err = repo.CreateInvitation(1, 1, project.Invitation{Email: "anonim@2", Role: project.MemberEditor})
+		assert.Nil(t, err)
+
+		_, err = repo.AcceptInvitation(first, 2)
+		assert.Equal(t, ErrInvalidInvitation, err)
+
+		invitations, err := repo.GetInvitations(1, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(invitations))
+		assert.Equal(t, project.InvitationRevoked, invitations[0].Status)
+
+		mine, err := repo.GetMyInvitations(2)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(mine))
+	})
+
+	t.Run("fail run CreateInvitation", func(t *testing.T) {
+		_, _, err := repo.CreateInvitation(1, 2, project.Invitation{Email: "anonim@3", Role: project.MemberEditor})
+		assert.NotNil(t, err)
+
+		_, _, err = repo.CreateInvitation(1, 1, project.Invitation{Email: "anonim@1", Role: project.MemberEditor})
+		assert.Equal(t, ErrMemberExists, err)
+	})
+
+	t.Run("fail run AcceptInvitation", func(t *testing.T) {
+		_, err := repo.AcceptInvitation(token+"x", 2)
+		assert.Equal(t, ErrInvalidInvitation, err)
+
+		_, err = repo.AcceptInvitation(token, 1)
+		assert.Equal(t, ErrInvitationEmail, err)
+	})
+
+	t.Run("success run AcceptInvitation", func(t *testing.T) {
+		res, err := repo.AcceptInvitation(token, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, project.MemberEditor, res.Role)
+
+		_, err = repo.GetById(1, 2)
+		assert.Nil(t, err)
+
+		err = repo.DeclineInvitation(token)
+		assert.Equal(t, ErrInvalidInvitation, err)
+	})
+
+	t.Run("success run RevokeInvitation", func(t *testing.T) {
+		res, _, err := repo.CreateInvitation(1, 1, project.Invitation{Email: "anonim@3", Role: project.MemberViewer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, repo.RevokeInvitation(1, 1, int(res.Id)))
+		assert.Equal(t, ErrInvitationNotFound, repo.RevokeInvitation(1, 1, int(res.Id)))
+	})
+}
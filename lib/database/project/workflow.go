@@ -0,0 +1,81 @@
+package project
+
+import (
+	"part3/models/base"
+	"part3/models/project"
+	"part3/models/task"
+	"part3/models/workflow"
+
+	"gorm.io/gorm"
+)
+
+var ErrStatusInUse = base.ConflictError("status_in_use", "status still used by tasks")
+
+// LoadWorkflow returns the project's configured workflow, or the default one when none is stored
+func LoadWorkflow(db *gorm.DB, project_id uint) (workflow.Workflow, error) {
+	wf := workflow.Workflow{}
+
+	if err := db.Where("project_id = ?", project_id).Order("position").Find(&wf.Statuses).Error; err != nil {
+		return wf, err
+	}
+	if len(wf.Statuses) == 0 {
+		return workflow.Default(), nil
+	}
+	if err := db.Where("project_id = ?", project_id).Find(&wf.Transitions).Error; err != nil {
+		return wf, err
+	}
+
+	return wf, nil
+}
+
+func (pd *ProDb) GetWorkflow(id int, user_id int) (workflow.Workflow, error) {
+	if _, err := pd.GetById(id, user_id); err != nil {
+		return workflow.Workflow{}, err
+	}
+
+	return LoadWorkflow(pd.db, uint(id))
+}
+
+func (pd *ProDb) UpdateWorkflow(id int, user_id int, wf workflow.Workflow) (workflow.Workflow, error) {
+	if _, err := Authorize(pd.db, uint(id), user_id, project.EditRoles...); err != nil {
+		return workflow.Workflow{}, err
+	}
+
+	names := []string{}
+	for i := range wf.Statuses {
+		wf.Statuses[i].Project_ID = uint(id)
+		names = append(names, wf.Statuses[i].Name)
+	}
+	for i := range wf.Transitions {
+		wf.Transitions[i].Project_ID = uint(id)
+	}
+
+	err := pd.db.Transaction(func(tx *gorm.DB) error {
+		var inUse int64
+		if err := tx.Model(&task.Task{}).Where("project_id = ? AND status NOT IN ?", id, names).Count(&inUse).Error; err != nil {
+			return err
+		}
+		if inUse > 0 {
+			return ErrStatusInUse
+		}
+
+		if err := tx.Unscoped().Where("project_id = ?", id).Delete(&workflow.Status{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("project_id = ?", id).Delete(&workflow.Transition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&wf.Statuses).Error; err != nil {
+			return err
+		}
+		if len(wf.Transitions) > 0 {
+			return tx.Create(&wf.Transitions).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return workflow.Workflow{}, err
+	}
+
+	return wf, nil
+}
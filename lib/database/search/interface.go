@@ -0,0 +1,10 @@
+package search
+
+import (
+	"part3/models/base"
+	"part3/models/search/response"
+)
+
+type Search interface {
+	Search(user_id int, q string, types []string, page base.Page) ([]response.ResultResponse, base.Meta, error)
+}
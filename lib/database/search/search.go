@@ -0,0 +1,249 @@
+package search
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"part3/lib/database/paginate"
+	"part3/lib/database/project"
+	"part3/models/base"
+	"part3/models/comment"
+	_project "part3/models/project"
+	"part3/models/search"
+	"part3/models/search/response"
+	"part3/models/task"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidQuery = base.ValidationError("invalid_query", "search query has no words")
+
+const (
+	maxTerms      = 8
+	snippetLength = 160
+)
+
+type candidate struct {
+	Kind       string
+	ID         uint
+	Project_id uint
+	Task_id    *uint
+	Title      string
+	Text       string
+	Score      float64
+}
+
+// source is one kind of record, names weigh more than comment bodies
+type source struct {
+	kind   string
+	column string
+	weight float64
+	fields string
+	query  func(db *gorm.DB, user_id int) *gorm.DB
+}
+
+var sources = []source{
+	{search.TypeProject, "projects.name", 2, "projects.id as id, projects.id as project_id, NULL as task_id, projects.name as title, projects.name as text", func(db *gorm.DB, user_id int) *gorm.DB {
+		return project.MemberJoin(db.Model(&_project.Project{}), "projects.id", user_id)
+	}},
+	{search.TypeTask, "tasks.name", 2, "tasks.id as id, tasks.project_id as project_id, NULL as task_id, tasks.name as title, tasks.name as text", func(db *gorm.DB, user_id int) *gorm.DB {
+		return project.MemberJoin(db.Model(&task.Task{}).Joins("inner join projects on projects.id = tasks.project_id AND projects.deleted_at IS NULL"), "tasks.project_id", user_id)
+	}},
+	{search.TypeComment, "comments.body", 1, "comments.id as id, tasks.project_id as project_id, tasks.id as task_id, tasks.name as title, comments.body as text", func(db *gorm.DB, user_id int) *gorm.DB {
+		return project.MemberJoin(db.Model(&comment.Comment{}).
+			Joins("inner join tasks on tasks.id = comments.task_id AND tasks.deleted_at IS NULL").
+			Joins("inner join projects on projects.id = tasks.project_id AND projects.deleted_at IS NULL"), "tasks.project_id", user_id)
+	}},
+}
+
+type SearchDb struct {
+	db       *gorm.DB
+	fulltext bool
+}
+
+// New searches with MATCH AGAINST on MySQL, where the migrations add the
+// FULLTEXT indexes, and with LIKE elsewhere
+func New(db *gorm.DB) *SearchDb {
+	return &SearchDb{db: db, fulltext: db.Dialector.Name() == "mysql"}
+}
+
+// Search returns the projects, tasks and comments the user can see matching
+// every word of q, a word also matches the words it starts. The sources are
+// joined in one query, so the database ranks, counts and pages them together
+func (sd *SearchDb) Search(user_id int, q string, types []string, page base.Page) ([]response.ResultResponse, base.Meta, error) {
+	meta := base.Meta{Limit: page.Limit, Offset: page.Offset}
+	if page.Cursor != "" {
+		return nil, meta, paginate.ErrInvalidCursor
+	}
+	if len(page.Sort) > 0 {
+		return nil, meta, paginate.ErrInvalidSort
+	}
+	terms := splitTerms(q)
+	if len(terms) == 0 {
+		return nil, meta, ErrInvalidQuery
+	}
+
+	queries := []interface{}{}
+	for _, src := range sources {
+		if len(types) > 0 && !contains(types, src.kind) {
+			continue
+		}
+		queries = append(queries, sd.match(src, src.query(sd.db, user_id), terms))
+	}
+	if len(queries) == 0 {
+		return []response.ResultResponse{}, meta, nil
+	}
+	union := strings.TrimSuffix(strings.Repeat("? UNION ALL ", len(queries)), " UNION ALL ")
+
+	if err := sd.db.Raw("SELECT COUNT(*) FROM ("+union+") results", queries...).Scan(&meta.Total).Error; err != nil {
+		return nil, meta, err
+	}
+
+	rows := []candidate{}
+	vars := append(queries, page.Limit, page.Offset)
+	if err := sd.db.Raw("SELECT * FROM ("+union+") results ORDER BY score DESC, id DESC, kind LIMIT ? OFFSET ?", vars...).Scan(&rows).Error; err != nil {
+		return nil, meta, err
+	}
+
+	results := make([]response.ResultResponse, len(rows))
+	for i, row := range rows {
+		results[i] = response.ResultResponse{
+			Type:       row.Kind,
+			ID:         row.ID,
+			Project_id: row.Project_id,
+			Task_id:    row.Task_id,
+			Title:      row.Title,
+			Snippet:    snippet(row.Text, terms),
+			Score:      math.Round(row.Score*1000) / 1000,
+		}
+	}
+	return results, meta, nil
+}
+
+// match keeps the rows whose column has every term and scores them. Without
+// FULLTEXT a term counts 3 as a whole word and 2 as the start of one, averaged
+// over the terms, and short texts win a tie
+func (sd *SearchDb) match(src source, query *gorm.DB, terms []string) *gorm.DB {
+	fields := src.fields + ", '" + src.kind + "' as kind"
+	if sd.fulltext {
+		against := booleanQuery(terms)
+		return query.Where("MATCH("+src.column+") AGAINST(? IN BOOLEAN MODE)", against).
+			Select(fields+", MATCH("+src.column+") AGAINST(? IN BOOLEAN MODE) * ? as score", against, src.weight)
+	}
+
+	// terms are only letters and digits, nothing to escape for LIKE
+	lower := "LOWER(" + src.column + ")"
+	ranks := make([]string, len(terms))
+	vars := []interface{}{}
+	for i, term := range terms {
+		query = query.Where("("+lower+" LIKE ? OR "+lower+" LIKE ?)", term+"%", "% "+term+"%")
+		ranks[i] = "CASE WHEN ' ' || " + lower + " || ' ' LIKE ? THEN 3 ELSE 2 END"
+		vars = append(vars, "% "+term+" %")
+	}
+	words := "(2 + LENGTH(" + src.column + ") - LENGTH(REPLACE(" + src.column + ", ' ', '')))"
+	score := fmt.Sprintf("((%s) * 1.0 / %d + 1.0 / %s) * %.1f as score", strings.Join(ranks, " + "), len(terms), words, src.weight)
+	return query.Select(fields+", "+score, vars...)
+}
+
+// booleanQuery requires every term, as a word or the start of one
+func booleanQuery(terms []string) string {
+	words := make([]string, len(terms))
+	for i, term := range terms {
+		words[i] = "+" + term + "*"
+	}
+	return strings.Join(words, " ")
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// splitTerms splits the query into lower case words, dropping repeats and any
+// operator characters
+func splitTerms(q string) []string {
+	terms := []string{}
+	seen := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(q), func(r rune) bool { return !isWordRune(r) }) {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// snippet cuts the text around the first match and marks every term in it
+func snippet(text string, terms []string) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(runes))
+	first := len(runes)
+	for _, term := range terms {
+		needle := []rune(term)
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if string(lower[i:i+len(needle)]) != term {
+				continue
+			}
+			for j := i; j < i+len(needle); j++ {
+				marked[j] = true
+			}
+			if i < first {
+				first = i
+			}
+		}
+	}
+
+	start, end := 0, len(runes)
+	if len(runes) > snippetLength {
+		if first < len(runes) {
+			start = first - snippetLength/3
+		}
+		if start < 0 {
+			start = 0
+		}
+		end = start + snippetLength
+		if end > len(runes) {
+			end, start = len(runes), len(runes)-snippetLength
+		}
+	}
+
+	var out strings.Builder
+	if start > 0 {
+		out.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		part := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			part = "<mark>" + part + "</mark>"
+		}
+		out.WriteString(part)
+		i = j
+	}
+	if end < len(runes) {
+		out.WriteString("…")
+	}
+	return out.String()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
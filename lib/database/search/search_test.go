@@ -0,0 +1,184 @@
+package search
+
+import (
+	"part3/configs"
+	_libComment "part3/lib/database/comment"
+	_libPro "part3/lib/database/project"
+	_libTask "part3/lib/database/task"
+	_lib "part3/lib/database/user"
+	"part3/models/base"
+	"part3/models/project"
+	"part3/models/search"
+	"part3/models/task"
+	"part3/models/user"
+	"part3/utils"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mocUserP := range []user.User{
+		{Name: "anonim1", Email: "anonim@1", Password: "anonim1"},
+		{Name: "anonim2", Email: "anonim@2", Password: "anonim2"},
+	} {
+		if _, err := _lib.New(db).Create(mocUserP); err != nil {
+			t.Fatal()
+		}
+	}
+	if _, err := _libPro.New(db).Create(1, project.Project{Name: "Website relaunch"}); err != nil {
+		t.Fatal()
+	}
+	if _, err := _libPro.New(db).Create(2, project.Project{Name: "Secret design"}); err != nil {
+		t.Fatal()
+	}
+	for _, mockTaskP := range []struct {
+		user_id int
+		task    task.Task
+	}{
+		{1, task.Task{Name: "Design landing page", Priority: 1, Project_id: 1}},
+		{1, task.Task{Name: "Write release notes", Priority: 1, Project_id: 1}},
+		{2, task.Task{Name: "Design secret logo", Priority: 1, Project_id: 2}},
+	} {
+		if _, err := _libTask.New(db).Create(mockTaskP.user_id, mockTaskP.task); err != nil {
+			t.Fatal()
+		}
+	}
+	if _, err := _libComment.New(db).Create(2, 1, "The designer shared the <b>mockups</b>"); err != nil {
+		t.Fatal()
+	}
+	page := base.Page{Limit: base.DefaultPageLimit}
+
+	t.Run("success run Search", func(t *testing.T) {
+		res, meta, err := repo.Search(1, "Design", nil, page)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), meta.Total)
+		assert.Equal(t, search.TypeTask, res[0].Type)
+		assert.Equal(t, "<mark>Design</mark> landing page", res[0].Snippet)
+		assert.Equal(t, search.TypeComment, res[1].Type)
+		assert.Equal(t, uint(2), *res[1].Task_id)
+		assert.Equal(t, "The <mark>design</mark>er shared the &lt;b&gt;mockups&lt;/b&gt;", res[1].Snippet)
+		assert.True(t, res[0].Score > res[1].Score)
+	})
+
+	t.Run("success run Search prefix of every word", func(t *testing.T) {
+		res, _, err := repo.Search(1, "relau web", nil, page)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.Equal(t, search.TypeProject, res[0].Type)
+
+		res, _, err = repo.Search(1, "notes", []string{search.TypeComment}, page)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+	})
+
+	t.Run("success run Search other projects hidden", func(t *testing.T) {
+		res, _, err := repo.Search(1, "secret", nil, page)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+
+		res, _, err = repo.Search(2, "secret", nil, page)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+	})
+
+	t.Run("fail run Search", func(t *testing.T) {
+		_, _, err := repo.Search(1, " +-* ", nil, page)
+		assert.Equal(t, ErrInvalidQuery, err)
+	})
+}
+
+func TestSplitTerms(t *testing.T) {
+	assert.Equal(t, []string{"design", "page"}, splitTerms(`+Design* "page" DESIGN -`))
+	assert.Equal(t, maxTerms, len(splitTerms(strings.Repeat("a b c d e f g h i j ", 2))))
+	assert.Equal(t, "+design* +page*", booleanQuery([]string{"design", "page"}))
+}
+
+func TestSearchRank(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := _lib.New(db).Create(user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}); err != nil {
+		t.Fatal()
+	}
+	if _, err := _libPro.New(db).Create(1, project.Project{Name: "Website relaunch"}); err != nil {
+		t.Fatal()
+	}
+	for _, name := range []string{"Designer page", "Redesign page", "Design page", "Design"} {
+		if _, err := _libTask.New(db).Create(1, task.Task{Name: name, Priority: 1, Project_id: 1}); err != nil {
+			t.Fatal()
+		}
+	}
+
+	t.Run("success run Search whole words first", func(t *testing.T) {
+		res, meta, err := repo.Search(1, "design", nil, base.Page{Limit: base.DefaultPageLimit})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), meta.Total)
+		titles := []string{}
+		for _, result := range res {
+			titles = append(titles, result.Title)
+		}
+		assert.Equal(t, []string{"Design", "Design page", "Designer page"}, titles)
+		assert.True(t, res[0].Score > res[1].Score && res[1].Score > res[2].Score)
+	})
+
+	t.Run("success run Search page", func(t *testing.T) {
+		res, meta, err := repo.Search(1, "design", nil, base.Page{Limit: 1, Offset: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), meta.Total)
+		assert.Equal(t, 1, len(res))
+		assert.Equal(t, "Designer page", res[0].Title)
+
+		res, meta, err = repo.Search(1, "design", nil, base.Page{Limit: 1, Offset: 5})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), meta.Total)
+		assert.Equal(t, 0, len(res))
+	})
+
+	t.Run("success run Search pages ties", func(t *testing.T) {
+		// the task now has the id, name and so the score of the project
+		if err := db.Model(&task.Task{}).Where("id = ?", 1).Update("name", "Website relaunch").Error; err != nil {
+			t.Fatal(err)
+		}
+
+		kinds := []string{}
+		for offset := 0; offset < 2; offset++ {
+			res, _, err := repo.Search(1, "relaunch", nil, base.Page{Limit: 1, Offset: offset})
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(res))
+			kinds = append(kinds, res[0].Type)
+		}
+		assert.Equal(t, []string{search.TypeProject, search.TypeTask}, kinds)
+	})
+}
+
+func TestSnippet(t *testing.T) {
+	t.Run("marks every term", func(t *testing.T) {
+		assert.Equal(t, "<mark>Ship</mark> the <mark>new</mark> <mark>Ship</mark>", snippet("Ship the new \n\n Ship", []string{"ship", "new"}))
+	})
+
+	t.Run("cuts around the first match", func(t *testing.T) {
+		text := strings.Repeat("word ", 60) + "needle " + strings.Repeat("word ", 60)
+		res := snippet(text, []string{"needle"})
+		assert.True(t, strings.HasPrefix(res, "…"))
+		assert.True(t, strings.HasSuffix(res, "…"))
+		assert.Contains(t, res, "<mark>needle</mark>")
+		assert.Equal(t, snippetLength+2, len([]rune(strings.NewReplacer("<mark>", "", "</mark>", "").Replace(res))))
+	})
+
+	t.Run("escapes the text", func(t *testing.T) {
+		assert.Equal(t, "a &lt;<mark>b</mark>&gt;", snippet("a <b>", []string{"b"}))
+	})
+}
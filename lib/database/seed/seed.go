@@ -0,0 +1,152 @@
+package seed
+
+import (
+	_label "part3/lib/database/label"
+	_project "part3/lib/database/project"
+	_task "part3/lib/database/task"
+	_user "part3/lib/database/user"
+	"part3/models/base"
+	boardReq "part3/models/board/request"
+	"part3/models/label"
+	"part3/models/project"
+	"part3/models/task"
+	"part3/models/task/request"
+	"part3/models/user"
+	"part3/models/workflow"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const DemoEmail = "demo@example.com"
+
+var ErrSeeded = base.ConflictError("already_seeded", "demo data already seeded")
+
+// Result is what Seed made, the password is only known here
+type Result struct {
+	User     user.User
+	Password string
+	Projects int
+	Tasks    int
+}
+
+type demoTask struct {
+	name     string
+	priority int
+	dueIn    int
+	status   string
+	section  string
+	labels   []string
+}
+
+type demoProject struct {
+	name     string
+	sections []string
+	labels   []label.Label
+	tasks    []demoTask
+}
+
+var demo = []demoProject{
+	{
+		name:     "Website relaunch",
+		sections: []string{"Design", "Build"},
+		labels:   []label.Label{{Name: "bug", Colour: "#d73a4a"}, {Name: "feature", Colour: "#0e8a16"}},
+		tasks: []demoTask{
+			{name: "Design landing page", priority: 1, dueIn: 3, status: workflow.StatusInProgress, section: "Design", labels: []string{"feature"}},
+			{name: "Fix login redirect", priority: 1, dueIn: 1, section: "Build", labels: []string{"bug"}},
+			{name: "Write release notes", priority: 3, dueIn: 14, section: "Build"},
+			{name: "Pick hosting provider", priority: 2, status: workflow.StatusDone},
+		},
+	},
+	{
+		name: "Personal",
+		tasks: []demoTask{
+			{name: "Plan next sprint", priority: 2, dueIn: 2},
+			{name: "Read onboarding guide", priority: 3},
+		},
+	},
+}
+
+// Seed adds a demo user owning a few projects with sections, labels and tasks,
+// through the repositories so the data goes through the same rules as the API
+func Seed(db *gorm.DB, password string) (Result, error) {
+	var seeded int64
+	if err := db.Model(&user.User{}).Where("email = ?", DemoEmail).Count(&seeded).Error; err != nil {
+		return Result{}, err
+	}
+	if seeded > 0 {
+		return Result{}, ErrSeeded
+	}
+
+	demoUser, err := _user.New(db).Create(user.User{Name: "Demo", Email: DemoEmail, Password: password})
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{User: demoUser, Password: password}
+	user_id := int(demoUser.ID)
+
+	projects := _project.New(db)
+	tasks := _task.New(db)
+	boards := _task.NewBoard(db)
+	labels := _label.New(db)
+	now := time.Now().UTC()
+
+	for _, demoPro := range demo {
+		pro, err := projects.Create(user_id, project.Project{Name: demoPro.name})
+		if err != nil {
+			return result, err
+		}
+		result.Projects++
+
+		section_ids := map[string]uint{}
+		for _, name := range demoPro.sections {
+			section, err := boards.CreateSection(int(pro.ID), user_id, boardReq.SectionRequest{Name: name})
+			if err != nil {
+				return result, err
+			}
+			section_ids[name] = section.ID
+		}
+		label_ids := map[string]uint{}
+		for _, demoLabel := range demoPro.labels {
+			newLabel, err := labels.Create(int(pro.ID), user_id, demoLabel)
+			if err != nil {
+				return result, err
+			}
+			label_ids[newLabel.Name] = newLabel.ID
+		}
+
+		for _, demoTask := range demoPro.tasks {
+			newTask := task.Task{Name: demoTask.name, Priority: demoTask.priority, Project_id: pro.ID}
+			if demoTask.dueIn > 0 {
+				dueAt := now.AddDate(0, 0, demoTask.dueIn)
+				newTask.DueAt = &dueAt
+			}
+			created, err := tasks.Create(user_id, newTask)
+			if err != nil {
+				return result, err
+			}
+			result.Tasks++
+
+			if demoTask.status != "" || demoTask.section != "" {
+				move := request.MoveRequest{Status: demoTask.status}
+				if section_id, ok := section_ids[demoTask.section]; ok {
+					move.Section_id = &section_id
+				}
+				if _, err := tasks.Move(int(created.ID), user_id, move); err != nil {
+					return result, err
+				}
+			}
+			attach := []uint{}
+			for _, name := range demoTask.labels {
+				attach = append(attach, label_ids[name])
+			}
+			if len(attach) > 0 {
+				if _, err := labels.Attach(int(created.ID), user_id, attach); err != nil {
+					return result, err
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
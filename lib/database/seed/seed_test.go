@@ -0,0 +1,48 @@
+package seed
+
+import (
+	"part3/configs"
+	_task "part3/lib/database/task"
+	"part3/models/base"
+	"part3/models/board"
+	"part3/models/task/request"
+	"part3/models/workflow"
+	"part3/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeed(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("success run Seed", func(t *testing.T) {
+		res, err := Seed(db, "demo1234")
+		assert.Nil(t, err)
+		assert.Equal(t, DemoEmail, res.User.Email)
+		assert.True(t, utils.CheckPassword(res.User.Password, "demo1234"))
+		assert.Equal(t, 2, res.Projects)
+		assert.Equal(t, 6, res.Tasks)
+
+		tasks, meta, err := _task.New(db).GetAll(int(res.User.ID), request.TaskFilter{}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(6), meta.Total)
+		assert.Equal(t, 6, len(tasks))
+
+		columns, err := _task.NewBoard(db).GetBoard(1, int(res.User.ID), board.BySection)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(columns.Columns))
+		assert.Equal(t, 1, len(columns.Columns[1].Tasks))
+		assert.Equal(t, workflow.StatusInProgress, columns.Columns[1].Tasks[0].Status)
+		assert.Equal(t, 1, len(columns.Columns[1].Tasks[0].Labels))
+	})
+
+	t.Run("fail run Seed twice", func(t *testing.T) {
+		_, err := Seed(db, "demo1234")
+		assert.Equal(t, ErrSeeded, err)
+	})
+}
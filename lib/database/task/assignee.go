@@ -0,0 +1,104 @@
+package task
+
+import (
+	"part3/lib/database/project"
+	"part3/models/base"
+	_project "part3/models/project"
+	"part3/models/task"
+	"part3/models/task/response"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrAssigneeNotFound = base.NotFoundError("assignee_not_found", "task or assignee not found")
+
+type assigneeRow struct {
+	Task_id uint
+	response.AssigneeResponse
+}
+
+// loadAssignees fills the assignees of every task in one query, listings would be N+1 otherwise
+func loadAssignees(db *gorm.DB, tasks []response.TaskResponse) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := []uint{}
+	index := map[uint][]int{}
+	for i := range tasks {
+		tasks[i].Assignees = []response.AssigneeResponse{}
+		ids = append(ids, tasks[i].ID)
+		index[tasks[i].ID] = append(index[tasks[i].ID], i)
+	}
+
+	rows := []assigneeRow{}
+	err := db.Model(&task.Assignee{}).
+		Select("task_assignees.task_id as task_id, users.id as user_id, users.name as name, users.email as email").
+		Joins("inner join users on users.id = task_assignees.user_id AND users.deleted_at IS NULL").
+		Where("task_assignees.task_id IN ?", ids).
+		Order("task_assignees.id").
+		Find(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		for _, i := range index[row.Task_id] {
+			tasks[i].Assignees = append(tasks[i].Assignees, row.AssigneeResponse)
+		}
+	}
+	return nil
+}
+
+func (td *TaskDb) GetAssignees(id int, user_id int) ([]response.AssigneeResponse, error) {
+	taskResp, err := td.GetByIdResp(id, user_id)
+	if err != nil {
+		return nil, base.NotFoundAs(err, ErrAssigneeNotFound)
+	}
+	return taskResp.Assignees, nil
+}
+
+// AddAssignees skips users already assigned, checking they belong to the project is left to the caller
+func (td *TaskDb) AddAssignees(id int, user_id int, assignees []uint) ([]response.AssigneeResponse, error) {
+	found, err := td.editable(id, user_id, 0)
+	if err != nil {
+		return nil, base.NotFoundAs(err, ErrAssigneeNotFound)
+	}
+
+	rows := []task.Assignee{}
+	for _, assignee := range assignees {
+		rows = append(rows, task.Assignee{Task_ID: found.ID, User_ID: assignee, AssignedBy: uint(user_id)})
+	}
+	if len(rows) > 0 {
+		if err := td.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return td.GetAssignees(id, user_id)
+}
+
+func (td *TaskDb) RemoveAssignee(id int, user_id int, assignee_id int) error {
+	found := task.Task{}
+	if err := td.db.Where("id = ?", id).First(&found).Error; err != nil {
+		return base.NotFoundAs(err, ErrAssigneeNotFound)
+	}
+	// members may always take themselves off a task, changing someone else's work needs edit rights
+	roles := _project.EditRoles
+	if assignee_id == user_id {
+		roles = _project.MemberRoles
+	}
+	if _, err := project.Authorize(td.db, found.Project_id, user_id, roles...); err != nil {
+		return base.NotFoundAs(err, ErrAssigneeNotFound)
+	}
+
+	res := td.db.Where("task_id = ? AND user_id = ?", id, assignee_id).Delete(&task.Assignee{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrAssigneeNotFound
+	}
+	return nil
+}
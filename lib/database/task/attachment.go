@@ -0,0 +1,189 @@
+package task
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"part3/lib/storage"
+	"part3/models/base"
+	"part3/models/task"
+	"part3/models/task/response"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSizeMismatch       = base.ValidationError("size_mismatch", "attachment size does not match its content")
+	ErrAttachmentNotFound = base.NotFoundError("attachment_not_found", "task or attachment not found")
+	ErrAttachmentTooLarge = base.TooLargeError("attachment_too_large", "attachment is too large")
+	ErrUnsupportedType    = base.MediaTypeError("unsupported_media_type", "unsupported attachment type")
+)
+
+// AttachmentDb keeps the attachment rows in the database and their content in the storage
+type AttachmentDb struct {
+	tasks *TaskDb
+	store storage.Storage
+}
+
+func NewAttachments(db *gorm.DB, store storage.Storage) *AttachmentDb {
+	return &AttachmentDb{tasks: New(db), store: store}
+}
+
+func storageKey(task_id int) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", task_id, hex.EncodeToString(random)), nil
+}
+
+// Create streams the content to the storage while hashing it, the row is only
+// written once the whole file is stored and its size matches
+func (ad *AttachmentDb) Create(task_id int, user_id int, att task.Attachment, content io.Reader) (task.Attachment, error) {
+	found, err := ad.tasks.editable(task_id, user_id, 0)
+	if err != nil {
+		return att, base.NotFoundAs(err, ErrAttachmentNotFound)
+	}
+
+	key, err := storageKey(task_id)
+	if err != nil {
+		return att, err
+	}
+
+	hash := sha256.New()
+	counted := &countingReader{reader: io.TeeReader(io.LimitReader(content, att.Size+1), hash)}
+	if err := ad.store.Put(key, counted, att.Size, att.ContentType); err != nil {
+		return att, err
+	}
+	if counted.count != att.Size {
+		ad.remove(key)
+		return att, ErrSizeMismatch
+	}
+
+	att.Task_ID = found.ID
+	att.UploadedBy = uint(user_id)
+	att.Checksum = hex.EncodeToString(hash.Sum(nil))
+	att.StorageKey = key
+	if err := ad.tasks.db.Create(&att).Error; err != nil {
+		ad.remove(key)
+		return att, err
+	}
+	return att, nil
+}
+
+func (ad *AttachmentDb) GetAll(task_id int, user_id int) ([]response.AttachmentResponse, error) {
+	if _, err := ad.tasks.GetById(task_id, user_id); err != nil {
+		return nil, base.NotFoundAs(err, ErrAttachmentNotFound)
+	}
+
+	rows := []task.Attachment{}
+	if err := ad.tasks.db.Where("task_id = ? AND orphaned_at IS NULL", task_id).Order("id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	attachments := []response.AttachmentResponse{}
+	for _, row := range rows {
+		attachments = append(attachments, row.ToAttachmentResponse())
+	}
+	return attachments, nil
+}
+
+// Open returns the attachment with its content, the caller closes the content
+func (ad *AttachmentDb) Open(task_id int, id int, user_id int) (task.Attachment, io.ReadCloser, error) {
+	att := task.Attachment{}
+
+	if _, err := ad.tasks.GetById(task_id, user_id); err != nil {
+		return att, nil, base.NotFoundAs(err, ErrAttachmentNotFound)
+	}
+	if err := ad.tasks.db.Where("id = ? AND task_id = ? AND orphaned_at IS NULL", id, task_id).First(&att).Error; err != nil {
+		return att, nil, base.NotFoundAs(err, ErrAttachmentNotFound)
+	}
+
+	content, err := ad.store.Get(att.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return att, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return att, nil, err
+	}
+	return att, content, nil
+}
+
+// DeleteById keeps the row when the storage fails, so the file is never lost track of
+func (ad *AttachmentDb) DeleteById(task_id int, id int, user_id int) error {
+	if _, err := ad.tasks.editable(task_id, user_id, 0); err != nil {
+		return base.NotFoundAs(err, ErrAttachmentNotFound)
+	}
+
+	return ad.tasks.db.Transaction(func(tx *gorm.DB) error {
+		att := task.Attachment{}
+		if err := tx.Where("id = ? AND task_id = ? AND orphaned_at IS NULL", id, task_id).First(&att).Error; err != nil {
+			return base.NotFoundAs(err, ErrAttachmentNotFound)
+		}
+		if err := tx.Delete(&att).Error; err != nil {
+			return err
+		}
+		return ad.store.Delete(att.StorageKey)
+	})
+}
+
+// PurgeOrphans removes the files of deleted tasks and returns how many went
+func (ad *AttachmentDb) PurgeOrphans() (int, error) {
+	rows := []task.Attachment{}
+	if err := ad.tasks.db.Where("orphaned_at IS NOT NULL").Order("id").Find(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, row := range rows {
+		if err := ad.store.Delete(row.StorageKey); err != nil {
+			return purged, err
+		}
+		if err := ad.tasks.db.Delete(&row).Error; err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// PurgeEvery purges the orphaned files on a fixed interval until stop is closed
+func (ad *AttachmentDb) PurgeEvery(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if purged, err := ad.PurgeOrphans(); err != nil {
+				log.Warn("purge orphaned attachments ", err)
+			} else if purged > 0 {
+				log.Info("purged orphaned attachments ", purged)
+			}
+		}
+	}
+}
+
+func (ad *AttachmentDb) remove(key string) {
+	if err := ad.store.Delete(key); err != nil {
+		log.Warn("remove attachment ", key, " ", err)
+	}
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	cr.count += int64(n)
+	return n, err
+}
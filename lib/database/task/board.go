@@ -0,0 +1,338 @@
+package task
+
+import (
+	"errors"
+	"part3/lib/database/project"
+	"part3/lib/rank"
+	"part3/models/base"
+	"part3/models/board"
+	boardReq "part3/models/board/request"
+	boardResp "part3/models/board/response"
+	_project "part3/models/project"
+	"part3/models/task"
+	"part3/models/task/request"
+	"part3/models/task/response"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidSection  = base.ValidationError("invalid_section", "section must be in the same project")
+	ErrInvalidPosition = base.ValidationError("invalid_position", "task can only be moved next to another task of its project")
+	ErrInvalidBoard    = base.ValidationError("invalid_board", "unknown board grouping")
+	ErrSectionNotFound = base.NotFoundError("section_not_found", "section not found")
+)
+
+// appendRank is a rank after every task of the project, the last task is locked
+// so two tasks appended at the same time do not get the same rank
+func appendRank(tx *gorm.DB, project_id uint) (string, error) {
+	last := []task.Task{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Unscoped().
+		Where("project_id = ? AND board_rank <> ''", project_id).
+		Order("board_rank desc").Limit(1).
+		Find(&last).Error
+	if err != nil {
+		return "", err
+	}
+	if len(last) == 0 {
+		return rank.Between("", "")
+	}
+	return rank.Between(last[0].Rank, "")
+}
+
+// neighbourRank is the first rank of the project past key, going up or down,
+// the moved task itself is skipped
+func neighbourRank(tx *gorm.DB, project_id uint, id uint, key string, up bool) (string, error) {
+	found := []task.Task{}
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Unscoped().Where("project_id = ? AND id <> ?", project_id, id)
+	if up {
+		query = query.Where("board_rank > ?", key).Order("board_rank")
+	} else {
+		query = query.Where("board_rank < ? AND board_rank <> ''", key).Order("board_rank desc")
+	}
+	if err := query.Limit(1).Find(&found).Error; err != nil {
+		return "", err
+	}
+	if len(found) == 0 {
+		return "", nil
+	}
+	return found[0].Rank, nil
+}
+
+// moveRank is the rank putting the task right before or right after the
+// neighbour, only the neighbours are locked so the rest of the column is free
+// for other moves
+func moveRank(tx *gorm.DB, upTask *task.Task, move request.MoveRequest) (string, error) {
+	neighbour_id := move.After_id
+	if move.Before_id != 0 {
+		neighbour_id = move.Before_id
+	}
+	if neighbour_id == 0 {
+		return appendRank(tx, upTask.Project_id)
+	}
+
+	neighbour := task.Task{}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", neighbour_id).First(&neighbour).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrInvalidPosition
+		}
+		return "", err
+	}
+	if neighbour.ID == upTask.ID || neighbour.Project_id != upTask.Project_id || neighbour.Rank == "" {
+		return "", ErrInvalidPosition
+	}
+
+	if move.After_id != 0 {
+		next, err := neighbourRank(tx, upTask.Project_id, upTask.ID, neighbour.Rank, true)
+		if err != nil {
+			return "", err
+		}
+		return rank.Between(neighbour.Rank, next)
+	}
+	prev, err := neighbourRank(tx, upTask.Project_id, upTask.ID, neighbour.Rank, false)
+	if err != nil {
+		return "", err
+	}
+	return rank.Between(prev, neighbour.Rank)
+}
+
+// Move puts the task in another column and at another place of its board in
+// one transaction, only the moved task gets a new rank
+func (td *TaskDb) Move(id int, user_id int, move request.MoveRequest) (task.Task, error) {
+	upTask := task.Task{}
+
+	err := td.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&upTask).Error; err != nil {
+			return err
+		}
+		if _, err := project.Authorize(tx, upTask.Project_id, user_id, _project.EditRoles...); err != nil {
+			return err
+		}
+
+		section_id := upTask.Section_id
+		if move.Section_id != nil {
+			section_id = nil
+			if *move.Section_id != 0 {
+				section := board.Section{}
+				if err := tx.Where("id = ? AND project_id = ?", *move.Section_id, upTask.Project_id).First(&section).Error; err != nil {
+					if errors.Is(err, gorm.ErrRecordNotFound) {
+						return ErrInvalidSection
+					}
+					return err
+				}
+				section_id = &section.ID
+			}
+		}
+
+		key, err := moveRank(tx, &upTask, move)
+		if err != nil {
+			return err
+		}
+		upTask.Section_id = section_id
+		upTask.Rank = key
+		err = tx.Model(&upTask).Updates(map[string]interface{}{
+			"section_id": upTask.Section_id,
+			"board_rank": upTask.Rank,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if move.Status == "" || move.Status == upTask.Status {
+			return nil
+		}
+		wf, err := project.LoadWorkflow(tx, upTask.Project_id)
+		if err != nil {
+			return err
+		}
+		if !wf.Has(move.Status) {
+			return ErrUnknownStatus
+		}
+		return td.setStatus(tx, &upTask, wf, move.Status, user_id, []task.Task{})
+	})
+	if err != nil {
+		return task.Task{}, base.NotFoundAs(err, ErrTaskNotFound)
+	}
+
+	return upTask, nil
+}
+
+type BoardDb struct {
+	tasks *TaskDb
+}
+
+func NewBoard(db *gorm.DB) *BoardDb {
+	return &BoardDb{tasks: New(db)}
+}
+
+// GetBoard returns every task of the project in board order, grouped in one
+// column per workflow status or per section
+func (bd *BoardDb) GetBoard(project_id int, user_id int, by string) (boardResp.BoardResponse, error) {
+	if by == "" {
+		by = board.ByStatus
+	}
+	if by != board.ByStatus && by != board.BySection {
+		return boardResp.BoardResponse{}, ErrInvalidBoard
+	}
+	if _, err := project.Authorize(bd.tasks.db, uint(project_id), user_id, _project.MemberRoles...); err != nil {
+		return boardResp.BoardResponse{}, base.NotFoundAs(err, ErrSectionNotFound)
+	}
+
+	tasks := []response.TaskResponse{}
+	err := bd.tasks.db.Model(&task.Task{}).
+		Joins("inner join projects on projects.id = tasks.project_id AND projects.deleted_at IS NULL").
+		Where("tasks.project_id = ?", project_id).
+		Select(taskRespSelect).
+		Order("tasks.board_rank").Order("tasks.id").
+		Find(&tasks).Error
+	if err != nil {
+		return boardResp.BoardResponse{}, err
+	}
+	if err := loadDetails(bd.tasks.db, tasks); err != nil {
+		return boardResp.BoardResponse{}, err
+	}
+
+	columns := []boardResp.ColumnResponse{}
+	if by == board.ByStatus {
+		wf, err := project.LoadWorkflow(bd.tasks.db, uint(project_id))
+		if err != nil {
+			return boardResp.BoardResponse{}, err
+		}
+		for _, status := range wf.Statuses {
+			columns = append(columns, boardResp.ColumnResponse{Key: status.Name, Name: status.Name, Tasks: []response.TaskResponse{}})
+		}
+	} else {
+		sections := []board.Section{}
+		if err := bd.tasks.db.Where("project_id = ?", project_id).Order("position").Order("id").Find(&sections).Error; err != nil {
+			return boardResp.BoardResponse{}, err
+		}
+		columns = append(columns, boardResp.ColumnResponse{Key: "none", Name: "No section", Tasks: []response.TaskResponse{}})
+		for i := range sections {
+			columns = append(columns, boardResp.ColumnResponse{
+				Key:        sectionKey(sections[i].ID),
+				Name:       sections[i].Name,
+				Section_id: &sections[i].ID,
+				Tasks:      []response.TaskResponse{},
+			})
+		}
+	}
+
+	index := map[string]int{}
+	for i, column := range columns {
+		index[column.Key] = i
+	}
+	for _, t := range tasks {
+		key := t.Status
+		if by == board.BySection {
+			key = "none"
+			if t.Section_id != nil {
+				key = sectionKey(*t.Section_id)
+			}
+		}
+		// a status dropped from the workflow still shows its tasks
+		i, ok := index[key]
+		if !ok {
+			i = len(columns)
+			index[key] = i
+			columns = append(columns, boardResp.ColumnResponse{Key: key, Name: key, Tasks: []response.TaskResponse{}})
+		}
+		columns[i].Tasks = append(columns[i].Tasks, t)
+	}
+
+	return boardResp.BoardResponse{By: by, Columns: columns}, nil
+}
+
+func sectionKey(id uint) string {
+	return "section-" + strconv.FormatUint(uint64(id), 10)
+}
+
+func (bd *BoardDb) GetSections(project_id int, user_id int) ([]boardResp.SectionResponse, error) {
+	sections := []boardResp.SectionResponse{}
+
+	if _, err := project.Authorize(bd.tasks.db, uint(project_id), user_id, _project.MemberRoles...); err != nil {
+		return sections, base.NotFoundAs(err, ErrSectionNotFound)
+	}
+
+	err := bd.tasks.db.Model(&board.Section{}).Select("id as id, name as name, position as position").
+		Where("project_id = ?", project_id).
+		Order("position").Order("id").
+		Find(&sections).Error
+	return sections, err
+}
+
+// CreateSection puts the section after the others unless a position is given
+func (bd *BoardDb) CreateSection(project_id int, user_id int, sectionReq boardReq.SectionRequest) (board.Section, error) {
+	if _, err := project.Authorize(bd.tasks.db, uint(project_id), user_id, _project.EditRoles...); err != nil {
+		return board.Section{}, base.NotFoundAs(err, ErrSectionNotFound)
+	}
+
+	section := sectionReq.ToSection()
+	section.Project_ID = uint(project_id)
+	if sectionReq.Position == nil {
+		var last struct{ Position *int }
+		if err := bd.tasks.db.Model(&board.Section{}).Select("max(position) as position").Where("project_id = ?", project_id).Scan(&last).Error; err != nil {
+			return board.Section{}, err
+		}
+		if last.Position != nil {
+			section.Position = *last.Position + 1
+		}
+	}
+
+	if err := bd.tasks.db.Create(&section).Error; err != nil {
+		return board.Section{}, err
+	}
+	return section, nil
+}
+
+func (bd *BoardDb) UpdateSection(project_id int, id int, user_id int, sectionReq boardReq.SectionRequest) (board.Section, error) {
+	section, err := bd.editableSection(project_id, id, user_id)
+	if err != nil {
+		return section, err
+	}
+
+	upSection := sectionReq.ToSection()
+	updates := map[string]interface{}{}
+	if upSection.Name != "" {
+		updates["name"] = upSection.Name
+	}
+	if sectionReq.Position != nil {
+		updates["position"] = upSection.Position
+	}
+	if len(updates) > 0 {
+		if err := bd.tasks.db.Model(&section).Updates(updates).Error; err != nil {
+			return board.Section{}, err
+		}
+	}
+
+	if err := bd.tasks.db.First(&section, section.ID).Error; err != nil {
+		return board.Section{}, base.NotFoundAs(err, ErrSectionNotFound)
+	}
+	return section, nil
+}
+
+// DeleteSection takes its tasks out of the section, they stay on the board
+func (bd *BoardDb) DeleteSection(project_id int, id int, user_id int) error {
+	section, err := bd.editableSection(project_id, id, user_id)
+	if err != nil {
+		return err
+	}
+
+	return bd.tasks.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&task.Task{}).Unscoped().Where("section_id = ?", section.ID).Update("section_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&section).Error
+	})
+}
+
+func (bd *BoardDb) editableSection(project_id int, id int, user_id int) (board.Section, error) {
+	section := board.Section{}
+
+	if _, err := project.Authorize(bd.tasks.db, uint(project_id), user_id, _project.EditRoles...); err != nil {
+		return section, base.NotFoundAs(err, ErrSectionNotFound)
+	}
+	err := bd.tasks.db.Where("id = ? AND project_id = ?", id, project_id).First(&section).Error
+	return section, base.NotFoundAs(err, ErrSectionNotFound)
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"part3/models/base"
+	"part3/models/task"
+	"part3/models/task/request"
+	"part3/models/task/response"
+)
+
+var ErrChecklistItemNotFound = base.NotFoundError("checklist_item_not_found", "task or checklist item not found")
+
+func (td *TaskDb) GetChecklist(id int, user_id int) ([]response.ChecklistItemResponse, error) {
+	if _, err := td.GetById(id, user_id); err != nil {
+		return nil, base.NotFoundAs(err, ErrTaskNotFound)
+	}
+
+	items := []task.ChecklistItem{}
+	if err := td.db.Where("task_id = ?", id).Order("position").Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	checklist := []response.ChecklistItemResponse{}
+	for _, item := range items {
+		checklist = append(checklist, item.ToChecklistItemResponse())
+	}
+	return checklist, nil
+}
+
+// AddChecklistItem appends the item at the end of the checklist
+func (td *TaskDb) AddChecklistItem(id int, user_id int, item task.ChecklistItem) (task.ChecklistItem, error) {
+	found, err := td.editable(id, user_id, 0)
+	if err != nil {
+		return item, base.NotFoundAs(err, ErrTaskNotFound)
+	}
+
+	var last int64
+	if err := td.db.Model(&task.ChecklistItem{}).Where("task_id = ?", found.ID).Count(&last).Error; err != nil {
+		return item, err
+	}
+
+	item.Task_ID = found.ID
+	item.Position = int(last)
+	if err := td.db.Create(&item).Error; err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+func (td *TaskDb) UpdateChecklistItem(id int, user_id int, item_id int, itemReq request.ChecklistRequest) (task.ChecklistItem, error) {
+	item := task.ChecklistItem{}
+
+	if _, err := td.editable(id, user_id, 0); err != nil {
+		return item, base.NotFoundAs(err, ErrChecklistItemNotFound)
+	}
+	if err := td.db.Where("id = ? AND task_id = ?", item_id, id).First(&item).Error; err != nil {
+		return item, base.NotFoundAs(err, ErrChecklistItemNotFound)
+	}
+
+	changes := map[string]interface{}{}
+	if itemReq.Name != "" {
+		item.Name = itemReq.Name
+		changes["name"] = item.Name
+	}
+	if itemReq.Done != nil {
+		item.Done = *itemReq.Done
+		changes["done"] = item.Done
+	}
+	if itemReq.Position != nil {
+		item.Position = *itemReq.Position
+		changes["position"] = item.Position
+	}
+	if len(changes) > 0 {
+		if err := td.db.Model(&item).Updates(changes).Error; err != nil {
+			return item, err
+		}
+	}
+	return item, nil
+}
+
+func (td *TaskDb) DeleteChecklistItem(id int, user_id int, item_id int) error {
+	if _, err := td.editable(id, user_id, 0); err != nil {
+		return base.NotFoundAs(err, ErrChecklistItemNotFound)
+	}
+
+	res := td.db.Where("id = ? AND task_id = ?", item_id, id).Delete(&task.ChecklistItem{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrChecklistItemNotFound
+	}
+	return nil
+}
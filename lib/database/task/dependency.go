@@ -0,0 +1,278 @@
+package task
+
+import (
+	"errors"
+	"math"
+	"part3/lib/database/project"
+	"part3/models/base"
+	_project "part3/models/project"
+	"part3/models/task"
+	"part3/models/task/response"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidDependency  = base.ValidationError("invalid_dependency", "a task can only depend on another task of its project")
+	ErrDependencyCycle    = base.ConflictError("dependency_cycle", "dependency would create a cycle")
+	ErrBlocked            = base.ConflictError("task_blocked", "task is blocked by open tasks")
+	ErrDependencyNotFound = base.NotFoundError("dependency_not_found", "task or dependency not found")
+)
+
+const dependencyTaskSelect = "tasks.id as id, tasks.name as name, tasks.status as status, tasks.completed_at as completed_at"
+
+type DependencyDb struct {
+	tasks *TaskDb
+}
+
+func NewDependencies(db *gorm.DB) *DependencyDb {
+	return &DependencyDb{tasks: New(db)}
+}
+
+func (dd *DependencyDb) GetAll(task_id int, user_id int) (response.DependenciesResponse, error) {
+	if _, err := dd.tasks.GetById(task_id, user_id); err != nil {
+		return response.DependenciesResponse{}, base.NotFoundAs(err, ErrDependencyNotFound)
+	}
+	return dependencies(dd.tasks.db, uint(task_id))
+}
+
+func dependencies(db *gorm.DB, task_id uint) (response.DependenciesResponse, error) {
+	deps := response.DependenciesResponse{
+		Blocked_by: []response.DependencyTaskResponse{},
+		Blocks:     []response.DependencyTaskResponse{},
+	}
+
+	err := db.Model(&task.Task{}).Select(dependencyTaskSelect).
+		Joins("inner join task_dependencies on task_dependencies.blocker_id = tasks.id").
+		Where("task_dependencies.blocked_id = ?", task_id).
+		Order("tasks.id").
+		Find(&deps.Blocked_by).Error
+	if err != nil {
+		return deps, err
+	}
+
+	err = db.Model(&task.Task{}).Select(dependencyTaskSelect).
+		Joins("inner join task_dependencies on task_dependencies.blocked_id = tasks.id").
+		Where("task_dependencies.blocker_id = ?", task_id).
+		Order("tasks.id").
+		Find(&deps.Blocks).Error
+	return deps, err
+}
+
+// Create records that blocker_id blocks the task, refusing an edge that would
+// let a task wait on itself through a chain of dependencies. The tasks of the
+// project are locked first, two edges added at the same time could otherwise
+// each pass the check and close a cycle together
+func (dd *DependencyDb) Create(task_id int, user_id int, blocker_id uint) (response.DependenciesResponse, error) {
+	found, err := dd.tasks.editable(task_id, user_id, 0)
+	if err != nil {
+		return response.DependenciesResponse{}, base.NotFoundAs(err, ErrDependencyNotFound)
+	}
+
+	err = dd.tasks.db.Transaction(func(tx *gorm.DB) error {
+		locked := []uint{}
+		err := tx.Model(&task.Task{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("project_id = ?", found.Project_id).
+			Order("id").
+			Pluck("id", &locked).Error
+		if err != nil {
+			return err
+		}
+
+		blocker := task.Task{}
+		if err := tx.Where("id = ?", blocker_id).First(&blocker).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidDependency
+			}
+			return err
+		}
+		if blocker.ID == found.ID || blocker.Project_id != found.Project_id {
+			return ErrInvalidDependency
+		}
+
+		// the new edge closes a cycle when the blocker already waits on the task
+		reached, err := reachable(tx, found.ID, blocker.ID)
+		if err != nil {
+			return err
+		}
+		if reached {
+			return ErrDependencyCycle
+		}
+
+		dep := task.Dependency{Blocker_ID: blocker.ID, Blocked_ID: found.ID, CreatedBy: uint(user_id)}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&dep).Error
+	})
+	if err != nil {
+		return response.DependenciesResponse{}, err
+	}
+
+	return dependencies(dd.tasks.db, found.ID)
+}
+
+func (dd *DependencyDb) DeleteById(task_id int, blocker_id int, user_id int) error {
+	if _, err := dd.tasks.editable(task_id, user_id, 0); err != nil {
+		return base.NotFoundAs(err, ErrDependencyNotFound)
+	}
+
+	res := dd.tasks.db.Where("blocked_id = ? AND blocker_id = ?", task_id, blocker_id).Delete(&task.Dependency{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrDependencyNotFound
+	}
+	return nil
+}
+
+// reachable follows the dependencies from the blocker side, one level per query
+func reachable(db *gorm.DB, from uint, to uint) (bool, error) {
+	seen := map[uint]bool{from: true}
+
+	for level := []uint{from}; len(level) > 0; {
+		deps := []task.Dependency{}
+		if err := db.Where("blocker_id IN ?", level).Find(&deps).Error; err != nil {
+			return false, err
+		}
+		level = []uint{}
+		for _, dep := range deps {
+			if dep.Blocked_ID == to {
+				return true, nil
+			}
+			if !seen[dep.Blocked_ID] {
+				seen[dep.Blocked_ID] = true
+				level = append(level, dep.Blocked_ID)
+			}
+		}
+	}
+	return false, nil
+}
+
+// checkBlockers fails when one of the tasks still waits on an open task,
+// blockers completed in the same change do not count
+func checkBlockers(db *gorm.DB, ids []uint) error {
+	var open int64
+	err := db.Model(&task.Dependency{}).
+		Joins("inner join tasks on tasks.id = task_dependencies.blocker_id AND tasks.deleted_at IS NULL").
+		Where("task_dependencies.blocked_id IN ? AND task_dependencies.blocker_id NOT IN ? AND tasks.completed_at IS NULL", ids, ids).
+		Count(&open).Error
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return ErrBlocked
+	}
+	return nil
+}
+
+// GetGraph returns every task of the project with the dependencies between them
+func (dd *DependencyDb) GetGraph(project_id int, user_id int) (response.GraphResponse, error) {
+	graph := response.GraphResponse{
+		Nodes:         []response.GraphNodeResponse{},
+		Edges:         []response.GraphEdgeResponse{},
+		Critical_path: []uint{},
+	}
+
+	if _, err := project.Authorize(dd.tasks.db, uint(project_id), user_id, _project.MemberRoles...); err != nil {
+		return graph, base.NotFoundAs(err, ErrDependencyNotFound)
+	}
+
+	err := dd.tasks.db.Model(&task.Task{}).
+		Select("tasks.id as id, tasks.name as name, tasks.status as status, tasks.start_at as start_at, tasks.due_at as due_at, tasks.completed_at as completed_at").
+		Where("tasks.project_id = ?", project_id).
+		Order("tasks.id").
+		Find(&graph.Nodes).Error
+	if err != nil {
+		return graph, err
+	}
+
+	err = dd.tasks.db.Model(&task.Dependency{}).
+		Select("task_dependencies.blocker_id as blocker_id, task_dependencies.blocked_id as blocked_id").
+		Joins("inner join tasks blockers on blockers.id = task_dependencies.blocker_id AND blockers.deleted_at IS NULL").
+		Joins("inner join tasks blocked on blocked.id = task_dependencies.blocked_id AND blocked.deleted_at IS NULL").
+		Where("blockers.project_id = ? AND blocked.project_id = ?", project_id, project_id).
+		Order("task_dependencies.blocker_id").
+		Order("task_dependencies.blocked_id").
+		Find(&graph.Edges).Error
+	if err != nil {
+		return graph, err
+	}
+
+	for i := range graph.Nodes {
+		graph.Nodes[i].Duration = remainingDays(graph.Nodes[i])
+	}
+	graph.Critical_path, graph.Critical_length = criticalPath(graph.Nodes, graph.Edges)
+	return graph, nil
+}
+
+// remainingDays is 0 for a done task, the days from start to due otherwise and 1
+// for a task without both dates
+func remainingDays(node response.GraphNodeResponse) int {
+	if node.CompletedAt != nil {
+		return 0
+	}
+	if node.StartAt == nil || node.DueAt == nil || !node.DueAt.After(*node.StartAt) {
+		return 1
+	}
+	return int(math.Ceil(node.DueAt.Sub(*node.StartAt).Hours() / 24))
+}
+
+// criticalPath is the longest chain of the DAG weighted by the node durations,
+// ties go to the chain ending on the lowest task id
+func criticalPath(nodes []response.GraphNodeResponse, edges []response.GraphEdgeResponse) ([]uint, int) {
+	duration := map[uint]int{}
+	for _, node := range nodes {
+		duration[node.ID] = node.Duration
+	}
+	next := map[uint][]uint{}
+	waiting := map[uint]int{}
+	for _, edge := range edges {
+		next[edge.Blocker_id] = append(next[edge.Blocker_id], edge.Blocked_id)
+		waiting[edge.Blocked_id]++
+	}
+
+	ready := []uint{}
+	for _, node := range nodes {
+		if waiting[node.ID] == 0 {
+			ready = append(ready, node.ID)
+		}
+	}
+
+	length := map[uint]int{}
+	prev := map[uint]uint{}
+	end, best := uint(0), -1
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return ready[i] < ready[j] })
+		id := ready[0]
+		ready = ready[1:]
+
+		length[id] += duration[id]
+		if length[id] > best || (length[id] == best && id < end) {
+			end, best = id, length[id]
+		}
+		for _, blocked := range next[id] {
+			if _, ok := prev[blocked]; !ok || length[id] > length[blocked] {
+				length[blocked] = length[id]
+				prev[blocked] = id
+			}
+			if waiting[blocked]--; waiting[blocked] == 0 {
+				ready = append(ready, blocked)
+			}
+		}
+	}
+
+	if best < 0 {
+		return []uint{}, 0
+	}
+	path := []uint{end}
+	for id := end; ; {
+		before, ok := prev[id]
+		if !ok {
+			break
+		}
+		path = append([]uint{before}, path...)
+		id = before
+	}
+	return path, best
+}
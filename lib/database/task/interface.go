@@ -1,9 +1,15 @@
 package task
 
 import (
+	"io"
+	"part3/models/base"
+	"part3/models/board"
+	boardReq "part3/models/board/request"
+	boardResp "part3/models/board/response"
 	"part3/models/task"
 	"part3/models/task/request"
 	"part3/models/task/response"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -12,8 +18,40 @@ type Task interface {
 	Create(user_id int, newTask task.Task) (task.Task, error)
 	UpdateById(id int, user_id int, taskReg request.TaskRequest) (task.Task, error)
 	DeleteById(id int, user_id int) (gorm.DeletedAt, error)
-	GetAll(user_id int) ([]response.TaskResponse, error)
+	GetAll(user_id int, filter request.TaskFilter, page base.Page) ([]response.TaskResponse, base.Meta, error)
+	GetAgenda(user_id int, now time.Time, loc *time.Location, days int) (response.AgendaResponse, error)
 	GetByIdResp(id int, user_id int) (response.TaskResponse, error)
-	TaskCompleted(id int, user_id int, taskRequest request.TaskRequest) (task.Task, error)
-	TaskReopened(id int, user_id int, taskRequest request.TaskRequest) (task.Task, error)
+	TaskCompleted(id int, user_id int, cascade bool) (task.Task, error)
+	TaskReopened(id int, user_id int) (task.Task, error)
+	Transition(id int, user_id int, status string) (task.Task, error)
+	GetAssignees(id int, user_id int) ([]response.AssigneeResponse, error)
+	AddAssignees(id int, user_id int, assignees []uint) ([]response.AssigneeResponse, error)
+	RemoveAssignee(id int, user_id int, assignee_id int) error
+	GetChecklist(id int, user_id int) ([]response.ChecklistItemResponse, error)
+	AddChecklistItem(id int, user_id int, item task.ChecklistItem) (task.ChecklistItem, error)
+	UpdateChecklistItem(id int, user_id int, item_id int, itemReq request.ChecklistRequest) (task.ChecklistItem, error)
+	DeleteChecklistItem(id int, user_id int, item_id int) error
+	Move(id int, user_id int, move request.MoveRequest) (task.Task, error)
+}
+
+type Attachment interface {
+	Create(task_id int, user_id int, att task.Attachment, content io.Reader) (task.Attachment, error)
+	GetAll(task_id int, user_id int) ([]response.AttachmentResponse, error)
+	Open(task_id int, id int, user_id int) (task.Attachment, io.ReadCloser, error)
+	DeleteById(task_id int, id int, user_id int) error
+}
+
+type Dependency interface {
+	GetAll(task_id int, user_id int) (response.DependenciesResponse, error)
+	Create(task_id int, user_id int, blocker_id uint) (response.DependenciesResponse, error)
+	DeleteById(task_id int, blocker_id int, user_id int) error
+	GetGraph(project_id int, user_id int) (response.GraphResponse, error)
+}
+
+type Board interface {
+	GetBoard(project_id int, user_id int, by string) (boardResp.BoardResponse, error)
+	GetSections(project_id int, user_id int) ([]boardResp.SectionResponse, error)
+	CreateSection(project_id int, user_id int, sectionReq boardReq.SectionRequest) (board.Section, error)
+	UpdateSection(project_id int, id int, user_id int, sectionReq boardReq.SectionRequest) (board.Section, error)
+	DeleteSection(project_id int, id int, user_id int) error
 }
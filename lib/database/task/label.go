@@ -0,0 +1,57 @@
+package task
+
+import (
+	"part3/models/label"
+	labelResp "part3/models/label/response"
+	"part3/models/task/response"
+
+	"gorm.io/gorm"
+)
+
+func uniqueStrings(values []string) []string {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// loadLabels fills the labels of every task in one query
+func loadLabels(db *gorm.DB, tasks []response.TaskResponse) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := []uint{}
+	index := map[uint][]int{}
+	for i := range tasks {
+		tasks[i].Labels = []labelResp.LabelResponse{}
+		ids = append(ids, tasks[i].ID)
+		index[tasks[i].ID] = append(index[tasks[i].ID], i)
+	}
+
+	rows := []struct {
+		Task_id uint
+		labelResp.LabelResponse
+	}{}
+	err := db.Model(&label.TaskLabel{}).
+		Select("task_labels.task_id as task_id, labels.id as id, labels.name as name, labels.colour as colour").
+		Joins("inner join labels on labels.id = task_labels.label_id").
+		Where("task_labels.task_id IN ?", ids).
+		Order("labels.name").
+		Find(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		for _, i := range index[row.Task_id] {
+			tasks[i].Labels = append(tasks[i].Labels, row.LabelResponse)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package task
+
+import (
+	"part3/lib/recurrence"
+	"part3/models/label"
+	"part3/models/task"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// recur creates the occurrence following a completed recurring task, with the
+// same assignees and labels and its checklist unchecked. A task creates its next
+// occurrence once, completing it again after a reopen does not add another
+func recur(tx *gorm.DB, done *task.Task, initial string) error {
+	if done.Recurrence == "" || done.Next_id != nil || done.CompletedAt == nil {
+		return nil
+	}
+
+	rule, err := recurrence.Parse(done.Recurrence)
+	if err != nil {
+		return err
+	}
+	loc, err := time.LoadLocation(done.Timezone)
+	if err != nil {
+		return err
+	}
+	start, due, ok := rule.Roll(done.StartAt, done.DueAt, *done.CompletedAt, loc)
+	if !ok {
+		return nil
+	}
+
+	next := task.Task{
+		CreatedBy:       done.CreatedBy,
+		Name:            done.Name,
+		Status:          initial,
+		StatusChangedAt: time.Now(),
+		StartAt:         start,
+		DueAt:           due,
+		Priority:        done.Priority,
+		Project_id:      done.Project_id,
+		Parent_id:       done.Parent_id,
+		Recurrence:      done.Recurrence,
+		Timezone:        done.Timezone,
+	}
+	if next.Rank, err = appendRank(tx, next.Project_id); err != nil {
+		return err
+	}
+	if err := tx.Create(&next).Error; err != nil {
+		return err
+	}
+
+	assignees := []task.Assignee{}
+	if err := tx.Where("task_id = ?", done.ID).Find(&assignees).Error; err != nil {
+		return err
+	}
+	for i := range assignees {
+		assignees[i].ID = 0
+		assignees[i].CreatedAt = time.Time{}
+		assignees[i].Task_ID = next.ID
+	}
+	if len(assignees) > 0 {
+		if err := tx.Create(&assignees).Error; err != nil {
+			return err
+		}
+	}
+
+	labels := []label.TaskLabel{}
+	if err := tx.Where("task_id = ?", done.ID).Find(&labels).Error; err != nil {
+		return err
+	}
+	for i := range labels {
+		labels[i].ID = 0
+		labels[i].CreatedAt = time.Time{}
+		labels[i].Task_ID = next.ID
+	}
+	if len(labels) > 0 {
+		if err := tx.Create(&labels).Error; err != nil {
+			return err
+		}
+	}
+
+	items := []task.ChecklistItem{}
+	if err := tx.Where("task_id = ?", done.ID).Find(&items).Error; err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].ID = 0
+		items[i].CreatedAt = time.Time{}
+		items[i].UpdatedAt = time.Time{}
+		items[i].Task_ID = next.ID
+		items[i].Done = false
+	}
+	if len(items) > 0 {
+		if err := tx.Create(&items).Error; err != nil {
+			return err
+		}
+	}
+
+	done.Next_id = &next.ID
+	return tx.Model(done).Update("next_id", next.ID).Error
+}
+
+// anchorRule writes the day of the first occurrence into a monthly rule, so the
+// occurrences after a shorter month come back to it. The day is taken again
+// when the date the rule follows has moved
+func anchorRule(t *task.Task, moved bool) error {
+	first := ruleDate(t)
+	if t.Recurrence == "" || first == nil {
+		return nil
+	}
+
+	rule, err := recurrence.Parse(t.Recurrence)
+	if err != nil {
+		return err
+	}
+	if rule.Start != "" && !moved {
+		return nil
+	}
+	loc, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		return err
+	}
+	t.Recurrence = rule.Anchored(*first, loc).String()
+	return nil
+}
+
+// ruleDate is the date the rule follows, the due date or else the start date
+func ruleDate(t *task.Task) *time.Time {
+	if t.DueAt != nil {
+		return t.DueAt
+	}
+	return t.StartAt
+}
@@ -0,0 +1,140 @@
+package task
+
+import (
+	"errors"
+	"part3/models/base"
+	"part3/models/task"
+	"part3/models/task/response"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidParent = base.ValidationError("invalid_parent", "parent task must be in the same project")
+	ErrTaskCycle     = base.ConflictError("task_cycle", "task cannot be its own ancestor")
+)
+
+// checkParent makes sure the parent lives in the same project and that hanging
+// the task under it does not close a loop, id is 0 for a task not created yet
+func checkParent(db *gorm.DB, id uint, parent_id uint, project_id uint) error {
+	parent := task.Task{}
+	if err := db.Where("id = ?", parent_id).First(&parent).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidParent
+		}
+		return err
+	}
+	if parent.Project_id != project_id {
+		return ErrInvalidParent
+	}
+
+	seen := map[uint]bool{}
+	for current := &parent; ; {
+		if current.ID == id {
+			return ErrTaskCycle
+		}
+		if current.Parent_id == nil || seen[current.ID] {
+			return nil
+		}
+		seen[current.ID] = true
+
+		next := task.Task{}
+		if err := db.Where("id = ?", *current.Parent_id).First(&next).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		current = &next
+	}
+}
+
+// descendants walks the subtask tree one level per query
+func descendants(db *gorm.DB, id uint) ([]task.Task, error) {
+	found := []task.Task{}
+	seen := map[uint]bool{id: true}
+
+	for level := []uint{id}; len(level) > 0; {
+		children := []task.Task{}
+		if err := db.Where("parent_id IN ?", level).Order("id").Find(&children).Error; err != nil {
+			return nil, err
+		}
+		level = []uint{}
+		for _, child := range children {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			found = append(found, child)
+			level = append(level, child.ID)
+		}
+	}
+	return found, nil
+}
+
+type progressRow struct {
+	Owner uint
+	Done  int
+	Total int
+}
+
+// loadProgress counts the direct subtasks and checklist items of every task,
+// two grouped queries whatever the number of tasks
+func loadProgress(db *gorm.DB, tasks []response.TaskResponse) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	ids := []uint{}
+	for i := range tasks {
+		ids = append(ids, tasks[i].ID)
+	}
+
+	subtasks := []progressRow{}
+	err := db.Model(&task.Task{}).
+		Select("parent_id as owner, SUM(CASE WHEN completed_at IS NOT NULL THEN 1 ELSE 0 END) as done, COUNT(*) as total").
+		Where("parent_id IN ?", ids).
+		Group("parent_id").
+		Find(&subtasks).Error
+	if err != nil {
+		return err
+	}
+
+	checklist := []progressRow{}
+	err = db.Model(&task.ChecklistItem{}).
+		Select("task_id as owner, SUM(CASE WHEN done = ? THEN 1 ELSE 0 END) as done, COUNT(*) as total", true).
+		Where("task_id IN ?", ids).
+		Group("task_id").
+		Find(&checklist).Error
+	if err != nil {
+		return err
+	}
+
+	bySubtasks := map[uint]progressRow{}
+	for _, row := range subtasks {
+		bySubtasks[row.Owner] = row
+	}
+	byChecklist := map[uint]progressRow{}
+	for _, row := range checklist {
+		byChecklist[row.Owner] = row
+	}
+
+	for i := range tasks {
+		sub, list := bySubtasks[tasks[i].ID], byChecklist[tasks[i].ID]
+		tasks[i].Subtasks = response.ProgressResponse{Done: sub.Done, Total: sub.Total}
+		tasks[i].Checklist = response.ProgressResponse{Done: list.Done, Total: list.Total}
+		tasks[i].Progress = response.ProgressResponse{Done: sub.Done + list.Done, Total: sub.Total + list.Total}
+	}
+	return nil
+}
+
+// loadDetails fills what a task response carries besides the task row itself
+func loadDetails(db *gorm.DB, tasks []response.TaskResponse) error {
+	if err := loadAssignees(db, tasks); err != nil {
+		return err
+	}
+	if err := loadLabels(db, tasks); err != nil {
+		return err
+	}
+	return loadProgress(db, tasks)
+}
@@ -1,115 +1,498 @@
 package task
 
 import (
-	"errors"
+	"fmt"
+	"part3/lib/database/paginate"
+	"part3/lib/database/project"
+	"part3/models/base"
+	"part3/models/label"
+	_project "part3/models/project"
 	"part3/models/task"
 	"part3/models/task/request"
 	"part3/models/task/response"
+	"part3/models/workflow"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const taskRespSelect = "tasks.id as id, tasks.created_at as created_at, tasks.updated_at as updated_at, tasks.name as name, tasks.created_by as created_by, tasks.status as status, tasks.status_changed_at as status_changed_at, tasks.completed_at as completed_at, tasks.completed_by as completed_by, tasks.start_at as start_at, tasks.due_at as due_at, tasks.project_id as project_id, tasks.parent_id as parent_id, tasks.recurrence as recurrence, tasks.timezone as timezone, tasks.next_id as next_id, tasks.section_id as section_id, tasks.board_rank as board_rank, tasks.priority as priority, projects.name as project_name"
+
+var (
+	ErrUnknownStatus     = base.ValidationError("unknown_status", "unknown status")
+	ErrIllegalTransition = base.ConflictError("illegal_transition", "illegal status transition")
+	ErrTaskNotFound      = base.NotFoundError("task_not_found", "task not found")
+	ErrProjectRequired   = base.ValidationError("project_required", "project_id is required")
 )
 
 type TaskDb struct {
-	db *gorm.DB
+	db              *gorm.DB
+	blockCompletion bool
 }
 
 func New(db *gorm.DB) *TaskDb {
-	return &TaskDb{db: db}
+	return &TaskDb{db: db, blockCompletion: true}
+}
+
+// SetBlockCompletion says whether a task waiting on open blockers can be completed
+func (td *TaskDb) SetBlockCompletion(block bool) {
+	td.blockCompletion = block
 }
 
 func (td *TaskDb) Create(user_id int, newTask task.Task) (task.Task, error) {
-	newTask.User_ID = uint(user_id)
-	if err := td.db.Create(&newTask).Error; err != nil {
+	newTask.CreatedBy = uint(user_id)
+
+	if _, err := project.Authorize(td.db, newTask.Project_id, user_id, _project.EditRoles...); err != nil {
+		return newTask, err
+	}
+	if newTask.Parent_id != nil && *newTask.Parent_id == 0 {
+		newTask.Parent_id = nil
+	}
+	if newTask.Parent_id != nil {
+		if err := checkParent(td.db, 0, *newTask.Parent_id, newTask.Project_id); err != nil {
+			return newTask, err
+		}
+	}
+
+	if err := anchorRule(&newTask, false); err != nil {
+		return newTask, err
+	}
+
+	wf, err := project.LoadWorkflow(td.db, newTask.Project_id)
+	if err != nil {
+		return newTask, err
+	}
+	newTask.Status = wf.Initial()
+	newTask.StatusChangedAt = time.Now()
+
+	err = td.db.Transaction(func(tx *gorm.DB) error {
+		if newTask.Rank, err = appendRank(tx, newTask.Project_id); err != nil {
+			return err
+		}
+		return tx.Create(&newTask).Error
+	})
+	if err != nil {
 		return newTask, err
 	}
 
 	return newTask, nil
 }
 
+// GetById returns the task when the user is a member of its project, whatever the role
 func (td *TaskDb) GetById(id int, user_id int) (task.Task, error) {
 	task := task.Task{}
 
-	if err := td.db.Model(&task).Where("id = ? AND user_id = ?", id, user_id).First(&task).Error; err != nil {
+	if err := project.MemberJoin(td.db.Model(&task), "tasks.project_id", user_id).Where("tasks.id = ?", id).First(&task).Error; err != nil {
 		return task, err
 	}
 
 	return task, nil
 }
 
+// editable loads the task and checks the user may change it, moving a task
+// to another project needs edit rights on both
+func (td *TaskDb) editable(id int, user_id int, project_id uint) (task.Task, error) {
+	found := task.Task{}
+
+	if err := td.db.Where("id = ?", id).First(&found).Error; err != nil {
+		return found, err
+	}
+	if _, err := project.Authorize(td.db, found.Project_id, user_id, _project.EditRoles...); err != nil {
+		return found, err
+	}
+	if project_id != 0 && project_id != found.Project_id {
+		if _, err := project.Authorize(td.db, project_id, user_id, _project.EditRoles...); err != nil {
+			return found, err
+		}
+	}
+	return found, nil
+}
+
+// UpdateById replaces the fields of the task with those of the request, a
+// missing date, rule or parent clears it and the project is required like the
+// rest of a replacement. The subtask tree stays whole, a task moved to another
+// project takes its subtasks along and leaves its parent unless it gets a new one there
 func (td *TaskDb) UpdateById(id int, user_id int, taskReg request.TaskRequest) (task.Task, error) {
+	if taskReg.Project_id == 0 {
+		return task.Task{}, ErrProjectRequired
+	}
+	found, err := td.editable(id, user_id, taskReg.Project_id)
+	if err != nil {
+		return task.Task{}, err
+	}
 
-	res := td.db.Model(task.Task{Model: gorm.Model{ID: uint(id)}, User_ID: uint(user_id)}).Updates(task.Task{Name: taskReg.Name, Priority: taskReg.Priority, Project_id: taskReg.Project_id})
+	upTask := taskReg.ToTask()
+	before, after := ruleDate(&found), ruleDate(&upTask)
+	moved := (before == nil) != (after == nil) || (before != nil && !before.Equal(*after))
+	if err := anchorRule(&upTask, moved); err != nil {
+		return task.Task{}, err
+	}
+	project_id := upTask.Project_id
+	parent_id := upTask.Parent_id
+	if parent_id != nil && (*parent_id == 0 || (project_id != found.Project_id && samePointer(parent_id, found.Parent_id))) {
+		parent_id = nil
+	}
+	if parent_id != nil {
+		if err := checkParent(td.db, found.ID, *parent_id, project_id); err != nil {
+			return task.Task{}, err
+		}
+	}
 
-	if res.RowsAffected == 0 {
-		return task.Task{}, errors.New(gorm.ErrRecordNotFound.Error())
+	err = td.db.Transaction(func(tx *gorm.DB) error {
+		// a map so the zero values are written too
+		res := tx.Model(&task.Task{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"name":       upTask.Name,
+			"priority":   upTask.Priority,
+			"project_id": project_id,
+			"parent_id":  parent_id,
+			"start_at":   upTask.StartAt,
+			"due_at":     upTask.DueAt,
+			"recurrence": upTask.Recurrence,
+			"timezone":   upTask.Timezone,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if project_id != found.Project_id {
+			children, err := descendants(tx, found.ID)
+			if err != nil {
+				return err
+			}
+			ids := []uint{}
+			for _, child := range children {
+				ids = append(ids, child.ID)
+			}
+			if len(ids) > 0 {
+				if err := tx.Model(&task.Task{}).Where("id IN ?", ids).Update("project_id", project_id).Error; err != nil {
+					return err
+				}
+			}
+			// labels and dependencies belong to the old project
+			moved := append(ids, found.ID)
+			if err := tx.Where("task_id IN ?", moved).Delete(&label.TaskLabel{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("blocker_id IN ? OR blocked_id IN ?", moved, moved).Delete(&task.Dependency{}).Error; err != nil {
+				return err
+			}
+			// sections too, the moved tasks go to the end of the new project's board
+			if err := tx.Model(&task.Task{}).Where("id IN ?", moved).Update("section_id", nil).Error; err != nil {
+				return err
+			}
+			for _, moved_id := range moved {
+				key, err := appendRank(tx, project_id)
+				if err != nil {
+					return err
+				}
+				if err := tx.Model(&task.Task{}).Where("id = ?", moved_id).Update("board_rank", key).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return task.Task{}, err
 	}
 
-	task := taskReg.ToTask()
+	upTask = task.Task{}
+	if err := td.db.First(&upTask, id).Error; err != nil {
+		return upTask, err
+	}
+	return upTask, nil
+}
 
-	return task, nil
+func samePointer(a *uint, b *uint) bool {
+	return a != nil && b != nil && *a == *b
 }
 
 func (bd *TaskDb) DeleteById(id int, user_id int) (gorm.DeletedAt, error) {
-	task := task.Task{}
+	deleted := task.Task{}
 
-	res := bd.db.Model(&task).Where("id = ? AND user_id = ?", id, user_id).Delete(&task)
+	found, err := bd.editable(id, user_id, 0)
+	if err != nil {
+		return deleted.DeletedAt, err
+	}
 
-	if res.RowsAffected == 0 {
-		return task.DeletedAt, errors.New(gorm.ErrRecordNotFound.Error())
+	err = bd.db.Transaction(func(tx *gorm.DB) error {
+		// subtasks of a deleted task move up to its parent
+		if err := tx.Model(&task.Task{}).Where("parent_id = ?", id).Update("parent_id", found.Parent_id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("blocker_id = ? OR blocked_id = ?", id, id).Delete(&task.Dependency{}).Error; err != nil {
+			return err
+		}
+		// the files are removed by the next purge, outside of this request
+		if err := tx.Model(&task.Attachment{}).Where("task_id = ? AND orphaned_at IS NULL", id).Update("orphaned_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&deleted).Where("id = ?", id).Delete(&deleted)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return gorm.DeletedAt{}, err
 	}
 
-	return task.DeletedAt, nil
+	return deleted.DeletedAt, nil
 }
 
-func (bd *TaskDb) GetAll(user_id int) ([]response.TaskResponse, error) {
+var taskColumns = paginate.Columns{
+	"id":         {Expr: "tasks.id", Field: "ID"},
+	"name":       {Expr: "tasks.name", Field: "Name"},
+	"status":     {Expr: "tasks.status", Field: "Status"},
+	"priority":   {Expr: "tasks.priority", Field: "Priority"},
+	"created_at": {Expr: "tasks.created_at", Field: "CreatedAt"},
+	"updated_at": {Expr: "tasks.updated_at", Field: "UpdatedAt"},
+	"due_at":     {Expr: "tasks.due_at", Field: "DueAt", Nullable: true},
+	"rank":       {Expr: "tasks.board_rank", Field: "Rank"},
+}
+
+func (bd *TaskDb) GetAll(user_id int, filter request.TaskFilter, page base.Page) ([]response.TaskResponse, base.Meta, error) {
 	taskRespArr := []response.TaskResponse{}
 
-	res := bd.db.Model(task.Task{}).Select("tasks.id as ID, tasks.created_at as CreatedAt, tasks.updated_at as UpdatedAt, tasks.name as Name, tasks.project_id as Project_id,tasks.priority as Priority ,projects.name as Project_name").Joins("inner join projects on projects.id = tasks.project_id").Find(&taskRespArr)
-	if res.RowsAffected == 0 {
-		return nil, errors.New(gorm.ErrRecordNotFound.Error())
+	meta, err := paginate.Find(bd.filtered(user_id, filter), taskRespSelect, page, taskColumns, &taskRespArr)
+	if err != nil {
+		return nil, meta, err
+	}
+	if err := loadDetails(bd.db, taskRespArr); err != nil {
+		return nil, meta, err
+	}
+	return taskRespArr, meta, nil
+}
+
+func (bd *TaskDb) filtered(user_id int, filter request.TaskFilter) *gorm.DB {
+	query := project.MemberJoin(bd.db.Model(task.Task{}).Joins("inner join projects on projects.id = tasks.project_id AND projects.deleted_at IS NULL"), "tasks.project_id", user_id)
+
+	if filter.Project_id != 0 {
+		query = query.Where("tasks.project_id = ?", filter.Project_id)
+	}
+	if filter.Parent_id != nil && *filter.Parent_id == 0 {
+		query = query.Where("tasks.parent_id IS NULL")
+	} else if filter.Parent_id != nil {
+		query = query.Where("tasks.parent_id = ?", *filter.Parent_id)
+	}
+	if len(filter.Labels) > 0 {
+		names := []string{}
+		for _, name := range filter.Labels {
+			names = append(names, strings.ToLower(name))
+		}
+		labelled := "SELECT %s FROM task_labels INNER JOIN labels ON labels.id = task_labels.label_id WHERE task_labels.task_id = tasks.id AND LOWER(labels.name) IN ?"
+		if filter.LabelMode == request.LabelModeAll {
+			query = query.Where("("+fmt.Sprintf(labelled, "COUNT(DISTINCT LOWER(labels.name))")+") = ?", names, len(uniqueStrings(names)))
+		} else {
+			query = query.Where("EXISTS ("+fmt.Sprintf(labelled, "1")+")", names)
+		}
+	}
+	if filter.Assignee != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM task_assignees WHERE task_assignees.task_id = tasks.id AND task_assignees.user_id = ?)", filter.Assignee)
+	}
+	if filter.Status != "" {
+		query = query.Where("tasks.status = ?", filter.Status)
+	}
+	if filter.PriorityMin != nil {
+		query = query.Where("tasks.priority >= ?", *filter.PriorityMin)
+	}
+	if filter.PriorityMax != nil {
+		query = query.Where("tasks.priority <= ?", *filter.PriorityMax)
+	}
+	if filter.Name != "" {
+		cond, pattern := paginate.Contains("tasks.name", filter.Name)
+		query = query.Where(cond, pattern)
 	}
-	return taskRespArr, nil
+	if filter.CreatedBefore != nil {
+		query = query.Where("tasks.created_at < ?", filter.CreatedBefore.UTC())
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("tasks.created_at >= ?", filter.CreatedAfter.UTC())
+	}
+	if filter.DueBefore != nil {
+		query = query.Where("tasks.due_at < ?", filter.DueBefore.UTC())
+	}
+	if filter.DueAfter != nil {
+		query = query.Where("tasks.due_at >= ?", filter.DueAfter.UTC())
+	}
+	if filter.Overdue {
+		query = query.Where("tasks.due_at < ? AND tasks.completed_at IS NULL", time.Now().UTC())
+	}
+	if filter.IncompleteOnly {
+		query = query.Where("tasks.completed_at IS NULL")
+	}
+	return query
+}
+
+// GetAgenda splits the open tasks into overdue, due today and due within the next days,
+// "today" is the calendar day of now in the caller's location
+func (bd *TaskDb) GetAgenda(user_id int, now time.Time, loc *time.Location, days int) (response.AgendaResponse, error) {
+	local := now.In(loc)
+	startOfDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+	endOfUpcoming := startOfDay.AddDate(0, 0, days+1)
+
+	agenda := response.AgendaResponse{
+		Overdue:  []response.TaskResponse{},
+		Today:    []response.TaskResponse{},
+		Upcoming: []response.TaskResponse{},
+	}
+
+	sections := []struct {
+		filter request.TaskFilter
+		dest   *[]response.TaskResponse
+	}{
+		{request.TaskFilter{DueBefore: &now, IncompleteOnly: true}, &agenda.Overdue},
+		{request.TaskFilter{DueAfter: &now, DueBefore: &endOfDay, IncompleteOnly: true}, &agenda.Today},
+		{request.TaskFilter{DueAfter: &endOfDay, DueBefore: &endOfUpcoming, IncompleteOnly: true}, &agenda.Upcoming},
+	}
+	for _, section := range sections {
+		if err := bd.filtered(user_id, section.filter).Select(taskRespSelect).Order("tasks.due_at").Order("tasks.id").Find(section.dest).Error; err != nil {
+			return agenda, err
+		}
+		if err := loadDetails(bd.db, *section.dest); err != nil {
+			return agenda, err
+		}
+	}
+
+	return agenda, nil
 }
 
 func (td *TaskDb) GetByIdResp(id int, user_id int) (response.TaskResponse, error) {
 	taskResp := response.TaskResponse{}
 
-	res := td.db.Model(task.Task{}).Where("tasks.id = ? AND tasks.user_id = ?", id, user_id).Select("tasks.id as ID, tasks.created_at as CreatedAt, tasks.updated_at as UpdatedAt, tasks.name as Name, tasks.project_id as Project_id,tasks.priority as Priority ,projects.name as Project_name").Joins("inner join projects on projects.id = tasks.project_id").First(&taskResp)
+	res := project.MemberJoin(td.db.Model(task.Task{}).Joins("inner join projects on projects.id = tasks.project_id AND projects.deleted_at IS NULL"), "tasks.project_id", user_id).Where("tasks.id = ?", id).Select(taskRespSelect).First(&taskResp)
 
 	if res.RowsAffected == 0 {
 		return response.TaskResponse{}, res.Error
 	}
 
-	return taskResp, nil
+	tasks := []response.TaskResponse{taskResp}
+	if err := loadDetails(td.db, tasks); err != nil {
+		return response.TaskResponse{}, err
+	}
+
+	return tasks[0], nil
 }
 
-func (td *TaskDb) TaskCompleted(id int, user_id int, taskRequest request.TaskRequest) (task.Task, error) {
+// TaskCompleted with cascade also completes every open subtask, at any depth,
+// failing as a whole when one of them cannot move to the done status. A recurring
+// task gets its next occurrence, subtasks completed along with it do not
+func (td *TaskDb) TaskCompleted(id int, user_id int, cascade bool) (task.Task, error) {
+	return td.changeStatus(id, user_id, cascade, func(wf workflow.Workflow) string {
+		return wf.DoneStatus()
+	})
+}
 
-	// completeTask := task.Task{}
+func (td *TaskDb) TaskReopened(id int, user_id int) (task.Task, error) {
+	return td.changeStatus(id, user_id, false, func(wf workflow.Workflow) string {
+		return wf.Initial()
+	})
+}
 
-	res := td.db.Model(task.Task{Model: gorm.Model{ID: uint(id)}, User_ID: uint(user_id)}).Updates(task.Task{Status: taskRequest.Status})
+func (td *TaskDb) Transition(id int, user_id int, status string) (task.Task, error) {
+	return td.changeStatus(id, user_id, false, func(wf workflow.Workflow) string {
+		return status
+	})
+}
 
-	if res.Statement.RowsAffected == 0 {
-		taskRequest.Status = false
-		return task.Task{}, errors.New(gorm.ErrRecordNotFound.Error())
+// changeStatus moves the task to the status picked from its project's workflow,
+// the row is locked so two concurrent moves cannot both pass the transition check
+func (td *TaskDb) changeStatus(id int, user_id int, cascade bool, target func(wf workflow.Workflow) string) (task.Task, error) {
+	upTask := task.Task{}
+
+	err := td.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&upTask).Error; err != nil {
+			return err
+		}
+		if _, err := project.Authorize(tx, upTask.Project_id, user_id, _project.EditRoles...); err != nil {
+			return err
+		}
+
+		wf, err := project.LoadWorkflow(tx, upTask.Project_id)
+		if err != nil {
+			return err
+		}
+
+		status := target(wf)
+		if !wf.Has(status) {
+			return ErrUnknownStatus
+		}
+
+		children := []task.Task{}
+		if cascade {
+			if children, err = descendants(tx.Clauses(clause.Locking{Strength: "UPDATE"}).Session(&gorm.Session{}), upTask.ID); err != nil {
+				return err
+			}
+		}
+		return td.setStatus(tx, &upTask, wf, status, user_id, children)
+	})
+	if err != nil {
+		return task.Task{}, err
 	}
 
-	task := taskRequest.ToTask()
-
-	return task, nil
-
+	return upTask, nil
 }
 
-func (td *TaskDb) TaskReopened(id int, user_id int, taskRequest request.TaskRequest) (task.Task, error) {
-
-	res := td.db.Model(task.Task{Model: gorm.Model{ID: uint(id)}, User_ID: uint(user_id)}).Updates(task.Task{Status: taskRequest.Status})
+// setStatus moves the locked task, and the subtasks given with it, to the status
+func (td *TaskDb) setStatus(tx *gorm.DB, upTask *task.Task, wf workflow.Workflow, status string, user_id int, children []task.Task) error {
+	if status == wf.DoneStatus() && td.blockCompletion {
+		completing := []uint{upTask.ID}
+		for _, child := range children {
+			completing = append(completing, child.ID)
+		}
+		if err := checkBlockers(tx, completing); err != nil {
+			return err
+		}
+	}
 
-	if res.Statement.RowsAffected == 0 {
-		taskRequest.Status = true
-		return task.Task{}, errors.New(gorm.ErrRecordNotFound.Error())
+	if err := applyStatus(tx, upTask, wf, status, user_id); err != nil {
+		return err
+	}
+	if status == wf.DoneStatus() {
+		if err := recur(tx, upTask, wf.Initial()); err != nil {
+			return err
+		}
+	}
+	for i := range children {
+		if children[i].Status == status {
+			continue
+		}
+		if err := applyStatus(tx, &children[i], wf, status, user_id); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	task := taskRequest.ToTask()
+func applyStatus(tx *gorm.DB, upTask *task.Task, wf workflow.Workflow, status string, user_id int) error {
+	if !wf.CanTransition(upTask.Status, status) {
+		return ErrIllegalTransition
+	}
 
-	return task, nil
+	now := time.Now()
+	upTask.Status = status
+	upTask.StatusChangedAt = now
+	upTask.CompletedAt = nil
+	upTask.CompletedBy = nil
+	if status == wf.DoneStatus() {
+		completedBy := uint(user_id)
+		upTask.CompletedAt = &now
+		upTask.CompletedBy = &completedBy
+	}
+	return tx.Model(upTask).Updates(map[string]interface{}{
+		"status":            upTask.Status,
+		"status_changed_at": upTask.StatusChangedAt,
+		"completed_at":      upTask.CompletedAt,
+		"completed_by":      upTask.CompletedBy,
+	}).Error
 }
@@ -1,15 +1,26 @@
 package task
 
 import (
+	"fmt"
+	"io/ioutil"
 	"part3/configs"
+	"part3/lib/database/paginate"
 	_libPro "part3/lib/database/project"
 	_lib "part3/lib/database/user"
+	"part3/lib/storage"
+	"part3/models/base"
+	"part3/models/board"
+	boardReq "part3/models/board/request"
 	"part3/models/project"
 	"part3/models/task"
 	"part3/models/task/request"
+	"part3/models/task/response"
 	"part3/models/user"
+	"part3/models/workflow"
 	"part3/utils"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -19,12 +30,9 @@ func TestCreate(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run Create", func(t *testing.T) {
 		mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
@@ -32,10 +40,14 @@ func TestCreate(t *testing.T) {
 		if _, err := _lib.New(db).Create(mocUserP); err != nil {
 			t.Fatal()
 		}
-		mockTask := task.Task{Name: "anonim123", Priority: 1}
+		mockPro := project.Project{Name: "Proanonim"}
+		if _, err := _libPro.New(db).Create(1, mockPro); err != nil {
+			t.Fatal()
+		}
+		mockTask := task.Task{Name: "anonim123", Priority: 1, Project_id: 1}
 		res, err := repo.Create(1, mockTask)
 		assert.Nil(t, err)
-		assert.Equal(t, 1, int(res.User_ID))
+		assert.Equal(t, 1, int(res.CreatedBy))
 		assert.Equal(t, "anonim123", res.Name)
 		assert.Equal(t, 1, int(res.Priority))
 	})
@@ -49,8 +61,8 @@ func TestCreate(t *testing.T) {
 		if _, err := repo.Create(1, mockTaskP); err != nil {
 			t.Fatal()
 		}
-		mockTask := task.Task{Model: gorm.Model{ID: 1}, User_ID: 1, Name: "anonim123", Priority: 1}
-		_, err := repo.Create(int(mockTask.User_ID), mockTask)
+		mockTask := task.Task{Model: gorm.Model{ID: 1}, CreatedBy: 1, Name: "anonim123", Priority: 1}
+		_, err := repo.Create(int(mockTask.CreatedBy), mockTask)
 		assert.NotNil(t, err)
 	})
 }
@@ -59,12 +71,9 @@ func TestGetById(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run GetById", func(t *testing.T) {
 		mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
@@ -72,6 +81,10 @@ func TestGetById(t *testing.T) {
 		if _, err := _lib.New(db).Create(mocUserP); err != nil {
 			t.Fatal()
 		}
+		mockPro := project.Project{Name: "Proanonim"}
+		if _, err := _libPro.New(db).Create(1, mockPro); err != nil {
+			t.Fatal()
+		}
 		mockTaskP := task.Task{Name: "Taskanonim123", Priority: 5, Project_id: 1}
 		if _, err := repo.Create(1, mockTaskP); err != nil {
 			t.Fatal()
@@ -80,7 +93,7 @@ func TestGetById(t *testing.T) {
 		res, err := repo.GetById(1, 1)
 		assert.Nil(t, err)
 		assert.Equal(t, 1, int(res.ID))
-		assert.Equal(t, 1, int(res.User_ID))
+		assert.Equal(t, 1, int(res.CreatedBy))
 	})
 
 	t.Run("fail run GetById", func(t *testing.T) {
@@ -102,26 +115,43 @@ func TestUpdateById(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run UpdateById", func(t *testing.T) {
 		mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
 		if _, err := _lib.New(db).Create(mocUserP); err != nil {
 			t.Fatal()
 		}
+		mockPro := project.Project{Name: "Proanonim"}
+		if _, err := _libPro.New(db).Create(1, mockPro); err != nil {
+			t.Fatal()
+		}
 		mockTaskP := task.Task{Name: "Taskanonim123", Priority: 5, Project_id: 1}
 		if _, err := repo.Create(1, mockTaskP); err != nil {
 			t.Fatal()
 		}
-		mockTask := request.TaskRequest{Name: "anonim321", Priority: 2}
+		mockTask := request.TaskRequest{Name: "anonim321", Priority: 2, Project_id: 1}
 		res, err := repo.UpdateById(1, 1, mockTask)
 		assert.Nil(t, err)
 		assert.Equal(t, "anonim321", res.Name)
+		assert.Equal(t, uint(1), res.ID)
+		assert.Equal(t, uint(1), res.Project_id)
+	})
+
+	t.Run("success run UpdateById replaces zero values", func(t *testing.T) {
+		due := time.Date(2022, 3, 1, 9, 0, 0, 0, time.UTC)
+		weekly := "FREQ=WEEKLY"
+		if _, err := repo.UpdateById(1, 1, request.TaskRequest{Name: "anonim321", Project_id: 1, Priority: 2, DueAt: &due, Recurrence: &weekly}); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := repo.UpdateById(1, 1, request.TaskRequest{Name: "anonim321", Project_id: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, res.Priority)
+		assert.Nil(t, res.DueAt)
+		assert.Equal(t, "", res.Recurrence)
 	})
 
 	t.Run("fail run UpdateById", func(t *testing.T) {
@@ -133,9 +163,12 @@ func TestUpdateById(t *testing.T) {
 		if _, err := repo.Create(1, mockTaskP); err != nil {
 			t.Fatal()
 		}
-		mockTask := request.TaskRequest{Name: "anonim321", Priority: 2}
+		mockTask := request.TaskRequest{Name: "anonim321", Priority: 2, Project_id: 1}
 		_, err := repo.UpdateById(10, 1, mockTask)
 		assert.NotNil(t, err)
+
+		_, err = repo.UpdateById(1, 1, request.TaskRequest{Name: "anonim321"})
+		assert.Equal(t, ErrProjectRequired, err)
 	})
 }
 
@@ -143,18 +176,19 @@ func TestDeleteById(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run DeleteById", func(t *testing.T) {
 		mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
 		if _, err := _lib.New(db).Create(mocUserP); err != nil {
 			t.Fatal()
 		}
+		mockPro := project.Project{Name: "Proanonim"}
+		if _, err := _libPro.New(db).Create(1, mockPro); err != nil {
+			t.Fatal()
+		}
 		mockTaskP := task.Task{Name: "Taskanonim123", Priority: 5, Project_id: 1}
 		if _, err := repo.Create(1, mockTaskP); err != nil {
 			t.Fatal()
@@ -183,12 +217,9 @@ func TestGetAll(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run GetAll", func(t *testing.T) {
 		mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
@@ -203,17 +234,101 @@ func TestGetAll(t *testing.T) {
 		if _, err := repo.Create(1, mockTaskP); err != nil {
 			t.Fatal()
 		}
-		_, err := repo.GetAll(1)
+		res, meta, err := repo.GetAll(1, request.TaskFilter{}, base.Page{Limit: 20})
 		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.Equal(t, 1, int(meta.Total))
 	})
 
-	t.Run("fail run GetAll", func(t *testing.T) {
+	t.Run("success run GetAll empty", func(t *testing.T) {
 		_, errT := repo.DeleteById(1, 1)
 		if errT != nil {
 			t.Fail()
 		}
-		_, err := repo.GetAll(1)
-		assert.NotNil(t, err)
+		res, meta, err := repo.GetAll(1, request.TaskFilter{}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+		assert.Equal(t, 0, int(meta.Total))
+	})
+}
+
+func TestGetAllPage(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
+	if _, err := _lib.New(db).Create(mocUserP); err != nil {
+		t.Fatal()
+	}
+	mockPro := project.Project{Name: "Proanonim"}
+	if _, err := _libPro.New(db).Create(1, mockPro); err != nil {
+		t.Fatal()
+	}
+	for _, priority := range []int{3, 1, 3, 2, 5} {
+		mockTaskP := task.Task{Name: "Taskanonim123", Priority: priority, Project_id: 1}
+		if _, err := repo.Create(1, mockTaskP); err != nil {
+			t.Fatal()
+		}
+	}
+	page := base.Page{Limit: 2, Sort: []base.Sort{{Field: "priority", Desc: true}}}
+	ids := func(res []response.TaskResponse) []int {
+		ids := []int{}
+		for _, taskResp := range res {
+			ids = append(ids, int(taskResp.ID))
+		}
+		return ids
+	}
+
+	t.Run("success run GetAll offset", func(t *testing.T) {
+		offsetPage := page
+		offsetPage.Offset = 2
+		res, meta, err := repo.GetAll(1, request.TaskFilter{}, offsetPage)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{3, 4}, ids(res))
+		assert.Equal(t, 5, int(meta.Total))
+	})
+
+	t.Run("success run GetAll cursor", func(t *testing.T) {
+		res, meta, err := repo.GetAll(1, request.TaskFilter{}, page)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{5, 1}, ids(res))
+		assert.Equal(t, "", meta.PrevCursor)
+
+		page.Cursor = meta.NextCursor
+		res, meta, err = repo.GetAll(1, request.TaskFilter{}, page)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{3, 4}, ids(res))
+
+		page.Cursor = meta.NextCursor
+		res, meta, err = repo.GetAll(1, request.TaskFilter{}, page)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2}, ids(res))
+		assert.Equal(t, "", meta.NextCursor)
+
+		page.Cursor = meta.PrevCursor
+		res, _, err = repo.GetAll(1, request.TaskFilter{}, page)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{3, 4}, ids(res))
+	})
+
+	t.Run("success run GetAll filters", func(t *testing.T) {
+		min, max := 2, 3
+		res, meta, err := repo.GetAll(1, request.TaskFilter{PriorityMin: &min, PriorityMax: &max, Name: "ANONIM"}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 3, 4}, ids(res))
+		assert.Equal(t, 3, int(meta.Total))
+	})
+
+	t.Run("fail run GetAll", func(t *testing.T) {
+		_, _, err := repo.GetAll(1, request.TaskFilter{}, base.Page{Limit: 2, Sort: []base.Sort{{Field: "user_id"}}})
+		assert.Equal(t, paginate.ErrInvalidSort, err)
+
+		_, _, err = repo.GetAll(1, request.TaskFilter{}, base.Page{Limit: 2, Cursor: "bm90IGEgY3Vyc29y"})
+		assert.Equal(t, paginate.ErrInvalidCursor, err)
 	})
 }
 
@@ -221,12 +336,9 @@ func TestGetByIdResp(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("Success GetByIdResp", func(t *testing.T) {
 		mocUserP := user.User{Name: "anonim123", Email: "anonim@123", Password: "anonim123"}
@@ -260,86 +372,809 @@ func TestTaskCompleted(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("fail run TaskCompleted", func(t *testing.T) {
 		mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
 		if _, err := _lib.New(db).Create(mocUserP); err != nil {
 			t.Fatal()
 		}
+		mockPro := project.Project{Name: "Proanonim"}
+		if _, err := _libPro.New(db).Create(1, mockPro); err != nil {
+			t.Fatal()
+		}
 		mockTaskP := task.Task{Name: "Taskanonim123", Priority: 5, Project_id: 1}
 		if _, err := repo.Create(1, mockTaskP); err != nil {
 			t.Fatal()
 		}
-		mockTask := request.TaskRequest{Status: true}
-		_, err := repo.TaskCompleted(5, 1, mockTask)
+		_, err := repo.TaskCompleted(5, 1, false)
 		assert.NotNil(t, err)
 
 	})
 
 	t.Run("success run TaskCompleted", func(t *testing.T) {
-		mocUserP := user.User{Name: "anonim12", Email: "anonim@12", Password: "anonim12"}
+		res, err := repo.TaskCompleted(1, 1, false)
+
+		assert.Nil(t, err)
+		assert.Equal(t, workflow.StatusDone, res.Status)
+		assert.False(t, res.StatusChangedAt.IsZero())
+		assert.NotNil(t, res.CompletedAt)
+		assert.Equal(t, 1, int(*res.CompletedBy))
+	})
+
+	t.Run("fail run TaskCompleted twice", func(t *testing.T) {
+		_, err := repo.TaskCompleted(1, 1, false)
+		assert.Equal(t, ErrIllegalTransition, err)
+	})
+}
+
+func TestTaskReopened(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("fail run TaskReopened", func(t *testing.T) {
+		mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
 		if _, err := _lib.New(db).Create(mocUserP); err != nil {
 			t.Fatal()
 		}
-		mockTaskP := task.Task{Name: "Taskanonim1234", Priority: 5, Project_id: 1}
+		mockPro := project.Project{Name: "Proanonim"}
+		if _, err := _libPro.New(db).Create(1, mockPro); err != nil {
+			t.Fatal()
+		}
+		mockTaskP := task.Task{Name: "Taskanonim123", Priority: 5, Project_id: 1}
 		if _, err := repo.Create(1, mockTaskP); err != nil {
 			t.Fatal()
 		}
-		mockTask := request.TaskRequest{Status: true}
+		_, err := repo.TaskReopened(5, 1)
+		assert.NotNil(t, err)
+
+	})
+
+	t.Run("success run TaskReopened", func(t *testing.T) {
+		if _, err := repo.TaskCompleted(1, 1, false); err != nil {
+			t.Fatal()
+		}
 
-		res, err := repo.TaskCompleted(1, 1, mockTask)
+		res, err := repo.TaskReopened(1, 1)
 
 		assert.Nil(t, err)
-		assert.Equal(t, true, res.Status)
+		assert.Equal(t, workflow.StatusTodo, res.Status)
+		assert.Nil(t, res.CompletedAt)
+		assert.Nil(t, res.CompletedBy)
 	})
 }
 
-func TestTaskReopened(t *testing.T) {
+func TestTransition(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
-	t.Run("fail run TaskReopened", func(t *testing.T) {
-		mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
-		if _, err := _lib.New(db).Create(mocUserP); err != nil {
+	mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
+	if _, err := _lib.New(db).Create(mocUserP); err != nil {
+		t.Fatal()
+	}
+	mockPro := project.Project{Name: "Proanonim"}
+	if _, err := _libPro.New(db).Create(1, mockPro); err != nil {
+		t.Fatal()
+	}
+
+	t.Run("success run Transition", func(t *testing.T) {
+		mockTaskP := task.Task{Name: "Taskanonim123", Priority: 5, Project_id: 1}
+		created, err := repo.Create(1, mockTaskP)
+		if err != nil {
 			t.Fatal()
 		}
-		mockTaskP := task.Task{Name: "Taskanonim123", Priority: 5, Project_id: 1}
+		assert.Equal(t, workflow.StatusTodo, created.Status)
+
+		res, err := repo.Transition(1, 1, workflow.StatusInProgress)
+		assert.Nil(t, err)
+		assert.Equal(t, workflow.StatusInProgress, res.Status)
+		assert.True(t, !res.StatusChangedAt.Before(created.StatusChangedAt))
+	})
+
+	t.Run("fail run Transition illegal", func(t *testing.T) {
+		_, err := repo.Transition(1, 1, workflow.StatusInProgress)
+		assert.Equal(t, ErrIllegalTransition, err)
+	})
+
+	t.Run("fail run Transition unknown status", func(t *testing.T) {
+		_, err := repo.Transition(1, 1, "archived")
+		assert.Equal(t, ErrUnknownStatus, err)
+	})
+
+	t.Run("success run Transition custom workflow", func(t *testing.T) {
+		wf := workflow.Workflow{
+			Statuses: []workflow.Status{
+				{Name: "open", Position: 0},
+				{Name: workflow.StatusInProgress, Position: 1},
+				{Name: "closed", Position: 2, IsDone: true},
+			},
+			Transitions: []workflow.Transition{{From: workflow.StatusInProgress, To: "closed"}},
+		}
+		if _, err := _libPro.New(db).UpdateWorkflow(1, 1, wf); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := repo.TaskCompleted(1, 1, false)
+		assert.Nil(t, err)
+		assert.Equal(t, "closed", res.Status)
+
+		created, err := repo.Create(1, task.Task{Name: "Taskanonim456", Priority: 1, Project_id: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, "open", created.Status)
+	})
+}
+
+func TestDueDates(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
+	if _, err := _lib.New(db).Create(mocUserP); err != nil {
+		t.Fatal()
+	}
+	mockPro := project.Project{Name: "Proanonim"}
+	if _, err := _libPro.New(db).Create(1, mockPro); err != nil {
+		t.Fatal()
+	}
+
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	// the overdue filter compares with the clock, so the tasks are due around it
+	now := time.Now().In(loc)
+	endOfDay := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc)
+	dueAt := []time.Time{
+		now.AddDate(0, 0, -1),
+		now.Add(endOfDay.Sub(now) / 2),
+		now.AddDate(0, 0, 2),
+		now.AddDate(0, 0, 30),
+	}
+	for i := range dueAt {
+		// stored in UTC like TaskRequest.ToTask does
+		dueAt[i] = dueAt[i].UTC()
+		mockTaskP := task.Task{Name: "Taskanonim123", Priority: 5, Project_id: 1, DueAt: &dueAt[i]}
 		if _, err := repo.Create(1, mockTaskP); err != nil {
 			t.Fatal()
 		}
-		mockTask := request.TaskRequest{Status: false}
-		_, err := repo.TaskReopened(5, 1, mockTask)
+	}
+
+	t.Run("success run GetAll due filter", func(t *testing.T) {
+		before := now.AddDate(0, 0, 3)
+		res, _, err := repo.GetAll(1, request.TaskFilter{DueAfter: &now, DueBefore: &before}, base.Page{Limit: 20, Sort: []base.Sort{{Field: "due_at"}}})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.Equal(t, 2, int(res[0].ID))
+	})
+
+	t.Run("success run GetAll overdue", func(t *testing.T) {
+		res, _, err := repo.GetAll(1, request.TaskFilter{Overdue: true}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.Equal(t, 1, int(res[0].ID))
+	})
+
+	t.Run("success run GetAgenda", func(t *testing.T) {
+		res, err := repo.GetAgenda(1, now, loc, 7)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res.Overdue))
+		assert.Equal(t, 1, len(res.Today))
+		assert.Equal(t, 1, len(res.Upcoming))
+	})
+
+	t.Run("completed task leaves agenda", func(t *testing.T) {
+		if _, err := repo.TaskCompleted(1, 1, false); err != nil {
+			t.Fatal()
+		}
+		res, err := repo.GetAgenda(1, now, loc, 7)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res.Overdue))
+	})
+}
+
+func TestMembership(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mocUserP := range []user.User{
+		{Name: "anonim1", Email: "anonim@1", Password: "anonim1"},
+		{Name: "anonim2", Email: "anonim@2", Password: "anonim2"},
+		{Name: "anonim3", Email: "anonim@3", Password: "anonim3"},
+	} {
+		if _, err := _lib.New(db).Create(mocUserP); err != nil {
+			t.Fatal()
+		}
+	}
+	mockPro := project.Project{Name: "Proanonim"}
+	if _, err := _libPro.New(db).Create(1, mockPro); err != nil {
+		t.Fatal()
+	}
+	if _, err := _libPro.New(db).AddMember(1, 1, project.Member{User_ID: 2, Role: project.MemberViewer}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Create(1, task.Task{Name: "Taskanonim123", Priority: 5, Project_id: 1}); err != nil {
+		t.Fatal()
+	}
+
+	t.Run("viewer reads shared tasks", func(t *testing.T) {
+		res, err := repo.GetByIdResp(1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, int(res.ID))
+
+		list, meta, err := repo.GetAll(2, request.TaskFilter{}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(list))
+		assert.Equal(t, 1, int(meta.Total))
+	})
+
+	t.Run("viewer cannot change tasks", func(t *testing.T) {
+		_, err := repo.Create(2, task.Task{Name: "Taskanonim456", Priority: 1, Project_id: 1})
+		assert.Equal(t, _libPro.ErrForbidden, err)
+
+		_, err = repo.UpdateById(1, 2, request.TaskRequest{Name: "anonim321", Project_id: 1, Priority: 2})
+		assert.Equal(t, _libPro.ErrForbidden, err)
+
+		_, err = repo.TaskCompleted(1, 2, false)
+		assert.Equal(t, _libPro.ErrForbidden, err)
+	})
+
+	t.Run("editor changes tasks", func(t *testing.T) {
+		if _, err := _libPro.New(db).UpdateMember(1, 1, 2, project.MemberEditor); err != nil {
+			t.Fatal(err)
+		}
+		res, err := repo.TaskCompleted(1, 2, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, int(*res.CompletedBy))
+	})
+
+	t.Run("non member sees nothing", func(t *testing.T) {
+		_, err := repo.GetByIdResp(1, 3)
 		assert.NotNil(t, err)
 
+		list, _, err := repo.GetAll(3, request.TaskFilter{}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(list))
+
+		_, err = repo.DeleteById(1, 3)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
 	})
+}
 
-	t.Run("success run TaskReopened", func(t *testing.T) {
-		mocUserP := user.User{Name: "anonim12", Email: "anonim@12", Password: "anonim12"}
+func TestAssignees(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mocUserP := range []user.User{
+		{Name: "anonim1", Email: "anonim@1", Password: "anonim1"},
+		{Name: "anonim2", Email: "anonim@2", Password: "anonim2"},
+	} {
 		if _, err := _lib.New(db).Create(mocUserP); err != nil {
 			t.Fatal()
 		}
-		mockTaskP := task.Task{Name: "Taskanonim1234", Priority: 5, Project_id: 1}
+	}
+	if _, err := _libPro.New(db).Create(1, project.Project{Name: "Proanonim"}); err != nil {
+		t.Fatal()
+	}
+	if _, err := _libPro.New(db).AddMember(1, 1, project.Member{User_ID: 2, Role: project.MemberViewer}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Taskanonim1", "Taskanonim2"} {
+		if _, err := repo.Create(1, task.Task{Name: name, Priority: 1, Project_id: 1}); err != nil {
+			t.Fatal()
+		}
+	}
+
+	t.Run("success run AddAssignees", func(t *testing.T) {
+		res, err := repo.AddAssignees(1, 1, []uint{1, 2})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+
+		res, err = repo.AddAssignees(1, 1, []uint{2})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+	})
+
+	t.Run("fail run AddAssignees viewer", func(t *testing.T) {
+		_, err := repo.AddAssignees(2, 2, []uint{2})
+		assert.Equal(t, _libPro.ErrForbidden, err)
+	})
+
+	t.Run("success run GetAll assigned to me", func(t *testing.T) {
+		list, meta, err := repo.GetAll(2, request.TaskFilter{Assignee: 2}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, int(meta.Total))
+		assert.Equal(t, "Taskanonim1", list[0].Name)
+		assert.Equal(t, 2, len(list[0].Assignees))
+		assert.Equal(t, 1, int(list[0].CreatedBy))
+	})
+
+	t.Run("success run RemoveAssignee self", func(t *testing.T) {
+		assert.Nil(t, repo.RemoveAssignee(1, 2, 2))
+		assert.Equal(t, ErrAssigneeNotFound, repo.RemoveAssignee(1, 2, 2))
+		assert.Equal(t, _libPro.ErrForbidden, repo.RemoveAssignee(1, 2, 1))
+
+		res, err := repo.GetAssignees(1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+	})
+}
+
+func TestSubtasks(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := _lib.New(db).Create(user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}); err != nil {
+		t.Fatal()
+	}
+	for _, name := range []string{"Proanonim1", "Proanonim2"} {
+		if _, err := _libPro.New(db).Create(1, project.Project{Name: name}); err != nil {
+			t.Fatal()
+		}
+	}
+	parent := func(id uint) *uint { return &id }
+
+	t.Run("success run Create subtasks", func(t *testing.T) {
+		if _, err := repo.Create(1, task.Task{Name: "Taskanonim1", Priority: 1, Project_id: 1}); err != nil {
+			t.Fatal()
+		}
+		res, err := repo.Create(1, task.Task{Name: "Taskanonim2", Priority: 1, Project_id: 1, Parent_id: parent(1)})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, int(*res.Parent_id))
+		_, err = repo.Create(1, task.Task{Name: "Taskanonim3", Priority: 1, Project_id: 1, Parent_id: parent(2)})
+		assert.Nil(t, err)
+	})
+
+	t.Run("fail run Create parent in another project", func(t *testing.T) {
+		_, err := repo.Create(1, task.Task{Name: "Taskanonim4", Priority: 1, Project_id: 2, Parent_id: parent(1)})
+		assert.Equal(t, ErrInvalidParent, err)
+	})
+
+	t.Run("fail run UpdateById cycle", func(t *testing.T) {
+		_, err := repo.UpdateById(1, 1, request.TaskRequest{Name: "Taskanonim1", Project_id: 1, Parent_id: parent(3)})
+		assert.Equal(t, ErrTaskCycle, err)
+		_, err = repo.UpdateById(1, 1, request.TaskRequest{Name: "Taskanonim1", Project_id: 1, Parent_id: parent(1)})
+		assert.Equal(t, ErrTaskCycle, err)
+	})
+
+	t.Run("success run checklist", func(t *testing.T) {
+		for _, name := range []string{"step1", "step2"} {
+			if _, err := repo.AddChecklistItem(1, 1, task.ChecklistItem{Name: name}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		done := true
+		res, err := repo.UpdateChecklistItem(1, 1, 1, request.ChecklistRequest{Done: &done})
+		assert.Nil(t, err)
+		assert.True(t, res.Done)
+
+		list, err := repo.GetChecklist(1, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(list))
+		assert.Equal(t, 1, list[1].Position)
+	})
+
+	t.Run("success run progress rollup", func(t *testing.T) {
+		res, err := repo.GetByIdResp(1, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, response.ProgressResponse{Done: 0, Total: 1}, res.Subtasks)
+		assert.Equal(t, response.ProgressResponse{Done: 1, Total: 2}, res.Checklist)
+		assert.Equal(t, response.ProgressResponse{Done: 1, Total: 3}, res.Progress)
+	})
+
+	t.Run("success run TaskCompleted cascade", func(t *testing.T) {
+		_, err := repo.TaskCompleted(1, 1, true)
+		assert.Nil(t, err)
+
+		none := uint(0)
+		list, _, err := repo.GetAll(1, request.TaskFilter{Parent_id: &none}, base.Page{Limit: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(list))
+		assert.Equal(t, response.ProgressResponse{Done: 1, Total: 1}, list[0].Subtasks)
+
+		grandchild, err := repo.GetByIdResp(3, 1)
+		assert.Nil(t, err)
+		assert.NotNil(t, grandchild.CompletedAt)
+	})
+
+	t.Run("success run UpdateById moves subtree", func(t *testing.T) {
+		// the old parent as read before the move is left too
+		_, err := repo.UpdateById(2, 1, request.TaskRequest{Name: "Taskanonim2", Project_id: 2, Parent_id: parent(1)})
+		assert.Nil(t, err)
+
+		moved, err := repo.GetByIdResp(2, 1)
+		assert.Nil(t, err)
+		assert.Nil(t, moved.Parent_id)
+		grandchild, err := repo.GetByIdResp(3, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, grandchild.Project_id)
+	})
+
+	t.Run("success run DeleteById lifts subtasks", func(t *testing.T) {
+		_, err := repo.DeleteById(2, 1)
+		assert.Nil(t, err)
+
+		grandchild, err := repo.GetByIdResp(3, 1)
+		assert.Nil(t, err)
+		assert.Nil(t, grandchild.Parent_id)
+	})
+}
+
+func TestAttachments(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	store := storage.NewLocal(t.TempDir())
+	repo := NewAttachments(db, store)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := _lib.New(db).Create(user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}); err != nil {
+		t.Fatal()
+	}
+	if _, err := _libPro.New(db).Create(1, project.Project{Name: "Proanonim"}); err != nil {
+		t.Fatal()
+	}
+	if _, err := New(db).Create(1, task.Task{Name: "Taskanonim", Priority: 1, Project_id: 1}); err != nil {
+		t.Fatal()
+	}
+
+	t.Run("success run Create", func(t *testing.T) {
+		res, err := repo.Create(1, 1, task.Attachment{Name: "notes.txt", ContentType: "text/plain; charset=utf-8", Size: 5}, strings.NewReader("hello"))
+		assert.Nil(t, err)
+		assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", res.Checksum)
+		assert.Equal(t, 1, int(res.UploadedBy))
+	})
+
+	t.Run("fail run Create size mismatch", func(t *testing.T) {
+		_, err := repo.Create(1, 1, task.Attachment{Name: "notes.txt", ContentType: "text/plain", Size: 3}, strings.NewReader("hello"))
+		assert.Equal(t, ErrSizeMismatch, err)
+
+		list, err := repo.GetAll(1, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(list))
+	})
+
+	t.Run("success run Open", func(t *testing.T) {
+		att, content, err := repo.Open(1, 1, 1)
+		assert.Nil(t, err)
+		raw, _ := ioutil.ReadAll(content)
+		content.Close()
+		assert.Equal(t, "hello", string(raw))
+		assert.Equal(t, "notes.txt", att.Name)
+	})
+
+	t.Run("success run PurgeOrphans after task delete", func(t *testing.T) {
+		found := task.Attachment{}
+		db.First(&found, 1)
+
+		if _, err := New(db).DeleteById(1, 1); err != nil {
+			t.Fatal(err)
+		}
+		purged, err := repo.PurgeOrphans()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, purged)
+
+		_, err = store.Get(found.StorageKey)
+		assert.Equal(t, storage.ErrNotFound, err)
+	})
+}
+
+func TestRecurrence(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
+	if _, err := _lib.New(db).Create(mocUserP); err != nil {
+		t.Fatal()
+	}
+	if _, err := _libPro.New(db).Create(1, project.Project{Name: "Proanonim"}); err != nil {
+		t.Fatal()
+	}
+	due := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	mockTaskP := task.Task{Name: "Weekly report", Priority: 1, Project_id: 1, DueAt: &due, Recurrence: "FREQ=WEEKLY", Timezone: "Asia/Jakarta"}
+	if _, err := repo.Create(1, mockTaskP); err != nil {
+		t.Fatal()
+	}
+	if _, err := repo.AddAssignees(1, 1, []uint{1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.AddChecklistItem(1, 1, task.ChecklistItem{Name: "send", Done: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("success run TaskCompleted recurring", func(t *testing.T) {
+		res, err := repo.TaskCompleted(1, 1, false)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(2), *res.Next_id)
+
+		next, err := repo.GetByIdResp(2, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "Weekly report", next.Name)
+		assert.Equal(t, workflow.StatusTodo, next.Status)
+		assert.Equal(t, due.AddDate(0, 0, 7), next.DueAt.UTC())
+		assert.Equal(t, "FREQ=WEEKLY", next.Recurrence)
+		assert.Equal(t, 1, len(next.Assignees))
+		assert.Equal(t, response.ProgressResponse{Done: 0, Total: 1}, next.Checklist)
+	})
+
+	t.Run("success run TaskCompleted again after reopen", func(t *testing.T) {
+		if _, err := repo.TaskReopened(1, 1); err != nil {
+			t.Fatal(err)
+		}
+		res, err := repo.TaskCompleted(1, 1, false)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(2), *res.Next_id)
+
+		var count int64
+		db.Model(&task.Task{}).Count(&count)
+		assert.Equal(t, 2, int(count))
+	})
+
+	t.Run("success run UpdateById stops recurring", func(t *testing.T) {
+		none := ""
+		_, err := repo.UpdateById(2, 1, request.TaskRequest{Name: "Weekly report", Project_id: 1, Recurrence: &none})
+		assert.Nil(t, err)
+
+		res, err := repo.TaskCompleted(2, 1, false)
+		assert.Nil(t, err)
+		assert.Nil(t, res.Next_id)
+	})
+
+	t.Run("success run TaskCompleted monthly keeps the day of month", func(t *testing.T) {
+		year := time.Now().Year() + 1
+		due := time.Date(year, 1, 31, 9, 0, 0, 0, time.UTC)
+		res, err := repo.Create(1, task.Task{Name: "Rent", Priority: 1, Project_id: 1, DueAt: &due, Recurrence: "FREQ=MONTHLY"})
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprintf("FREQ=MONTHLY;DTSTART=%d0131", year), res.Recurrence)
+
+		first, err := repo.TaskCompleted(int(res.ID), 1, false)
+		assert.Nil(t, err)
+		second, err := repo.TaskCompleted(int(*first.Next_id), 1, false)
+		assert.Nil(t, err)
+		third, err := repo.GetById(int(*second.Next_id), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(year, 3, 31, 9, 0, 0, 0, time.UTC), third.DueAt.UTC())
+	})
+
+	t.Run("success run UpdateById takes the day of month again", func(t *testing.T) {
+		year := time.Now().Year() + 1
+		due := time.Date(year, 5, 30, 9, 0, 0, 0, time.UTC)
+		monthly := fmt.Sprintf("FREQ=MONTHLY;DTSTART=%d0131", year)
+		res, err := repo.UpdateById(3, 1, request.TaskRequest{Name: "Rent", Priority: 1, Project_id: 1, DueAt: &due, Recurrence: &monthly})
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprintf("FREQ=MONTHLY;DTSTART=%d0530", year), res.Recurrence)
+	})
+}
+
+func TestDependencies(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	deps := NewDependencies(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
+	if _, err := _lib.New(db).Create(mocUserP); err != nil {
+		t.Fatal()
+	}
+	for _, name := range []string{"Proanonim1", "Proanonim2"} {
+		if _, err := _libPro.New(db).Create(1, project.Project{Name: name}); err != nil {
+			t.Fatal()
+		}
+	}
+	start := time.Now().UTC().Truncate(time.Second)
+	due := start.Add(60 * time.Hour)
+	for _, mockTaskP := range []task.Task{
+		{Name: "Design", Priority: 1, Project_id: 1, StartAt: &start, DueAt: &due},
+		{Name: "Build", Priority: 1, Project_id: 1},
+		{Name: "Docs", Priority: 1, Project_id: 1},
+		{Name: "Release", Priority: 1, Project_id: 1},
+		{Name: "Elsewhere", Priority: 1, Project_id: 2},
+	} {
 		if _, err := repo.Create(1, mockTaskP); err != nil {
 			t.Fatal()
 		}
-		mockTask := request.TaskRequest{Status: false}
+	}
 
-		res, err := repo.TaskReopened(1, 1, mockTask)
+	t.Run("success run Create", func(t *testing.T) {
+		for _, edge := range [][2]int{{2, 1}, {4, 2}, {4, 3}} {
+			_, err := deps.Create(edge[0], 1, uint(edge[1]))
+			assert.Nil(t, err)
+		}
 
+		res, err := deps.GetAll(4, 1)
 		assert.Nil(t, err)
-		assert.Equal(t, false, res.Status)
+		assert.Equal(t, 2, len(res.Blocked_by))
+		assert.Equal(t, 0, len(res.Blocks))
 	})
+
+	t.Run("fail run Create", func(t *testing.T) {
+		_, err := deps.Create(1, 1, 4)
+		assert.Equal(t, ErrDependencyCycle, err)
+
+		_, err = deps.Create(1, 1, 1)
+		assert.Equal(t, ErrInvalidDependency, err)
+
+		_, err = deps.Create(1, 1, 5)
+		assert.Equal(t, ErrInvalidDependency, err)
+	})
+
+	t.Run("success run GetGraph", func(t *testing.T) {
+		res, err := deps.GetGraph(1, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, len(res.Nodes))
+		assert.Equal(t, 3, len(res.Edges))
+		assert.Equal(t, []uint{1, 2, 4}, res.Critical_path)
+		assert.Equal(t, 5, res.Critical_length)
+	})
+
+	t.Run("fail run TaskCompleted blocked", func(t *testing.T) {
+		_, err := repo.TaskCompleted(2, 1, false)
+		assert.Equal(t, ErrBlocked, err)
+
+		repo.SetBlockCompletion(false)
+		_, err = repo.TaskCompleted(2, 1, false)
+		assert.Nil(t, err)
+		repo.SetBlockCompletion(true)
+	})
+
+	t.Run("success run DeleteById", func(t *testing.T) {
+		assert.Nil(t, deps.DeleteById(4, 3, 1))
+		assert.Equal(t, ErrDependencyNotFound, deps.DeleteById(4, 3, 1))
+	})
+}
+
+func TestBoard(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	boards := NewBoard(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	mocUserP := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
+	if _, err := _lib.New(db).Create(mocUserP); err != nil {
+		t.Fatal()
+	}
+	for _, name := range []string{"Proanonim1", "Proanonim2"} {
+		if _, err := _libPro.New(db).Create(1, project.Project{Name: name}); err != nil {
+			t.Fatal()
+		}
+	}
+	for _, mockTaskP := range []task.Task{
+		{Name: "A", Priority: 1, Project_id: 1},
+		{Name: "B", Priority: 1, Project_id: 1},
+		{Name: "C", Priority: 1, Project_id: 1},
+		{Name: "Elsewhere", Priority: 1, Project_id: 2},
+	} {
+		if _, err := repo.Create(1, mockTaskP); err != nil {
+			t.Fatal()
+		}
+	}
+
+	order := func(t *testing.T, by string) map[string][]string {
+		res, err := boards.GetBoard(1, 1, by)
+		assert.Nil(t, err)
+		columns := map[string][]string{}
+		for _, column := range res.Columns {
+			columns[column.Key] = []string{}
+			for _, task := range column.Tasks {
+				columns[column.Key] = append(columns[column.Key], task.Name)
+			}
+		}
+		return columns
+	}
+
+	t.Run("success run Create appends to the board", func(t *testing.T) {
+		assert.Equal(t, []string{"A", "B", "C"}, order(t, board.ByStatus)[workflow.StatusTodo])
+	})
+
+	t.Run("success run Move", func(t *testing.T) {
+		_, err := repo.Move(3, 1, request.MoveRequest{Before_id: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"C", "A", "B"}, order(t, board.ByStatus)[workflow.StatusTodo])
+
+		_, err = repo.Move(3, 1, request.MoveRequest{After_id: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"A", "C", "B"}, order(t, board.ByStatus)[workflow.StatusTodo])
+
+		// only the moved task gets a new rank
+		before, _ := repo.GetByIdResp(2, 1)
+		res, err := repo.Move(1, 1, request.MoveRequest{Status: workflow.StatusInProgress})
+		assert.Nil(t, err)
+		assert.Equal(t, workflow.StatusInProgress, res.Status)
+		after, _ := repo.GetByIdResp(2, 1)
+		assert.Equal(t, before.Rank, after.Rank)
+
+		columns := order(t, board.ByStatus)
+		assert.Equal(t, []string{"C", "B"}, columns[workflow.StatusTodo])
+		assert.Equal(t, []string{"A"}, columns[workflow.StatusInProgress])
+	})
+
+	t.Run("fail run Move", func(t *testing.T) {
+		_, err := repo.Move(1, 1, request.MoveRequest{After_id: 4})
+		assert.Equal(t, ErrInvalidPosition, err)
+
+		_, err = repo.Move(1, 1, request.MoveRequest{Before_id: 1})
+		assert.Equal(t, ErrInvalidPosition, err)
+
+		section_id := uint(99)
+		_, err = repo.Move(1, 1, request.MoveRequest{Section_id: &section_id})
+		assert.Equal(t, ErrInvalidSection, err)
+
+		_, err = repo.Move(1, 1, request.MoveRequest{Status: "shipped"})
+		assert.Equal(t, ErrUnknownStatus, err)
+
+		_, err = repo.Move(99, 1, request.MoveRequest{})
+		assert.Equal(t, ErrTaskNotFound, err)
+	})
+
+	t.Run("success run sections", func(t *testing.T) {
+		for _, name := range []string{"Backlog", "Doing"} {
+			_, err := boards.CreateSection(1, 1, boardReq.SectionRequest{Name: name})
+			assert.Nil(t, err)
+		}
+		sections, err := boards.GetSections(1, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(sections))
+		assert.Equal(t, 1, sections[1].Position)
+
+		section_id := sections[1].ID
+		_, err = repo.Move(2, 1, request.MoveRequest{Section_id: &section_id})
+		assert.Nil(t, err)
+		columns := order(t, board.BySection)
+		assert.Equal(t, []string{"C", "A"}, columns["none"])
+		assert.Equal(t, []string{"B"}, columns[sectionKey(section_id)])
+
+		assert.Nil(t, boards.DeleteSection(1, int(section_id), 1))
+		assert.Equal(t, []string{"C", "A", "B"}, order(t, board.BySection)["none"])
+	})
+
+	t.Run("fail run GetBoard", func(t *testing.T) {
+		_, err := boards.GetBoard(1, 1, "label")
+		assert.Equal(t, ErrInvalidBoard, err)
+	})
+}
+
+func TestCriticalPath(t *testing.T) {
+	nodes := []response.GraphNodeResponse{{ID: 1, Duration: 2}, {ID: 2, Duration: 1}, {ID: 3, Duration: 4}, {ID: 4, Duration: 1}, {ID: 5, Duration: 0}}
+	edges := []response.GraphEdgeResponse{{Blocker_id: 1, Blocked_id: 2}, {Blocker_id: 3, Blocked_id: 4}, {Blocker_id: 2, Blocked_id: 4}}
+
+	path, length := criticalPath(nodes, edges)
+	assert.Equal(t, []uint{3, 4}, path)
+	assert.Equal(t, 5, length)
+
+	path, length = criticalPath(nil, nil)
+	assert.Equal(t, []uint{}, path)
+	assert.Equal(t, 0, length)
 }
@@ -12,6 +12,7 @@ type User interface {
 	Create(newUser user.User) (user.User, error)
 	GetById(id int) (response.UserResponse, error)
 	UpdateById(id int, userReg request.UserRegister) (user.User, error)
+	UpdateRole(id int, role string) (user.User, error)
 	DeleteById(id int) (gorm.DeletedAt, error)
 	GetAll() ([]response.UserResponse, error)
 }
@@ -1,17 +1,25 @@
 package user
 
 import (
-	"errors"
+	"part3/models/base"
 	proResp "part3/models/project/response"
+	"part3/models/session"
 	taskResp "part3/models/task/response"
 	"part3/models/user"
 	"part3/models/user/request"
 	"part3/models/user/response"
+	"part3/utils"
+	"time"
 
 	"github.com/labstack/gommon/log"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrEmailTaken  = base.ConflictError("email_taken", "email is already registered")
+	ErrInvalidRole = base.ValidationError("invalid_role", "invalid role")
+)
+
 type UserDb struct {
 	db *gorm.DB
 }
@@ -21,7 +29,19 @@ func New(db *gorm.DB) *UserDb {
 }
 
 func (ud *UserDb) Create(newUser user.User) (user.User, error) {
+	hashed, err := utils.HashPassword(newUser.Password)
+	if err != nil {
+		return newUser, err
+	}
+	newUser.Password = hashed
+	if newUser.Role == "" {
+		newUser.Role = user.RoleMember
+	}
+
 	if err := ud.db.Create(&newUser).Error; err != nil {
+		if utils.IsDuplicate(err) {
+			return newUser, ErrEmailTaken
+		}
 		return newUser, err
 	}
 	return newUser, nil
@@ -38,7 +58,7 @@ func (ud *UserDb) GetById(id int) (response.UserResponse, error) {
 
 	project := []proResp.ProResponse{}
 
-	resPro := ud.db.Model(&user.User{}).Where("users.id = ?", id).Select("projects.id as Id, projects.created_at as Created_at, projects.updated_at as Updated_at, projects.name as Name").Joins("inner join projects on projects.user_id = users.id").Find(&project)
+	resPro := ud.db.Model(&user.User{}).Where("users.id = ?", id).Select("projects.id as id, projects.created_at as created_at, projects.updated_at as updated_at, projects.name as name").Joins("inner join projects on projects.user_id = users.id").Find(&project)
 
 	if resPro.Error != nil {
 		return userResp, resPro.Error
@@ -47,7 +67,7 @@ func (ud *UserDb) GetById(id int) (response.UserResponse, error) {
 
 	task := []taskResp.TaskResponse{}
 
-	resTask := ud.db.Model(&user.User{}).Where("users.id = ?", id).Select("tasks.id as ID, tasks.created_at as CreatedAt, tasks.updated_at as UpdatedAt, tasks.name as Name, tasks.project_id as Project_id,tasks.priority as Priority ,projects.name as Project_name").Joins("inner join tasks on users.id = tasks.user_id").Joins("inner join projects on projects.id = tasks.project_id").Find(&task)
+	resTask := ud.db.Model(&user.User{}).Where("users.id = ?", id).Select("tasks.id as id, tasks.created_at as created_at, tasks.updated_at as updated_at, tasks.name as name, tasks.project_id as project_id,tasks.priority as priority ,projects.name as project_name").Joins("inner join tasks on users.id = tasks.created_by AND tasks.deleted_at IS NULL").Joins("inner join projects on projects.id = tasks.project_id").Find(&task)
 
 	if resTask.Error != nil {
 		return userResp, resTask.Error
@@ -58,17 +78,73 @@ func (ud *UserDb) GetById(id int) (response.UserResponse, error) {
 	return userResp, nil
 }
 
+// GetByEmail finds the user signing in with email, for the admin commands
+func (ud *UserDb) GetByEmail(email string) (user.User, error) {
+	found := user.User{}
+
+	if err := ud.db.Where("email = ?", email).First(&found).Error; err != nil {
+		return found, err
+	}
+	return found, nil
+}
+
+// UpdateById replaces the name and the email, an empty name is written too.
+// The password only changes when given
 func (ud *UserDb) UpdateById(id int, userReg request.UserRegister) (user.User, error) {
+	updates := map[string]interface{}{"name": userReg.Name, "email": userReg.Email}
+	if userReg.Password != "" {
+		hashed, err := utils.HashPassword(userReg.Password)
+		if err != nil {
+			return user.User{}, err
+		}
+		updates["password"] = hashed
+	}
 
-	res := ud.db.Model(&user.User{Model: gorm.Model{ID: uint(id)}}).Updates(user.User{Name: userReg.Name, Email: userReg.Email, Password: userReg.Password})
+	res := ud.db.Model(&user.User{}).Where("id = ?", id).Updates(updates)
 
+	if utils.IsDuplicate(res.Error) {
+		return user.User{}, ErrEmailTaken
+	}
+	if res.Error != nil {
+		return user.User{}, res.Error
+	}
 	if res.RowsAffected == 0 {
-		return user.User{}, errors.New(gorm.ErrRecordNotFound.Error())
+		return user.User{}, gorm.ErrRecordNotFound
+	}
+
+	upUser := user.User{}
+	if err := ud.db.First(&upUser, id).Error; err != nil {
+		return upUser, err
+	}
+
+	return upUser, nil
+}
+
+// UpdateRole revokes the sessions of the user when the role changes, the role
+// travels in the access token so the old one would keep working until expiry
+func (ud *UserDb) UpdateRole(id int, role string) (user.User, error) {
+	if !user.IsValidRole(role) {
+		return user.User{}, ErrInvalidRole
 	}
 
-	user := userReg.ToUser()
+	upUser := user.User{}
+	err := ud.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&upUser, id).Error; err != nil {
+			return err
+		}
+		if upUser.Role == role {
+			return nil
+		}
+		if err := tx.Model(&upUser).Update("role", role).Error; err != nil {
+			return err
+		}
+		return tx.Model(&session.Session{}).Where("user_id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now()).Error
+	})
+	if err != nil {
+		return user.User{}, err
+	}
 
-	return user, nil
+	return upUser, nil
 }
 
 func (ud *UserDb) DeleteById(id int) (gorm.DeletedAt, error) {
@@ -76,7 +152,7 @@ func (ud *UserDb) DeleteById(id int) (gorm.DeletedAt, error) {
 
 	res := ud.db.Model(&user).Where("id = ?", id).Delete(&user)
 	if res.RowsAffected == 0 {
-		return user.DeletedAt, errors.New(gorm.ErrRecordNotFound.Error())
+		return user.DeletedAt, gorm.ErrRecordNotFound
 	}
 
 	return user.DeletedAt, nil
@@ -88,14 +164,14 @@ func (ud *UserDb) GetAll() ([]response.UserResponse, error) {
 	res := ud.db.Model(user.User{}).Find(&userRespArr)
 	log.Info(res.RowsAffected)
 	if res.RowsAffected == 0 {
-		return nil, errors.New(gorm.ErrRecordNotFound.Error())
+		return nil, gorm.ErrRecordNotFound
 	}
 
 	for i := 0; i < len(userRespArr); i++ {
 
 		project := []proResp.ProResponse{}
 
-		resPro := ud.db.Model(&user.User{}).Where("users.id = ?", userRespArr[i].ID).Select("projects.id as Id, projects.created_at as Created_at, projects.updated_at as Updated_at, projects.name as Name").Joins("inner join projects on projects.user_id = users.id").Find(&project)
+		resPro := ud.db.Model(&user.User{}).Where("users.id = ?", userRespArr[i].ID).Select("projects.id as id, projects.created_at as created_at, projects.updated_at as updated_at, projects.name as name").Joins("inner join projects on projects.user_id = users.id").Find(&project)
 
 		if resPro.Error != nil {
 			return userRespArr, resPro.Error
@@ -103,7 +179,7 @@ func (ud *UserDb) GetAll() ([]response.UserResponse, error) {
 		userRespArr[i].Projects = project
 
 		task := []taskResp.TaskResponse{}
-		resTask := ud.db.Model(&user.User{}).Where("users.id = ?", userRespArr[i].ID).Select("tasks.id as ID, tasks.created_at as CreatedAt, tasks.updated_at as UpdatedAt, tasks.name as Name, tasks.project_id as Project_id,tasks.priority as Priority ,projects.name as Project_name").Joins("inner join tasks on users.id = tasks.user_id").Joins("inner join projects on projects.id = tasks.project_id").Find(&task)
+		resTask := ud.db.Model(&user.User{}).Where("users.id = ?", userRespArr[i].ID).Select("tasks.id as id, tasks.created_at as created_at, tasks.updated_at as updated_at, tasks.name as name, tasks.project_id as project_id,tasks.priority as priority ,projects.name as project_name").Joins("inner join tasks on users.id = tasks.created_by AND tasks.deleted_at IS NULL").Joins("inner join projects on projects.id = tasks.project_id").Find(&task)
 
 		if resTask.Error != nil {
 			return userRespArr, resTask.Error
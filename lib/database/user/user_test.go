@@ -5,11 +5,13 @@ import (
 	_libPro "part3/lib/database/project"
 	_libTask "part3/lib/database/task"
 	"part3/models/project"
+	"part3/models/session"
 	"part3/models/task"
 	"part3/models/user"
 	"part3/models/user/request"
 	"part3/utils"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -19,11 +21,9 @@ func TestCreate(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&user.User{})
-
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run Create", func(t *testing.T) {
 		mocUser := user.User{Name: "anonim123", Email: "anonim@123", Password: "anonim123"}
@@ -31,7 +31,8 @@ func TestCreate(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, "anonim123", res.Name)
 		assert.Equal(t, "anonim@123", res.Email)
-		assert.Equal(t, "anonim123", res.Password)
+		assert.NotEqual(t, "anonim123", res.Password)
+		assert.True(t, utils.CheckPassword(res.Password, "anonim123"))
 
 	})
 
@@ -44,18 +45,21 @@ func TestCreate(t *testing.T) {
 		_, err := repo.Create(mocUser)
 		assert.NotNil(t, err)
 	})
+
+	t.Run("fail run Create email taken", func(t *testing.T) {
+		mocUser := user.User{Name: "anonim2", Email: "anonim@1", Password: "anonim2"}
+		_, err := repo.Create(mocUser)
+		assert.Equal(t, ErrEmailTaken, err)
+	})
 }
 
 func TestGetById(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run GetById", func(t *testing.T) {
 		mocUser := user.User{Name: "anonim123", Email: "anonim@1", Password: "anonim1"}
@@ -133,16 +137,37 @@ func TestGetById(t *testing.T) {
 	// })
 }
 
+func TestGetByEmail(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("success run GetByEmail", func(t *testing.T) {
+		mocUser := user.User{Name: "anonim123", Email: "anonim@123", Password: "anonim123"}
+		if _, err := repo.Create(mocUser); err != nil {
+			t.Fatal()
+		}
+		res, err := repo.GetByEmail("anonim@123")
+		assert.Nil(t, err)
+		assert.Equal(t, "anonim123", res.Name)
+	})
+
+	t.Run("fail run GetByEmail", func(t *testing.T) {
+		_, err := repo.GetByEmail("anonim@456")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+}
+
 func TestUpdateById(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run UpdateById", func(t *testing.T) {
 		mocUser := user.User{Name: "anonim123", Email: "anonim@123", Password: "anonim123"}
@@ -155,7 +180,15 @@ func TestUpdateById(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, "anonim321", res.Name)
 		assert.Equal(t, "anonim@321", res.Email)
-		assert.Equal(t, "anonim321", res.Password)
+		assert.True(t, utils.CheckPassword(res.Password, "anonim321"))
+	})
+
+	t.Run("success run UpdateById clears name and keeps password", func(t *testing.T) {
+		res, err := repo.UpdateById(1, request.UserRegister{Email: "anonim@321"})
+		assert.Nil(t, err)
+		assert.Equal(t, "", res.Name)
+		assert.Equal(t, "anonim@321", res.Email)
+		assert.True(t, utils.CheckPassword(res.Password, "anonim321"))
 	})
 
 	t.Run("fail run UpdateById", func(t *testing.T) {
@@ -165,16 +198,63 @@ func TestUpdateById(t *testing.T) {
 	})
 }
 
+func TestUpdateRole(t *testing.T) {
+	config := configs.GetConfig()
+	db := utils.InitDB(config)
+	repo := New(db)
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("success run UpdateRole", func(t *testing.T) {
+		mocUser := user.User{Name: "anonim123", Email: "anonim@123", Password: "anonim123"}
+		res, err := repo.Create(mocUser)
+		if err != nil {
+			t.Fatal()
+		}
+		assert.Equal(t, user.RoleMember, res.Role)
+
+		res, err = repo.UpdateRole(1, user.RoleAdmin)
+		assert.Nil(t, err)
+		assert.Equal(t, user.RoleAdmin, res.Role)
+	})
+
+	t.Run("success run UpdateRole revokes sessions", func(t *testing.T) {
+		sess := session.Session{User_ID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+		if err := db.Create(&sess).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := repo.UpdateRole(1, user.RoleAdmin)
+		assert.Nil(t, err)
+		db.First(&sess, sess.ID)
+		assert.Nil(t, sess.RevokedAt)
+
+		res, err := repo.UpdateRole(1, user.RoleMember)
+		assert.Nil(t, err)
+		assert.Equal(t, user.RoleMember, res.Role)
+		db.First(&sess, sess.ID)
+		assert.NotNil(t, sess.RevokedAt)
+	})
+
+	t.Run("fail run UpdateRole invalid role", func(t *testing.T) {
+		_, err := repo.UpdateRole(1, "superuser")
+		assert.Equal(t, ErrInvalidRole, err)
+	})
+
+	t.Run("fail run UpdateRole", func(t *testing.T) {
+		_, err := repo.UpdateRole(10, user.RoleAdmin)
+		assert.NotNil(t, err)
+	})
+}
+
 func TestDeleteById(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run DeleteById", func(t *testing.T) {
 		mocUser := user.User{Name: "anonim123", Email: "anonim@123", Password: "anonim123"}
@@ -198,12 +278,9 @@ func TestGetAll(t *testing.T) {
 	config := configs.GetConfig()
 	db := utils.InitDB(config)
 	repo := New(db)
-	db.Migrator().DropTable(&project.Project{})
-	db.Migrator().DropTable(&task.Task{})
-	db.Migrator().DropTable(&user.User{})
-	db.AutoMigrate(&project.Project{})
-	db.AutoMigrate(&task.Task{})
-	db.AutoMigrate(&user.User{})
+	if err := utils.ResetDB(db); err != nil {
+		t.Fatal(err)
+	}
 
 	t.Run("success run GetAll", func(t *testing.T) {
 		mocUser := user.User{Name: "anonim1", Email: "anonim@1", Password: "anonim1"}
@@ -0,0 +1,107 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"part3/configs"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New picks the mailer from the mail driver, file and log are meant for local development and tests
+func New(config *configs.AppConfig) (Mailer, error) {
+	switch config.Mail.Driver {
+	case "smtp":
+		if config.Mail.Host == "" {
+			return nil, fmt.Errorf("smtp mailer needs a host")
+		}
+		return NewSMTP(config.Mail.Host, config.Mail.Port, config.Mail.Username, config.Mail.Password, config.Mail.From), nil
+	case "file":
+		if config.Mail.File == "" {
+			return nil, fmt.Errorf("file mailer needs a file")
+		}
+		return NewFile(config.Mail.File, config.Mail.From), nil
+	case "log", "":
+		return NewFile("", config.Mail.From), nil
+	}
+	return nil, fmt.Errorf("unsupported mail driver %s", config.Mail.Driver)
+}
+
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTP(host string, port int, username string, password string, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{addr: fmt.Sprintf("%v:%v", host, port), auth: auth, from: from}
+}
+
+func (sm *SMTPMailer) Send(msg Message) error {
+	return smtp.SendMail(sm.addr, sm.auth, sm.from, []string{msg.To}, format(sm.from, msg))
+}
+
+// FileMailer appends every message to a file, or to the log when no file is set
+type FileMailer struct {
+	lock *sync.Mutex
+	path string
+	from string
+}
+
+func NewFile(path string, from string) *FileMailer {
+	return &FileMailer{lock: &sync.Mutex{}, path: path, from: from}
+}
+
+func (fm *FileMailer) Send(msg Message) error {
+	raw := format(fm.from, msg)
+	if fm.path == "" {
+		log.Info("mail ", string(raw))
+		return nil
+	}
+
+	fm.lock.Lock()
+	defer fm.lock.Unlock()
+
+	file, err := os.OpenFile(fm.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(raw, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+func format(from string, msg Message) []byte {
+	// header values must not carry line breaks or a crafted subject could add headers
+	clean := strings.NewReplacer("\r", "", "\n", "")
+	headers := []string{
+		"From: " + clean.Replace(from),
+		"To: " + clean.Replace(msg.To),
+		"Subject: " + clean.Replace(msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + msg.Body + "\r\n")
+}
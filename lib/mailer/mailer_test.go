@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"io/ioutil"
+	"part3/configs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+
+	t.Run("success run Send", func(t *testing.T) {
+		mail := NewFile(path, "noreply@anonim")
+		err := mail.Send(Message{To: "anonim@123", Subject: "Invitation\r\nBcc: evil@123", Body: "hello"})
+		assert.Nil(t, err)
+
+		raw, _ := ioutil.ReadFile(path)
+		assert.True(t, strings.Contains(string(raw), "To: anonim@123\r\n"))
+		assert.True(t, strings.Contains(string(raw), "Subject: InvitationBcc: evil@123\r\n"))
+		assert.True(t, strings.HasSuffix(string(raw), "hello\r\n\n"))
+	})
+
+	t.Run("fail run Send", func(t *testing.T) {
+		mail := NewFile(filepath.Join(path, "missing", "mail.log"), "noreply@anonim")
+		assert.NotNil(t, mail.Send(Message{To: "anonim@123"}))
+	})
+}
+
+func TestNew(t *testing.T) {
+	config := &configs.AppConfig{}
+
+	config.Mail.Driver = "log"
+	_, err := New(config)
+	assert.Nil(t, err)
+
+	config.Mail.Driver = "smtp"
+	_, err = New(config)
+	assert.NotNil(t, err)
+
+	config.Mail.Host = "localhost"
+	res, err := New(config)
+	assert.Nil(t, err)
+	assert.IsType(t, &SMTPMailer{}, res)
+
+	config.Mail.Driver = "pigeon"
+	_, err = New(config)
+	assert.NotNil(t, err)
+}
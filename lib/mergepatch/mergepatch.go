@@ -0,0 +1,82 @@
+// Package mergepatch applies JSON Merge Patch documents (RFC 7396): a member
+// of the patch replaces the one of the target, null removes it and objects are
+// merged member by member. Arrays and other values are replaced whole.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+var ErrNotObject = errors.New("merge patch must be a JSON object")
+
+// Apply returns doc patched with patch, numbers are copied as written
+func Apply(doc []byte, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+	if err := decode(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := decode(patch, &changes); err != nil {
+		return nil, err
+	}
+	return json.Marshal(merge(target, changes))
+}
+
+// Merge patches the JSON form of the struct target points to and reads the
+// result back into it, a removed member leaves its field at the zero value
+func Merge(target interface{}, patch []byte) error {
+	var changes interface{}
+	if err := decode(patch, &changes); err != nil {
+		return err
+	}
+	if _, ok := changes.(map[string]interface{}); !ok {
+		return ErrNotObject
+	}
+
+	doc, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	merged, err := Apply(doc, patch)
+	if err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(target).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	return json.Unmarshal(merged, target)
+}
+
+func merge(target interface{}, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	doc, ok := target.(map[string]interface{})
+	if !ok {
+		doc = map[string]interface{}{}
+	}
+	for name, value := range changes {
+		if value == nil {
+			delete(doc, name)
+			continue
+		}
+		doc[name] = merge(doc[name], value)
+	}
+	return doc
+}
+
+func decode(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	// only one document
+	if decoder.More() {
+		return errors.New("merge patch has data after the document")
+	}
+	return nil
+}
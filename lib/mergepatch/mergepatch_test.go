@@ -0,0 +1,82 @@
+package mergepatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	t.Run("success run Apply", func(t *testing.T) {
+		// the examples of RFC 7396 appendix A
+		for _, tc := range []struct{ doc, patch, result string }{
+			{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+			{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+			{`{"a":"b"}`, `{"a":null}`, `{}`},
+			{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+			{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+			{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+			{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+			{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+			{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+			{`{"a":"b"}`, `["c"]`, `["c"]`},
+			{`{"a":"foo"}`, `null`, `null`},
+			{`{"a":"foo"}`, `"bar"`, `"bar"`},
+			{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+			{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+			{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		} {
+			result, err := Apply([]byte(tc.doc), []byte(tc.patch))
+			assert.Nil(t, err)
+			assert.JSONEq(t, tc.result, string(result), tc.doc+" "+tc.patch)
+		}
+	})
+
+	t.Run("success run Apply keeps numbers", func(t *testing.T) {
+		result, err := Apply([]byte(`{"id":9007199254740993,"priority":2}`), []byte(`{"priority":0}`))
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"id":9007199254740993,"priority":0}`, string(result))
+	})
+
+	t.Run("fail run Apply", func(t *testing.T) {
+		_, err := Apply([]byte(`{"a":"b"}`), []byte(`{"a":`))
+		assert.NotNil(t, err)
+		_, err = Apply([]byte(`{"a":"b"}`), []byte(`{"a":1} {"b":2}`))
+		assert.NotNil(t, err)
+	})
+}
+
+type document struct {
+	Name     string     `json:"name"`
+	Priority int        `json:"priority"`
+	Parent   *uint      `json:"parent_id"`
+	DueAt    *time.Time `json:"due_at"`
+	Tags     []string   `json:"tags"`
+}
+
+func TestMerge(t *testing.T) {
+	parent := uint(3)
+	due := time.Date(2022, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("success run Merge", func(t *testing.T) {
+		doc := document{Name: "report", Priority: 2, Parent: &parent, DueAt: &due, Tags: []string{"a"}}
+		err := Merge(&doc, []byte(`{"priority":0,"parent_id":null,"tags":["b","c"]}`))
+		assert.Nil(t, err)
+		assert.Equal(t, document{Name: "report", Priority: 0, DueAt: &due, Tags: []string{"b", "c"}}, doc)
+	})
+
+	t.Run("success run Merge empty patch", func(t *testing.T) {
+		doc := document{Name: "report", Priority: 2, Parent: &parent}
+		assert.Nil(t, Merge(&doc, []byte(`{}`)))
+		assert.Equal(t, document{Name: "report", Priority: 2, Parent: &parent}, doc)
+	})
+
+	t.Run("fail run Merge", func(t *testing.T) {
+		doc := document{Name: "report"}
+		assert.Equal(t, ErrNotObject, Merge(&doc, []byte(`["name"]`)))
+		assert.Equal(t, ErrNotObject, Merge(&doc, []byte(`null`)))
+		assert.NotNil(t, Merge(&doc, []byte(`{"priority":"high"}`)))
+		assert.NotNil(t, Merge(&doc, []byte(`not json`)))
+	})
+}
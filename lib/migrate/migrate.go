@@ -0,0 +1,430 @@
+// Package migrate applies versioned SQL files in order and records each one in
+// the schema_migrations table with the checksum of its up file, so an applied
+// file that was edited afterwards is refused instead of silently skipped.
+//
+// Every run holds a lock, GET_LOCK on MySQL and an advisory lock on
+// PostgreSQL, so instances booting together apply a migration once. SQLite has
+// a single writer, every migration runs in a transaction there and a second
+// instance fails on its write instead of applying the migration again.
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidName = errors.New("invalid migration file name")
+	ErrChecksum    = errors.New("applied migration was changed")
+	ErrMissing     = errors.New("applied migration has no file")
+	ErrLocked      = errors.New("schema is locked by another migration")
+)
+
+const (
+	lockName = "schema_migrations"
+	// lockKey is the PostgreSQL advisory lock key, any number works as long as
+	// it stays the same
+	lockKey = 5181221311
+	// lockWait is how many seconds MySQL waits for another migration to finish
+	lockWait = 60
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  uint
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Record is a row of schema_migrations
+type Record struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null;type:varchar(255)"`
+	Checksum  string    `gorm:"not null;type:varchar(64)"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (Record) TableName() string {
+	return "schema_migrations"
+}
+
+// Status is a migration and whether it was applied. Changed is set when its up
+// file differs from the applied one, Missing when an applied migration has no
+// file anymore
+type Status struct {
+	Version   uint
+	Name      string
+	AppliedAt *time.Time
+	Changed   bool
+	Missing   bool
+}
+
+// Load reads the NNNN_name.up.sql and NNNN_name.down.sql pairs of files in
+// version order, every migration needs both files
+func Load(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidName, entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidName, entry.Name())
+		}
+		content, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[m.Version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: %s and %s share version %d", ErrInvalidName, m.Name, match[2], version)
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			return nil, fmt.Errorf("%w: %s needs an up and a down file", ErrInvalidName, m)
+		}
+		m.Checksum = checksum(m.Up)
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// statements splits a file into its statements, each ending with a semicolon
+// at the end of a line. Lines holding only a comment are dropped
+func statements(content string) []string {
+	found := []string{}
+	current := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current = append(current, strings.TrimRight(line, " \t\r"))
+		if strings.HasSuffix(trimmed, ";") {
+			stmt := strings.Join(current, "\n")
+			found = append(found, strings.TrimSuffix(stmt, ";"))
+			current = []string{}
+		}
+	}
+	if len(current) > 0 {
+		found = append(found, strings.Join(current, "\n"))
+	}
+	return found
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New reads the migrations of files, the directory of the driver of db
+func New(db *gorm.DB, files fs.FS) (*Migrator, error) {
+	migrations, err := Load(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies every pending migration in version order and returns them
+func (m *Migrator) Up() ([]Migration, error) {
+	applied := []Migration{}
+
+	err := m.locked(func(conn *gorm.DB) error {
+		records, err := m.verified(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := records[migration.Version]; ok {
+				continue
+			}
+			record := Record{Version: migration.Version, Name: migration.Name, Checksum: migration.Checksum, AppliedAt: time.Now().UTC()}
+			if err := run(conn, migration.Up, func(tx *gorm.DB) error { return tx.Create(&record).Error }); err != nil {
+				return fmt.Errorf("migration %s: %w", migration, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns them
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	reverted := []Migration{}
+
+	err := m.locked(func(conn *gorm.DB) error {
+		records, err := m.verified(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := records[migration.Version]; !ok {
+				continue
+			}
+			record := Record{Version: migration.Version}
+			if err := run(conn, migration.Down, func(tx *gorm.DB) error { return tx.Delete(&record).Error }); err != nil {
+				return fmt.Errorf("migration %s: %w", migration, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Mark records the migrations up to version as applied without running them,
+// for a database whose schema was made another way
+func (m *Migrator) Mark(version uint) error {
+	return m.locked(func(conn *gorm.DB) error {
+		records, err := m.records(conn)
+		if err != nil {
+			return err
+		}
+		return m.mark(conn, records, version)
+	})
+}
+
+// Adopt brings a database made without the migrations under them. When none
+// is recorded and legacy finds the old schema, upgrade brings it to version and
+// the migrations up to version are marked, all under the lock so two instances
+// starting together upgrade once. A failed upgrade marks nothing
+func (m *Migrator) Adopt(version uint, legacy func(conn *gorm.DB) bool, upgrade func(conn *gorm.DB) error) error {
+	return m.locked(func(conn *gorm.DB) error {
+		records, err := m.records(conn)
+		if err != nil {
+			return err
+		}
+		if len(records) > 0 || !legacy(conn) {
+			return nil
+		}
+		if err := upgrade(conn); err != nil {
+			return err
+		}
+		return m.mark(conn, records, version)
+	})
+}
+
+func (m *Migrator) mark(conn *gorm.DB, records map[uint]Record, version uint) error {
+	for _, migration := range m.migrations {
+		if _, ok := records[migration.Version]; ok || migration.Version > version {
+			continue
+		}
+		record := Record{Version: migration.Version, Name: migration.Name, Checksum: migration.Checksum, AppliedAt: time.Now().UTC()}
+		if err := conn.Create(&record).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status lists every migration, then the applied ones whose file is gone
+func (m *Migrator) Status() ([]Status, error) {
+	statuses := []Status{}
+
+	err := m.locked(func(conn *gorm.DB) error {
+		records, err := m.records(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if record, ok := records[migration.Version]; ok {
+				appliedAt := record.AppliedAt
+				status.AppliedAt = &appliedAt
+				status.Changed = record.Checksum != migration.Checksum
+				delete(records, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+		for _, record := range records {
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, Status{Version: record.Version, Name: record.Name, AppliedAt: &appliedAt, Missing: true})
+		}
+		return nil
+	})
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, err
+}
+
+func (m *Migrator) records(conn *gorm.DB) (map[uint]Record, error) {
+	found := []Record{}
+	if err := conn.Order("version").Find(&found).Error; err != nil {
+		return nil, err
+	}
+	records := map[uint]Record{}
+	for _, record := range found {
+		records[record.Version] = record
+	}
+	return records, nil
+}
+
+// verified returns the applied migrations once every one of them still has the
+// file it was applied from
+func (m *Migrator) verified(conn *gorm.DB) (map[uint]Record, error) {
+	records, err := m.records(conn)
+	if err != nil {
+		return nil, err
+	}
+	files := map[uint]Migration{}
+	for _, migration := range m.migrations {
+		files[migration.Version] = migration
+	}
+	for _, record := range records {
+		migration, ok := files[record.Version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %04d_%s", ErrMissing, record.Version, record.Name)
+		}
+		if migration.Checksum != record.Checksum {
+			return nil, fmt.Errorf("%w: %s", ErrChecksum, migration)
+		}
+	}
+	return records, nil
+}
+
+// locked runs fn on one connection holding the migration lock, schema_migrations
+// is made first when missing
+func (m *Migrator) locked(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		switch conn.Dialector.Name() {
+		case "mysql":
+			var got *int
+			if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, lockWait).Row().Scan(&got); err != nil {
+				return err
+			}
+			if got == nil || *got != 1 {
+				return ErrLocked
+			}
+			defer conn.Exec("SELECT RELEASE_LOCK(?)", lockName)
+		case "postgres":
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+				return err
+			}
+			defer conn.Exec("SELECT pg_advisory_unlock(?)", lockKey)
+		}
+
+		if !conn.Migrator().HasTable(&Record{}) {
+			if err := conn.Migrator().CreateTable(&Record{}); err != nil {
+				return err
+			}
+		}
+		// every query fn makes starts from a clean statement on the locked connection
+		return fn(conn.Session(&gorm.Session{NewDB: true}))
+	})
+}
+
+// run executes the statements of a file and records it in one transaction,
+// MySQL commits every schema change on its own so a failed MySQL migration
+// has to be cleaned up by hand
+func run(conn *gorm.DB, content string, record func(tx *gorm.DB) error) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range statements(content) {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return record(tx)
+	})
+}
+
+// Create adds the empty files of the next version to every driver directory
+// of dir and returns their paths
+func Create(dir string, name string) ([]string, error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), "_"))
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	drivers := []string{}
+	version := uint(0)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		drivers = append(drivers, entry.Name())
+		migrations, err := Load(os.DirFS(filepath.Join(dir, entry.Name())))
+		if err != nil {
+			return nil, err
+		}
+		if n := len(migrations); n > 0 && migrations[n-1].Version > version {
+			version = migrations[n-1].Version
+		}
+	}
+	if len(drivers) == 0 {
+		return nil, fmt.Errorf("no driver directory in %s", dir)
+	}
+
+	paths := []string{}
+	for _, driver := range drivers {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, driver, fmt.Sprintf("%04d_%s.%s.sql", version+1, name, direction))
+			content := fmt.Sprintf("-- %s %s for %s\n", name, direction, driver)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,238 @@
+package migrate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func files() fstest.MapFS {
+	return fstest.MapFS{
+		"0001_notes.up.sql":         {Data: []byte("-- notes\nCREATE TABLE notes (\n    id integer PRIMARY KEY\n);\nCREATE INDEX idx_notes ON notes (id);\n")},
+		"0001_notes.down.sql":       {Data: []byte("DROP TABLE notes;\n")},
+		"0002_note_body.up.sql":     {Data: []byte("ALTER TABLE notes ADD COLUMN body text;\n")},
+		"0002_note_body.down.sql":   {Data: []byte("ALTER TABLE notes DROP COLUMN body;\n")},
+		"0010_note_labels.up.sql":   {Data: []byte("CREATE TABLE note_labels (id integer);\n")},
+		"0010_note_labels.down.sql": {Data: []byte("DROP TABLE note_labels;\n")},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("success run Load", func(t *testing.T) {
+		res, err := Load(files())
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(res))
+		assert.Equal(t, uint(10), res[2].Version)
+		assert.Equal(t, "0002_note_body", res[1].String())
+		assert.Equal(t, 64, len(res[0].Checksum))
+	})
+
+	t.Run("fail run Load", func(t *testing.T) {
+		for _, name := range []string{"notes.up.sql", "0003_Notes.up.sql", "0000_notes.up.sql", "0003_notes.sql"} {
+			broken := files()
+			broken[name] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+			_, err := Load(broken)
+			assert.True(t, errors.Is(err, ErrInvalidName), name)
+		}
+
+		broken := files()
+		delete(broken, "0002_note_body.down.sql")
+		_, err := Load(broken)
+		assert.True(t, errors.Is(err, ErrInvalidName))
+
+		broken = files()
+		broken["0002_other.down.sql"] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+		_, err = Load(broken)
+		assert.True(t, errors.Is(err, ErrInvalidName))
+	})
+}
+
+func TestStatements(t *testing.T) {
+	assert.Equal(t, []string{
+		"CREATE TABLE notes (\n    id integer\n)",
+		"INSERT INTO notes VALUES (1)",
+		"SELECT 1",
+	}, statements("-- a comment\n\nCREATE TABLE notes (\n    id integer\n);\r\nINSERT INTO notes VALUES (1);\nSELECT 1\n"))
+}
+
+func TestMigrator(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	migrator, err := New(db, files())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("success run Up", func(t *testing.T) {
+		res, err := migrator.Up()
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(res))
+		assert.True(t, db.Migrator().HasColumn("notes", "body"))
+		assert.True(t, db.Migrator().HasIndex("notes", "idx_notes"))
+
+		res, err = migrator.Up()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(res))
+	})
+
+	t.Run("success run Down", func(t *testing.T) {
+		res, err := migrator.Down(2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.Equal(t, uint(10), res[0].Version)
+		assert.False(t, db.Migrator().HasTable("note_labels"))
+		assert.False(t, db.Migrator().HasColumn("notes", "body"))
+
+		statuses, err := migrator.Status()
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(statuses))
+		assert.NotNil(t, statuses[0].AppliedAt)
+		assert.Nil(t, statuses[1].AppliedAt)
+	})
+
+	t.Run("fail run Up changed migration", func(t *testing.T) {
+		changed := files()
+		changed["0001_notes.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE notes (id integer);\n")}
+		other, err := New(db, changed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = other.Up()
+		assert.True(t, errors.Is(err, ErrChecksum))
+
+		statuses, err := other.Status()
+		assert.Nil(t, err)
+		assert.True(t, statuses[0].Changed)
+	})
+
+	t.Run("fail run Up missing migration", func(t *testing.T) {
+		missing := files()
+		delete(missing, "0001_notes.up.sql")
+		delete(missing, "0001_notes.down.sql")
+		other, err := New(db, missing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = other.Up()
+		assert.True(t, errors.Is(err, ErrMissing))
+
+		statuses, err := other.Status()
+		assert.Nil(t, err)
+		assert.True(t, statuses[0].Missing)
+	})
+
+	t.Run("fail run Up rolls back the failed migration", func(t *testing.T) {
+		broken := files()
+		broken["0002_note_body.up.sql"] = &fstest.MapFile{Data: []byte("ALTER TABLE notes ADD COLUMN body text;\nSELECT nothing FROM nowhere;\n")}
+		other, err := New(db, broken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = other.Up()
+		assert.NotNil(t, err)
+		assert.False(t, db.Migrator().HasColumn("notes", "body"))
+	})
+
+	t.Run("success run Mark", func(t *testing.T) {
+		_, err := migrator.Down(1)
+		assert.Nil(t, err)
+		assert.False(t, db.Migrator().HasTable("notes"))
+
+		assert.Nil(t, db.Exec("CREATE TABLE notes (id integer PRIMARY KEY)").Error)
+		assert.Nil(t, migrator.Mark(1))
+		res, err := migrator.Up()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+	})
+}
+
+func TestAdopt(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	migrator, err := New(db, files())
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := func(conn *gorm.DB) bool { return conn.Migrator().HasTable("notes") }
+
+	t.Run("success run Adopt new database", func(t *testing.T) {
+		upgraded := false
+		assert.Nil(t, migrator.Adopt(1, legacy, func(conn *gorm.DB) error { upgraded = true; return nil }))
+		assert.False(t, upgraded)
+
+		statuses, err := migrator.Status()
+		assert.Nil(t, err)
+		assert.Nil(t, statuses[0].AppliedAt)
+	})
+
+	t.Run("fail run Adopt marks nothing", func(t *testing.T) {
+		assert.Nil(t, db.Exec("CREATE TABLE notes (id integer PRIMARY KEY)").Error)
+		assert.Equal(t, ErrLocked, migrator.Adopt(1, legacy, func(conn *gorm.DB) error { return ErrLocked }))
+
+		statuses, err := migrator.Status()
+		assert.Nil(t, err)
+		assert.Nil(t, statuses[0].AppliedAt)
+	})
+
+	t.Run("success run Adopt", func(t *testing.T) {
+		upgraded := 0
+		upgrade := func(conn *gorm.DB) error { upgraded++; return nil }
+		assert.Nil(t, migrator.Adopt(1, legacy, upgrade))
+		assert.Nil(t, migrator.Adopt(1, legacy, upgrade))
+		assert.Equal(t, 1, upgraded)
+
+		res, err := migrator.Up()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+	})
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for _, driver := range []string{"mysql", "sqlite"} {
+		if err := os.Mkdir(filepath.Join(dir, driver), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, file := range files() {
+		if err := os.WriteFile(filepath.Join(dir, "sqlite", name), file.Data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("success run Create", func(t *testing.T) {
+		res, err := Create(dir, "Add note Owner")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(dir, "mysql", "0011_add_note_owner.up.sql"),
+			filepath.Join(dir, "mysql", "0011_add_note_owner.down.sql"),
+			filepath.Join(dir, "sqlite", "0011_add_note_owner.up.sql"),
+			filepath.Join(dir, "sqlite", "0011_add_note_owner.down.sql"),
+		}, res)
+
+		migrations, err := Load(os.DirFS(filepath.Join(dir, "mysql")))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(migrations))
+	})
+
+	t.Run("fail run Create", func(t *testing.T) {
+		_, err := Create(dir, "add-owner")
+		assert.True(t, errors.Is(err, ErrInvalidName))
+	})
+}
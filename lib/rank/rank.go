@@ -0,0 +1,229 @@
+// Package rank generates fractional ordering keys: strings that sort in the
+// wanted order and between which a new key can always be made, so moving an
+// item only rewrites that item.
+//
+// A key is an integer part followed by an optional fraction. The first
+// character of the integer part gives its length, n..z for positive integers
+// growing in length and m..a for negative ones, so appending at either end
+// keeps keys short. Only 0-9 and a-z are used, the keys sort the same way
+// under binary and case insensitive collations.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const (
+	zero     = "n0"
+	smallest = "a0000000000000"
+)
+
+var (
+	ErrInvalidKey = errors.New("invalid rank key")
+	ErrOrder      = errors.New("rank keys out of order")
+	ErrExhausted  = errors.New("rank keys exhausted")
+)
+
+// Between returns a key sorting after a and before b, an empty a is the start
+// and an empty b the end of the list
+func Between(a string, b string) (string, error) {
+	if a != "" {
+		if err := validate(a); err != nil {
+			return "", err
+		}
+	}
+	if b != "" {
+		if err := validate(b); err != nil {
+			return "", err
+		}
+	}
+	if a != "" && b != "" && a >= b {
+		return "", ErrOrder
+	}
+
+	if a == "" {
+		if b == "" {
+			return zero, nil
+		}
+		ib, _ := integerPart(b)
+		if ib == smallest {
+			return ib + midpoint("", b[len(ib):]), nil
+		}
+		if ib < b {
+			return ib, nil
+		}
+		return decrement(ib)
+	}
+
+	ia, _ := integerPart(a)
+	fa := a[len(ia):]
+	if b == "" {
+		next, err := increment(ia)
+		if err != nil {
+			return ia + midpoint(fa, ""), nil
+		}
+		return next, nil
+	}
+
+	ib, _ := integerPart(b)
+	if ia == ib {
+		return ia + midpoint(fa, b[len(ib):]), nil
+	}
+	next, err := increment(ia)
+	if err == nil && next < b {
+		return next, nil
+	}
+	return ia + midpoint(fa, ""), nil
+}
+
+// Sequence returns n keys in order, for ranking existing rows in one go
+func Sequence(n int) []string {
+	keys := make([]string, 0, n)
+	last := ""
+	for i := 0; i < n; i++ {
+		last, _ = Between(last, "")
+		keys = append(keys, last)
+	}
+	return keys
+}
+
+// midpoint is a fraction between a and b, b empty meaning no upper bound.
+// Fractions never end with 0, so there is always room before them
+func midpoint(a string, b string) string {
+	if b != "" {
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	da := 0
+	if a != "" {
+		da = strings.IndexByte(digits, a[0])
+	}
+	db := len(digits)
+	if b != "" {
+		db = strings.IndexByte(digits, b[0])
+	}
+	if db-da > 1 {
+		return string(digits[(da+db+1)/2])
+	}
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(digits[da]) + midpoint(rest, "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return '0'
+}
+
+func integerLength(head byte) (int, error) {
+	switch {
+	case head >= 'n' && head <= 'z':
+		return int(head-'n') + 2, nil
+	case head >= 'a' && head <= 'm':
+		return int('m'-head) + 2, nil
+	}
+	return 0, ErrInvalidKey
+}
+
+func integerPart(key string) (string, error) {
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	length, err := integerLength(key[0])
+	if err != nil || length > len(key) {
+		return "", ErrInvalidKey
+	}
+	return key[:length], nil
+}
+
+func validate(key string) error {
+	if key == smallest {
+		return ErrInvalidKey
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return ErrInvalidKey
+		}
+	}
+	integer, err := integerPart(key)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(key[len(integer):], "0") {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+func increment(integer string) (string, error) {
+	head, body := integer[0], []byte(integer[1:])
+	for i := len(body) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, body[i]) + 1
+		if d < len(digits) {
+			body[i] = digits[d]
+			return string(head) + string(body), nil
+		}
+		body[i] = '0'
+	}
+
+	switch head {
+	case 'm':
+		return zero, nil
+	case 'z':
+		return "", ErrExhausted
+	}
+	head++
+	if head > 'n' {
+		body = append(body, '0')
+	} else {
+		body = body[:len(body)-1]
+	}
+	return string(head) + string(body), nil
+}
+
+func decrement(integer string) (string, error) {
+	last := digits[len(digits)-1]
+	head, body := integer[0], []byte(integer[1:])
+	for i := len(body) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, body[i]) - 1
+		if d >= 0 {
+			body[i] = digits[d]
+			return string(head) + string(body), nil
+		}
+		body[i] = last
+	}
+
+	switch head {
+	case 'n':
+		return "m" + string(last), nil
+	case 'a':
+		return "", ErrExhausted
+	}
+	head--
+	if head < 'm' {
+		body = append(body, last)
+	} else {
+		body = body[:len(body)-1]
+	}
+	return string(head) + string(body), nil
+}
@@ -0,0 +1,100 @@
+package rank
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBetween(t *testing.T) {
+	t.Run("success run Between", func(t *testing.T) {
+		for _, tc := range []struct{ a, b, key string }{
+			{"", "", "n0"},
+			{"n0", "", "n1"},
+			{"", "n0", "mz"},
+			{"nz", "", "o00"},
+			{"mz", "", "n0"},
+			{"", "m0", "lzz"},
+			{"n0", "n1", "n0i"},
+			{"n0i", "n1", "n0r"},
+			{"n0", "n0i", "n09"},
+			{"n0z", "n1", "n0zi"},
+			{"n1", "n2", "n1i"},
+			{"", "n0i", "n0"},
+		} {
+			key, err := Between(tc.a, tc.b)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.key, key, tc.a+" "+tc.b)
+		}
+	})
+
+	t.Run("fail run Between", func(t *testing.T) {
+		for _, tc := range []struct {
+			a, b string
+			err  error
+		}{
+			{"n1", "n0", ErrOrder},
+			{"n0", "n0", ErrOrder},
+			{"n0i0", "", ErrInvalidKey},
+			{"N0", "", ErrInvalidKey},
+			{"", "0", ErrInvalidKey},
+			{"o0", "", ErrInvalidKey},
+			{smallest, "", ErrInvalidKey},
+		} {
+			_, err := Between(tc.a, tc.b)
+			assert.Equal(t, tc.err, err, tc.a+" "+tc.b)
+		}
+	})
+
+	t.Run("success run Between keeps the order", func(t *testing.T) {
+		random := rand.New(rand.NewSource(1))
+		keys := []string{}
+		for i := 0; i < 2000; i++ {
+			at := random.Intn(len(keys) + 1)
+			a, b := "", ""
+			if at > 0 {
+				a = keys[at-1]
+			}
+			if at < len(keys) {
+				b = keys[at]
+			}
+			key, err := Between(a, b)
+			if !assert.Nil(t, err, a+" "+b) {
+				return
+			}
+			assert.True(t, (a == "" || a < key) && (b == "" || key < b), a+" "+key+" "+b)
+			keys = append(keys[:at], append([]string{key}, keys[at:]...)...)
+		}
+		assert.True(t, sort.StringsAreSorted(keys))
+	})
+
+	t.Run("success run Between case insensitive", func(t *testing.T) {
+		for _, key := range Sequence(500) {
+			assert.Equal(t, strings.ToLower(key), key)
+		}
+	})
+}
+
+func TestSequence(t *testing.T) {
+	keys := Sequence(5000)
+	assert.Equal(t, 5000, len(keys))
+	assert.True(t, sort.StringsAreSorted(keys))
+	assert.True(t, len(keys[len(keys)-1]) <= 4)
+
+	// appending always at the end or the start keeps keys short
+	first := keys[0]
+	for i := 0; i < 5000; i++ {
+		first, _ = Between("", first)
+	}
+	assert.True(t, len(first) <= 4)
+
+	// repeatedly inserting at the same spot grows by about one character per five moves
+	a, b := keys[0], keys[1]
+	for i := 0; i < 50; i++ {
+		b, _ = Between(a, b)
+	}
+	assert.True(t, len(b) < 20)
+}
@@ -0,0 +1,297 @@
+package recurrence
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Rule is a small subset of the iCalendar RRULE, written the same way:
+//
+//	FREQ=DAILY;INTERVAL=2
+//	FREQ=WEEKLY;BYDAY=MO,TH
+//	FREQ=MONTHLY;BYMONTHDAY=-1;UNTIL=20231231
+//	FREQ=MONTHLY;DTSTART=20230131
+//	FREQ=DAILY;INTERVAL=3;FROM=COMPLETION
+//
+// FROM=COMPLETION counts the interval from the day the previous occurrence was
+// completed instead of following the calendar, BYMONTHDAY=-1 is the last day of the month.
+// DTSTART is the day of the first occurrence, a monthly rule without BYMONTHDAY
+// comes back to its day of month after a shorter month
+type Rule struct {
+	Freq            string
+	Interval        int
+	ByDay           []time.Weekday
+	ByMonthDay      int
+	Until           string
+	Start           string
+	AfterCompletion bool
+}
+
+func Parse(value string) (Rule, error) {
+	rule := Rule{Interval: 1}
+
+	for _, part := range strings.Split(strings.ToUpper(strings.TrimSpace(value)), ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return Rule{}, ErrInvalidRule
+		}
+		switch key, val := kv[0], kv[1]; key {
+		case "FREQ":
+			if val != FreqDaily && val != FreqWeekly && val != FreqMonthly {
+				return Rule{}, ErrInvalidRule
+			}
+			rule.Freq = val
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 || interval > 366 {
+				return Rule{}, ErrInvalidRule
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			seen := map[time.Weekday]bool{}
+			for _, name := range strings.Split(val, ",") {
+				day, ok := weekdays[name]
+				if !ok {
+					return Rule{}, ErrInvalidRule
+				}
+				if !seen[day] {
+					seen[day] = true
+					rule.ByDay = append(rule.ByDay, day)
+				}
+			}
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(val)
+			if err != nil || day == 0 || day < -1 || day > 31 {
+				return Rule{}, ErrInvalidRule
+			}
+			rule.ByMonthDay = day
+		case "UNTIL":
+			if _, err := time.Parse("20060102", val); err != nil {
+				return Rule{}, ErrInvalidRule
+			}
+			rule.Until = val
+		case "DTSTART":
+			if _, err := time.Parse("20060102", val); err != nil {
+				return Rule{}, ErrInvalidRule
+			}
+			rule.Start = val
+		case "FROM":
+			if val != "COMPLETION" {
+				return Rule{}, ErrInvalidRule
+			}
+			rule.AfterCompletion = true
+		default:
+			return Rule{}, ErrInvalidRule
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return Rule{}, ErrInvalidRule
+	case len(rule.ByDay) > 0 && (rule.Freq != FreqWeekly || rule.AfterCompletion):
+		return Rule{}, ErrInvalidRule
+	case rule.ByMonthDay != 0 && (rule.Freq != FreqMonthly || rule.AfterCompletion):
+		return Rule{}, ErrInvalidRule
+	}
+	return rule, nil
+}
+
+// String writes the rule back in a canonical form, so equal rules are stored the same way
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		names := []string{}
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			for _, by := range r.ByDay {
+				if by == day {
+					names = append(names, strings.ToUpper(day.String()[:2]))
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(names, ","))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if r.Until != "" {
+		parts = append(parts, "UNTIL="+r.Until)
+	}
+	if r.Start != "" {
+		parts = append(parts, "DTSTART="+r.Start)
+	}
+	if r.AfterCompletion {
+		parts = append(parts, "FROM=COMPLETION")
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next is the first occurrence after anchor. The calculation is done on the
+// wall clock of loc, so a task due at 09:00 stays at 09:00 across DST changes
+func (r Rule) Next(anchor time.Time, loc *time.Location) time.Time {
+	local := anchor.In(loc)
+	y, m, d := local.Date()
+
+	switch r.Freq {
+	case FreqDaily:
+		return wallClock(local, y, m, d+r.Interval, loc)
+	case FreqWeekly:
+		if len(r.ByDay) == 0 {
+			return wallClock(local, y, m, d+7*r.Interval, loc)
+		}
+		// weeks start on monday, only every interval-th week from the anchor's counts
+		weekStart := d - (int(local.Weekday())+6)%7
+		for offset := 1; offset <= 7*r.Interval+7; offset++ {
+			week := (d + offset - weekStart) / 7
+			day := time.Date(y, m, d+offset, 0, 0, 0, 0, time.UTC).Weekday()
+			if week%r.Interval == 0 && r.hasDay(day) {
+				return wallClock(local, y, m, d+offset, loc)
+			}
+		}
+	case FreqMonthly:
+		if r.AfterCompletion {
+			return wallClock(local, y, m+time.Month(r.Interval), clampDay(y, m+time.Month(r.Interval), d), loc)
+		}
+		if r.ByMonthDay == 0 {
+			// clamped from the first day, not from the last occurrence
+			day := d
+			if start, err := time.Parse("20060102", r.Start); err == nil {
+				day = start.Day()
+			}
+			return wallClock(local, y, m+time.Month(r.Interval), clampDay(y, m+time.Month(r.Interval), day), loc)
+		}
+		for months := 0; ; months += r.Interval {
+			day := r.ByMonthDay
+			if day == -1 {
+				day = 31
+			}
+			day = clampDay(y, m+time.Month(months), day)
+			if months > 0 || day > d {
+				return wallClock(local, y, m+time.Month(months), day, loc)
+			}
+		}
+	}
+	return anchor
+}
+
+// Anchored returns the rule with the day of first as DTSTART when it is a
+// monthly rule that follows the day of month of its first occurrence
+func (r Rule) Anchored(first time.Time, loc *time.Location) Rule {
+	if r.Freq == FreqMonthly && r.ByMonthDay == 0 && !r.AfterCompletion {
+		r.Start = first.In(loc).Format("20060102")
+	}
+	return r
+}
+
+// Ended reports whether the occurrence falls after the UNTIL day, read in loc
+func (r Rule) Ended(occurrence time.Time, loc *time.Location) bool {
+	if r.Until == "" {
+		return false
+	}
+	until, _ := time.ParseInLocation("20060102", r.Until, loc)
+	return !occurrence.Before(until.AddDate(0, 0, 1))
+}
+
+func (r Rule) hasDay(day time.Weekday) bool {
+	for _, by := range r.ByDay {
+		if by == day {
+			return true
+		}
+	}
+	return false
+}
+
+// clampDay keeps the day inside the month, the 31st becomes the 30th in april
+func clampDay(year int, month time.Month, day int) int {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		return last
+	}
+	return day
+}
+
+// wallClock is the given day at the time of day of local. A time skipped when
+// the clocks go forward does not exist, it moves forward by the length of the gap
+func wallClock(local time.Time, year int, month time.Month, day int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), loc)
+
+	want := time.Date(year, month, day, local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC)
+	got := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	if gap := want.Sub(got); gap > 0 {
+		return t.Add(gap)
+	}
+	return t
+}
+
+// Roll gives the dates of the occurrence after the one completed at completed.
+// The due date follows the rule, or the start date when there is no due date,
+// and the other date keeps its distance in calendar days. An occurrence that
+// would still be in the past is skipped, so a late completion does not leave
+// a backlog of missed instances. ok is false once the rule has ended
+func (r Rule) Roll(start *time.Time, due *time.Time, completed time.Time, loc *time.Location) (*time.Time, *time.Time, bool) {
+	base := due
+	if base == nil {
+		base = start
+	}
+	if base == nil {
+		return nil, nil, !r.Ended(completed, loc)
+	}
+
+	var next time.Time
+	if r.AfterCompletion {
+		// the completion day with the time of day of the previous occurrence
+		y, m, d := completed.In(loc).Date()
+		next = r.Next(wallClock(base.In(loc), y, m, d, loc), loc)
+	} else {
+		next = r.Next(*base, loc)
+		for i := 0; !next.After(completed) && i < maxSkipped; i++ {
+			next = r.Next(next, loc)
+		}
+	}
+	if r.Ended(next, loc) {
+		return nil, nil, false
+	}
+
+	days := daysBetween(*base, next, loc)
+	return shiftDays(start, days, loc), shiftDays(due, days, loc), true
+}
+
+// maxSkipped bounds how many missed occurrences Roll walks over
+const maxSkipped = 10000
+
+// shiftDays moves t by whole calendar days in loc, keeping its time of day
+func shiftDays(t *time.Time, days int, loc *time.Location) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local := t.In(loc)
+	y, m, d := local.Date()
+	shifted := wallClock(local, y, m, d+days, loc).UTC()
+	return &shifted
+}
+
+// daysBetween counts the calendar days from a to b in loc
+func daysBetween(a time.Time, b time.Time, loc *time.Location) int {
+	ay, am, ad := a.In(loc).Date()
+	by, bm, bd := b.In(loc).Date()
+	from := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	to := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,220 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("success run Parse", func(t *testing.T) {
+		for value, canonical := range map[string]string{
+			"FREQ=DAILY":                            "FREQ=DAILY",
+			"freq=daily;interval=1":                 "FREQ=DAILY",
+			"FREQ=WEEKLY;BYDAY=TH,MO,MO":            "FREQ=WEEKLY;BYDAY=MO,TH",
+			"FREQ=MONTHLY;BYMONTHDAY=-1":            "FREQ=MONTHLY;BYMONTHDAY=-1",
+			"FREQ=DAILY;INTERVAL=3;FROM=COMPLETION": "FREQ=DAILY;INTERVAL=3;FROM=COMPLETION",
+			"UNTIL=20231231;FREQ=WEEKLY;INTERVAL=2": "FREQ=WEEKLY;INTERVAL=2;UNTIL=20231231",
+			"DTSTART=20230131;FREQ=MONTHLY":         "FREQ=MONTHLY;DTSTART=20230131",
+		} {
+			rule, err := Parse(value)
+			assert.Nil(t, err, value)
+			assert.Equal(t, canonical, rule.String())
+		}
+	})
+
+	t.Run("fail run Parse", func(t *testing.T) {
+		for _, value := range []string{
+			"",
+			"FREQ=YEARLY",
+			"INTERVAL=2",
+			"FREQ=DAILY;INTERVAL=0",
+			"FREQ=DAILY;BYDAY=MO",
+			"FREQ=WEEKLY;BYDAY=XX",
+			"FREQ=MONTHLY;BYMONTHDAY=32",
+			"FREQ=MONTHLY;BYMONTHDAY=15;FROM=COMPLETION",
+			"FREQ=DAILY;UNTIL=tomorrow",
+			"FREQ=MONTHLY;DTSTART=20230231",
+			"FREQ=DAILY;COUNT=3",
+		} {
+			_, err := Parse(value)
+			assert.Equal(t, ErrInvalidRule, err, value)
+		}
+	})
+}
+
+func mustParse(t *testing.T, value string) Rule {
+	rule, err := Parse(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rule
+}
+
+func TestNext(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	at := func(y int, m time.Month, d int, h int, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, loc)
+	}
+
+	cases := []struct {
+		name   string
+		rule   string
+		anchor time.Time
+		next   time.Time
+	}{
+		{"daily", "FREQ=DAILY", at(2023, 5, 10, 9, 0), at(2023, 5, 11, 9, 0)},
+		{"every other day across month", "FREQ=DAILY;INTERVAL=2", at(2023, 5, 31, 9, 0), at(2023, 6, 2, 9, 0)},
+		{"weekly same weekday", "FREQ=WEEKLY", at(2023, 5, 10, 9, 0), at(2023, 5, 17, 9, 0)},
+		{"weekly later this week", "FREQ=WEEKLY;BYDAY=MO,FR", at(2023, 5, 10, 9, 0), at(2023, 5, 12, 9, 0)},
+		{"weekly wraps to next week", "FREQ=WEEKLY;BYDAY=MO,FR", at(2023, 5, 12, 9, 0), at(2023, 5, 15, 9, 0)},
+		{"fortnightly skips a week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", at(2023, 5, 12, 9, 0), at(2023, 5, 22, 9, 0)},
+		{"fortnightly from sunday", "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU", at(2023, 5, 14, 9, 0), at(2023, 5, 28, 9, 0)},
+		{"monthly later this month", "FREQ=MONTHLY;BYMONTHDAY=15", at(2023, 1, 10, 9, 0), at(2023, 1, 15, 9, 0)},
+		{"monthly next month", "FREQ=MONTHLY;BYMONTHDAY=15", at(2023, 1, 15, 9, 0), at(2023, 2, 15, 9, 0)},
+		{"monthly clamps short month", "FREQ=MONTHLY;BYMONTHDAY=31", at(2023, 1, 31, 9, 0), at(2023, 2, 28, 9, 0)},
+		{"monthly last day", "FREQ=MONTHLY;BYMONTHDAY=-1", at(2024, 1, 31, 9, 0), at(2024, 2, 29, 9, 0)},
+		{"monthly across year", "FREQ=MONTHLY;INTERVAL=3", at(2023, 11, 5, 9, 0), at(2024, 2, 5, 9, 0)},
+		{"monthly clamps from the first day", "FREQ=MONTHLY;DTSTART=20230131", at(2023, 1, 31, 9, 0), at(2023, 2, 28, 9, 0)},
+		{"monthly back to the first day", "FREQ=MONTHLY;DTSTART=20230131", at(2023, 2, 28, 9, 0), at(2023, 3, 31, 9, 0)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			next := mustParse(t, tc.rule).Next(tc.anchor, loc)
+			assert.True(t, tc.next.Equal(next), next.String())
+		})
+	}
+}
+
+func TestNextDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+
+	t.Run("daily keeps the wall clock when clocks go forward", func(t *testing.T) {
+		// 2023-03-12 02:00 EST jumps to 03:00 EDT
+		anchor := time.Date(2023, 3, 11, 9, 0, 0, 0, loc)
+		next := mustParse(t, "FREQ=DAILY").Next(anchor, loc)
+		assert.Equal(t, "2023-03-12 09:00", next.In(loc).Format("2006-01-02 15:04"))
+		assert.Equal(t, 23*time.Hour, next.Sub(anchor))
+	})
+
+	t.Run("daily keeps the wall clock when clocks go back", func(t *testing.T) {
+		// 2023-11-05 02:00 EDT falls back to 01:00 EST
+		anchor := time.Date(2023, 11, 4, 9, 0, 0, 0, loc)
+		next := mustParse(t, "FREQ=DAILY").Next(anchor, loc)
+		assert.Equal(t, "2023-11-05 09:00", next.In(loc).Format("2006-01-02 15:04"))
+		assert.Equal(t, 25*time.Hour, next.Sub(anchor))
+	})
+
+	t.Run("weekly across the change", func(t *testing.T) {
+		anchor := time.Date(2023, 3, 6, 17, 30, 0, 0, loc)
+		next := mustParse(t, "FREQ=WEEKLY;BYDAY=MO").Next(anchor, loc)
+		assert.Equal(t, "2023-03-13 17:30 EDT", next.In(loc).Format("2006-01-02 15:04 MST"))
+	})
+
+	t.Run("time skipped by the change moves forward", func(t *testing.T) {
+		anchor := time.Date(2023, 3, 11, 2, 30, 0, 0, loc)
+		next := mustParse(t, "FREQ=DAILY").Next(anchor, loc)
+		assert.Equal(t, "2023-03-12 03:30 EDT", next.In(loc).Format("2006-01-02 15:04 MST"))
+		// the moved time is what the next occurrence builds on
+		assert.Equal(t, "2023-03-13 03:30 EDT", mustParse(t, "FREQ=DAILY").Next(next, loc).In(loc).Format("2006-01-02 15:04 MST"))
+	})
+
+	t.Run("a day in another zone", func(t *testing.T) {
+		// 23:00 in New York is already the next day in UTC
+		anchor := time.Date(2023, 3, 10, 23, 0, 0, 0, loc)
+		next := mustParse(t, "FREQ=WEEKLY;BYDAY=SA").Next(anchor, loc)
+		assert.Equal(t, "2023-03-11 23:00 EST", next.In(loc).Format("2006-01-02 15:04 MST"))
+		next = mustParse(t, "FREQ=WEEKLY;BYDAY=SA").Next(anchor, time.UTC)
+		assert.Equal(t, "2023-03-18 04:00 UTC", next.Format("2006-01-02 15:04 MST"))
+	})
+}
+
+func TestRoll(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	at := func(y int, m time.Month, d int, h int) *time.Time {
+		value := time.Date(y, m, d, h, 0, 0, 0, loc)
+		return &value
+	}
+	format := func(value *time.Time) string {
+		if value == nil {
+			return ""
+		}
+		return value.In(loc).Format("2006-01-02 15:04 MST")
+	}
+
+	t.Run("start keeps its distance across DST", func(t *testing.T) {
+		// 2023-03-26 01:00 GMT jumps to 02:00 BST
+		start, due, ok := mustParse(t, "FREQ=WEEKLY").Roll(at(2023, 3, 20, 9), at(2023, 3, 24, 17), *at(2023, 3, 24, 12), loc)
+		assert.True(t, ok)
+		assert.Equal(t, "2023-03-27 09:00 BST", format(start))
+		assert.Equal(t, "2023-03-31 17:00 BST", format(due))
+		assert.Equal(t, time.UTC, due.Location())
+	})
+
+	t.Run("late completion skips missed occurrences", func(t *testing.T) {
+		_, due, ok := mustParse(t, "FREQ=WEEKLY").Roll(nil, at(2023, 3, 3, 17), *at(2023, 3, 22, 10), loc)
+		assert.True(t, ok)
+		assert.Equal(t, "2023-03-24 17:00 GMT", format(due))
+	})
+
+	t.Run("after completion counts from the completion day", func(t *testing.T) {
+		_, due, ok := mustParse(t, "FREQ=DAILY;INTERVAL=3;FROM=COMPLETION").Roll(nil, at(2023, 3, 10, 8), *at(2023, 3, 25, 23), loc)
+		assert.True(t, ok)
+		assert.Equal(t, "2023-03-28 08:00 BST", format(due))
+	})
+
+	t.Run("start only", func(t *testing.T) {
+		start, due, ok := mustParse(t, "FREQ=DAILY").Roll(at(2023, 10, 28, 9), nil, *at(2023, 10, 28, 8), loc)
+		assert.True(t, ok)
+		assert.Equal(t, "2023-10-29 09:00 GMT", format(start))
+		assert.Nil(t, due)
+	})
+
+	t.Run("no dates", func(t *testing.T) {
+		start, due, ok := mustParse(t, "FREQ=DAILY").Roll(nil, nil, *at(2023, 10, 28, 8), loc)
+		assert.True(t, ok)
+		assert.Nil(t, start)
+		assert.Nil(t, due)
+	})
+
+	t.Run("rule ended", func(t *testing.T) {
+		rule := mustParse(t, "FREQ=DAILY;UNTIL=20231029")
+		_, due, ok := rule.Roll(nil, at(2023, 10, 28, 23), *at(2023, 10, 28, 8), loc)
+		assert.True(t, ok)
+		assert.Equal(t, "2023-10-29 23:00 GMT", format(due))
+
+		_, _, ok = rule.Roll(nil, due, *due, loc)
+		assert.False(t, ok)
+	})
+}
+
+func TestAnchored(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	// 2023-01-31 in Jakarta
+	first := time.Date(2023, 1, 30, 20, 0, 0, 0, time.UTC)
+
+	t.Run("success run Anchored", func(t *testing.T) {
+		assert.Equal(t, "FREQ=MONTHLY;DTSTART=20230131", mustParse(t, "FREQ=MONTHLY").Anchored(first, loc).String())
+		assert.Equal(t, "FREQ=MONTHLY;DTSTART=20230131", mustParse(t, "FREQ=MONTHLY;DTSTART=20221215").Anchored(first, loc).String())
+	})
+
+	t.Run("success run Anchored other rules", func(t *testing.T) {
+		for _, value := range []string{"FREQ=WEEKLY", "FREQ=MONTHLY;BYMONTHDAY=-1", "FREQ=MONTHLY;FROM=COMPLETION"} {
+			assert.Equal(t, value, mustParse(t, value).Anchored(first, loc).String())
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Storage talks to an S3 compatible service with path style urls and
+// signature version 4, the payload is streamed and left unsigned
+type S3Storage struct {
+	client    *http.Client
+	endpoint  string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	now       func() time.Time
+}
+
+func NewS3(endpoint string, region string, bucket string, accessKey string, secretKey string) *S3Storage {
+	return &S3Storage{
+		client:    &http.Client{},
+		endpoint:  strings.TrimRight(endpoint, "/"),
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		now:       time.Now,
+	}
+}
+
+func (ss *S3Storage) request(method string, key string, body io.Reader, size int64) (*http.Request, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	segments := []string{ss.bucket}
+	for _, part := range strings.Split(key, "/") {
+		segments = append(segments, url.PathEscape(part))
+	}
+	req, err := http.NewRequest(method, ss.endpoint+"/"+strings.Join(segments, "/"), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	ss.sign(req)
+	return req, nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func (ss *S3Storage) sign(req *http.Request) {
+	now := ss.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\nx-amz-content-sha256:" + unsignedPayload + "\nx-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := day + "/" + ss.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSHA256([]byte("AWS4"+ss.secretKey), day)
+	key = hmacSHA256(key, ss.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", ss.accessKey, scope, signedHeaders, signature))
+}
+
+func (ss *S3Storage) do(req *http.Request) (*http.Response, error) {
+	res, err := ss.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, ErrNotFound
+	}
+	if res.StatusCode >= 300 {
+		detail, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+		res.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s: %s %s", req.Method, req.URL.Path, res.Status, strings.TrimSpace(string(detail)))
+	}
+	return res, nil
+}
+
+func (ss *S3Storage) Put(key string, content io.Reader, size int64, contentType string) error {
+	req, err := ss.request(http.MethodPut, key, content, size)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := ss.do(req)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (ss *S3Storage) Get(key string) (io.ReadCloser, error) {
+	req, err := ss.request(http.MethodGet, key, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ss.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// Delete succeeds for missing objects, as S3 itself does
+func (ss *S3Storage) Delete(key string) error {
+	req, err := ss.request(http.MethodDelete, key, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	res, err := ss.do(req)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
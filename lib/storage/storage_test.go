@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"part3/configs"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	store := NewLocal(t.TempDir())
+
+	t.Run("success run Put and Get", func(t *testing.T) {
+		err := store.Put("tasks/1/abc", strings.NewReader("hello"), 5, "text/plain")
+		assert.Nil(t, err)
+
+		content, err := store.Get("tasks/1/abc")
+		assert.Nil(t, err)
+		raw, _ := ioutil.ReadAll(content)
+		content.Close()
+		assert.Equal(t, "hello", string(raw))
+	})
+
+	t.Run("fail run Put invalid key", func(t *testing.T) {
+		assert.Equal(t, ErrInvalidKey, store.Put("../escape", strings.NewReader("x"), 1, "text/plain"))
+		assert.Equal(t, ErrInvalidKey, store.Put("/root", strings.NewReader("x"), 1, "text/plain"))
+	})
+
+	t.Run("success run Delete", func(t *testing.T) {
+		assert.Nil(t, store.Delete("tasks/1/abc"))
+		assert.Nil(t, store.Delete("tasks/1/abc"))
+
+		_, err := store.Get("tasks/1/abc")
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+// s3StandIn keeps objects in memory and answers like an S3 bucket would
+type s3StandIn struct {
+	lock    sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") || !strings.Contains(auth, "/us-east-1/s3/aws4_request") || r.Header.Get("X-Amz-Date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		raw, _ := ioutil.ReadAll(r.Body)
+		s.objects[r.URL.Path] = raw
+		s.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		raw, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(raw)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	standIn := &s3StandIn{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	store := NewS3(server.URL, "us-east-1", "bucket", "access", "secret")
+
+	t.Run("success run Put and Get", func(t *testing.T) {
+		err := store.Put("tasks/1/abc", bytes.NewBufferString("hello"), 5, "text/plain")
+		assert.Nil(t, err)
+		assert.Equal(t, "text/plain", standIn.types["/bucket/tasks/1/abc"])
+
+		content, err := store.Get("tasks/1/abc")
+		assert.Nil(t, err)
+		raw, _ := ioutil.ReadAll(content)
+		content.Close()
+		assert.Equal(t, "hello", string(raw))
+	})
+
+	t.Run("success run Delete", func(t *testing.T) {
+		assert.Nil(t, store.Delete("tasks/1/abc"))
+
+		_, err := store.Get("tasks/1/abc")
+		assert.Equal(t, ErrNotFound, err)
+	})
+
+	t.Run("fail run Put wrong credentials", func(t *testing.T) {
+		wrong := NewS3(server.URL, "eu-west-1", "bucket", "access", "secret")
+		assert.NotNil(t, wrong.Put("tasks/1/abc", strings.NewReader("x"), 1, "text/plain"))
+	})
+}
+
+func TestNew(t *testing.T) {
+	config := &configs.AppConfig{}
+
+	config.Storage.Driver = "local"
+	_, err := New(config)
+	assert.NotNil(t, err)
+
+	config.Storage.Path = t.TempDir()
+	res, err := New(config)
+	assert.Nil(t, err)
+	assert.IsType(t, &LocalStorage{}, res)
+
+	config.Storage.Driver = "s3"
+	_, err = New(config)
+	assert.NotNil(t, err)
+
+	config.Storage.S3.Endpoint = "http://localhost:9000"
+	config.Storage.S3.Bucket = "bucket"
+	res, err = New(config)
+	assert.Nil(t, err)
+	assert.IsType(t, &S3Storage{}, res)
+
+	config.Storage.Driver = "floppy"
+	_, err = New(config)
+	assert.NotNil(t, err)
+}
@@ -1,42 +1,53 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"part3/configs"
-	"part3/delivery/controllers/auth"
-	"part3/delivery/controllers/project"
-	"part3/delivery/controllers/task"
-	"part3/delivery/controllers/user"
-	"part3/delivery/routes"
-	_authDb "part3/lib/database/auth"
-	_proDb "part3/lib/database/project"
-	_taskDB "part3/lib/database/task"
-	_userDb "part3/lib/database/user"
-	"part3/utils"
-
-	"github.com/labstack/echo/v4"
 )
 
-func main() {
-	config := configs.GetConfig()
-	db := utils.InitDB(config)
+const usage = `usage: part3 [command]
+
+commands:
+  serve                                       start the API server, the default
+  migrate up | down [steps] | status | create <name>
+  seed [--password p]                         add a demo user with projects and tasks
+  user create --name n --email e [--password p] [--admin]
+  user reset-password --email e [--password p]
+  token issue --email e                       print an access and a refresh token
+
+a password left out is generated and printed`
 
-	userRepo := _userDb.New(db)
-	userController := user.New(userRepo)
-	proRepo := _proDb.New(db)
-	proController := project.NewRepo(proRepo)
-	taskRepo := _taskDB.New(db)
-	taskController := task.New(taskRepo,proRepo)
-	authRepo := _authDb.New(db)
-	authController := auth.New(authRepo)
+var errUsage = errors.New(usage)
 
-	e := echo.New()
+func main() {
+	config := configs.GetConfig()
+	if err := run(config, os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
 
-	routes.UserPath(e, userController, authController)
-	routes.TaskPath(e, taskController)
-	routes.ProjectPath(e, proController)
-	routes.AdminPath(e, userController, authController)
+func run(config *configs.AppConfig, args []string) error {
+	if len(args) == 0 {
+		return serve(config)
+	}
 
-	log.Fatal(e.Start(fmt.Sprintf(":%d", config.Port)))
+	switch args[0] {
+	case "serve":
+		return serve(config)
+	case "migrate":
+		return runMigrate(config, args[1:])
+	case "seed":
+		return runSeed(config, args[1:])
+	case "user":
+		return runUser(config, args[1:])
+	case "token":
+		return runToken(config, args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return nil
+	}
+	return errUsage
 }
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"part3/configs"
+	"part3/lib/migrate"
+	"part3/utils"
+	"strconv"
+	"text/tabwriter"
+)
+
+// migrationsDir is where `migrate create` writes, the files are embedded from
+// there at build time
+const migrationsDir = "migrations"
+
+// runMigrate handles `migrate up|down [steps]|status|create <name>`, it
+// connects without applying the pending migrations first
+func runMigrate(config *configs.AppConfig, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return errUsage
+		}
+		paths, err := migrate.Create(migrationsDir, args[1])
+		for _, path := range paths {
+			fmt.Println("created", path)
+		}
+		return err
+	}
+
+	if args[0] != "up" && args[0] != "down" && args[0] != "status" {
+		return errUsage
+	}
+	db, err := utils.OpenDB(config)
+	if err != nil {
+		return err
+	}
+	migrator, err := utils.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := utils.Migrate(db)
+		for _, migration := range applied {
+			fmt.Println("applied", migration)
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return errUsage
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		for _, migration := range reverted {
+			fmt.Println("reverted", migration)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", ""
+			if status.AppliedAt != nil {
+				state, appliedAt = "applied", status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Changed {
+				state = "changed"
+			}
+			if status.Missing {
+				state = "missing"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		return w.Flush()
+	}
+	return nil
+}
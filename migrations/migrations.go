@@ -0,0 +1,11 @@
+// Package migrations holds the versioned schema changes, one directory per
+// database driver. Every change is a pair of files, NNNN_name.up.sql and
+// NNNN_name.down.sql, made with `migrate create <name>` so the versions of
+// the drivers stay in step. Statements end with a semicolon at the end of a
+// line. An applied file must not be edited, add a new migration instead.
+package migrations
+
+import "embed"
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var Files embed.FS
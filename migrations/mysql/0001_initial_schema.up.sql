@@ -0,0 +1,278 @@
+-- the schema the models had when AutoMigrate stopped running on boot, databases
+-- made by AutoMigrate are marked with this version instead of running it
+
+CREATE TABLE `users` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `name` varchar(100) NOT NULL,
+    `email` varchar(100) NOT NULL UNIQUE,
+    `password` varchar(100) NOT NULL,
+    `role` varchar(20) NOT NULL DEFAULT 'member',
+    PRIMARY KEY (`id`),
+    INDEX idx_users_deleted_at (`deleted_at`),
+    INDEX idx_users_email (`email`)
+);
+
+CREATE TABLE `tasks` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `created_by` bigint unsigned NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `status` varchar(30) NOT NULL DEFAULT 'todo',
+    `status_changed_at` datetime(3) NULL,
+    `completed_at` datetime(3) NULL,
+    `completed_by` bigint unsigned,
+    `start_at` datetime(3) NULL,
+    `due_at` datetime(3) NULL,
+    `priority` bigint NOT NULL,
+    `project_id` bigint unsigned NOT NULL,
+    `parent_id` bigint unsigned,
+    `recurrence` varchar(200) NOT NULL DEFAULT '',
+    `timezone` varchar(64) NOT NULL DEFAULT '',
+    `next_id` bigint unsigned,
+    `section_id` bigint unsigned,
+    `board_rank` varchar(191) NOT NULL DEFAULT '',
+    PRIMARY KEY (`id`),
+    INDEX idx_tasks_created_by (`created_by`),
+    INDEX idx_tasks_deleted_at (`deleted_at`),
+    INDEX idx_tasks_due_at (`due_at`),
+    INDEX idx_tasks_parent_id (`parent_id`),
+    INDEX idx_tasks_priority (`priority`),
+    INDEX idx_tasks_rank (`board_rank`),
+    INDEX idx_tasks_section_id (`section_id`)
+);
+
+CREATE TABLE `projects` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint unsigned,
+    `name` varchar(100) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_projects_deleted_at (`deleted_at`)
+);
+
+CREATE TABLE `sessions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `revoked_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_sessions_deleted_at (`deleted_at`),
+    INDEX idx_sessions_user_id (`user_id`)
+);
+
+CREATE TABLE `refresh_tokens` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `session_id` bigint unsigned NOT NULL,
+    `token_hash` varchar(64) NOT NULL UNIQUE,
+    `expires_at` datetime(3) NOT NULL,
+    `used_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_refresh_tokens_deleted_at (`deleted_at`),
+    INDEX idx_refresh_tokens_session_id (`session_id`)
+);
+
+CREATE TABLE `workflow_statuses` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `project_id` bigint unsigned NOT NULL,
+    `name` varchar(30) NOT NULL,
+    `position` bigint NOT NULL,
+    `is_done` boolean NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_workflow_statuses_deleted_at (`deleted_at`),
+    INDEX idx_workflow_statuses_project_id (`project_id`)
+);
+
+CREATE TABLE `workflow_transitions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `project_id` bigint unsigned NOT NULL,
+    `from_status` varchar(30) NOT NULL,
+    `to_status` varchar(30) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_workflow_transitions_deleted_at (`deleted_at`),
+    INDEX idx_workflow_transitions_project_id (`project_id`)
+);
+
+CREATE TABLE `project_members` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `project_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `role` varchar(20) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_project_members_user_id (`user_id`),
+    UNIQUE INDEX idx_project_member (`project_id`,`user_id`)
+);
+
+CREATE TABLE `project_invitations` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `project_id` bigint unsigned NOT NULL,
+    `email` varchar(100) NOT NULL,
+    `role` varchar(20) NOT NULL,
+    `invited_by` bigint unsigned NOT NULL,
+    `expires_at` datetime(3) NOT NULL,
+    `accepted_at` datetime(3) NULL,
+    `declined_at` datetime(3) NULL,
+    `revoked_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_project_invitations_deleted_at (`deleted_at`),
+    INDEX idx_project_invitations_email (`email`),
+    INDEX idx_project_invitations_project_id (`project_id`)
+);
+
+CREATE TABLE `task_assignees` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `task_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `assigned_by` bigint unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_task_assignees_user_id (`user_id`),
+    UNIQUE INDEX idx_task_assignee (`task_id`,`user_id`)
+);
+
+CREATE TABLE `task_checklist_items` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `task_id` bigint unsigned NOT NULL,
+    `name` varchar(200) NOT NULL,
+    `done` boolean NOT NULL DEFAULT false,
+    `position` bigint NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_task_checklist_items_task_id (`task_id`)
+);
+
+CREATE TABLE `task_attachments` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `task_id` bigint unsigned NOT NULL,
+    `uploaded_by` bigint unsigned NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `content_type` varchar(100) NOT NULL,
+    `size` bigint NOT NULL,
+    `checksum` varchar(64) NOT NULL,
+    `storage_key` varchar(255) NOT NULL,
+    `orphaned_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_task_attachments_orphaned_at (`orphaned_at`),
+    INDEX idx_task_attachments_task_id (`task_id`),
+    UNIQUE INDEX idx_task_attachments_storage_key (`storage_key`)
+);
+
+CREATE TABLE `task_dependencies` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `blocker_id` bigint unsigned NOT NULL,
+    `blocked_id` bigint unsigned NOT NULL,
+    `created_by` bigint unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_task_dependencies_blocked_id (`blocked_id`),
+    UNIQUE INDEX idx_task_dependency (`blocker_id`,`blocked_id`)
+);
+
+CREATE TABLE `comments` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `task_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `body` text NOT NULL,
+    `edited_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_comments_deleted_at (`deleted_at`),
+    INDEX idx_comments_task_id (`task_id`),
+    INDEX idx_comments_user_id (`user_id`)
+);
+
+CREATE TABLE `comment_revisions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `comment_id` bigint unsigned NOT NULL,
+    `body` text NOT NULL,
+    `edited_by` bigint unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_comment_revisions_comment_id (`comment_id`)
+);
+
+CREATE TABLE `comment_mentions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `comment_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_comment_mentions_user_id (`user_id`),
+    UNIQUE INDEX idx_comment_mention (`comment_id`,`user_id`)
+);
+
+CREATE TABLE `notifications` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `kind` varchar(30) NOT NULL,
+    `actor_id` bigint unsigned NOT NULL,
+    `task_id` bigint unsigned NOT NULL,
+    `comment_id` bigint unsigned,
+    `read_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_notifications_user_id (`user_id`)
+);
+
+CREATE TABLE `labels` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `project_id` bigint unsigned NOT NULL,
+    `name` varchar(50) NOT NULL,
+    `colour` varchar(7) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX idx_project_label (`project_id`,`name`)
+);
+
+CREATE TABLE `task_labels` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `task_id` bigint unsigned NOT NULL,
+    `label_id` bigint unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_task_labels_label_id (`label_id`),
+    UNIQUE INDEX idx_task_label (`task_id`,`label_id`)
+);
+
+CREATE TABLE `project_sections` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `project_id` bigint unsigned NOT NULL,
+    `name` varchar(50) NOT NULL,
+    `position` bigint NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX idx_project_sections_project_id (`project_id`)
+);
+
+CREATE FULLTEXT INDEX idx_tasks_name_fulltext ON tasks (name);
+CREATE FULLTEXT INDEX idx_projects_name_fulltext ON projects (name);
+CREATE FULLTEXT INDEX idx_comments_body_fulltext ON comments (body);
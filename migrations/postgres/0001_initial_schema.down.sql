@@ -0,0 +1,20 @@
+DROP TABLE "project_sections";
+DROP TABLE "task_labels";
+DROP TABLE "labels";
+DROP TABLE "notifications";
+DROP TABLE "comment_mentions";
+DROP TABLE "comment_revisions";
+DROP TABLE "comments";
+DROP TABLE "task_dependencies";
+DROP TABLE "task_attachments";
+DROP TABLE "task_checklist_items";
+DROP TABLE "task_assignees";
+DROP TABLE "project_invitations";
+DROP TABLE "project_members";
+DROP TABLE "workflow_transitions";
+DROP TABLE "workflow_statuses";
+DROP TABLE "refresh_tokens";
+DROP TABLE "sessions";
+DROP TABLE "projects";
+DROP TABLE "tasks";
+DROP TABLE "users";
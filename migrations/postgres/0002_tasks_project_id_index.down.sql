@@ -0,0 +1 @@
+DROP INDEX idx_tasks_project_id;
@@ -0,0 +1,30 @@
+-- the orphaned rows the up migration set aside are put back once the
+-- constraints are gone
+
+ALTER TABLE "comments" DROP CONSTRAINT "fk_comments_task";
+ALTER TABLE "task_labels" DROP CONSTRAINT "fk_task_labels_task";
+ALTER TABLE "task_dependencies" DROP CONSTRAINT "fk_task_dependencies_blocked";
+ALTER TABLE "task_dependencies" DROP CONSTRAINT "fk_task_dependencies_blocker";
+ALTER TABLE "task_attachments" DROP CONSTRAINT "fk_task_attachments_task";
+ALTER TABLE "task_checklist_items" DROP CONSTRAINT "fk_task_checklist_items_task";
+ALTER TABLE "task_assignees" DROP CONSTRAINT "fk_task_assignees_task";
+ALTER TABLE "project_members" DROP CONSTRAINT "fk_project_members_user";
+ALTER TABLE "project_members" DROP CONSTRAINT "fk_project_members_project";
+ALTER TABLE "tasks" DROP CONSTRAINT "fk_tasks_project";
+
+INSERT INTO "tasks" SELECT * FROM "orphaned_tasks";
+DROP TABLE "orphaned_tasks";
+INSERT INTO "project_members" SELECT * FROM "orphaned_project_members";
+DROP TABLE "orphaned_project_members";
+INSERT INTO "task_assignees" SELECT * FROM "orphaned_task_assignees";
+DROP TABLE "orphaned_task_assignees";
+INSERT INTO "task_checklist_items" SELECT * FROM "orphaned_task_checklist_items";
+DROP TABLE "orphaned_task_checklist_items";
+INSERT INTO "task_attachments" SELECT * FROM "orphaned_task_attachments";
+DROP TABLE "orphaned_task_attachments";
+INSERT INTO "task_dependencies" SELECT * FROM "orphaned_task_dependencies";
+DROP TABLE "orphaned_task_dependencies";
+INSERT INTO "task_labels" SELECT * FROM "orphaned_task_labels";
+DROP TABLE "orphaned_task_labels";
+INSERT INTO "comments" SELECT * FROM "orphaned_comments";
+DROP TABLE "orphaned_comments";
@@ -0,0 +1,31 @@
+-- the records point at their project, user or task. Rows left pointing at
+-- nothing could not take the constraint, they are moved to orphaned_ tables
+-- for the down migration to put them back
+
+CREATE TABLE "orphaned_tasks" AS SELECT * FROM "tasks" WHERE "project_id" NOT IN (SELECT "id" FROM "projects");
+DELETE FROM "tasks" WHERE "id" IN (SELECT "id" FROM "orphaned_tasks");
+CREATE TABLE "orphaned_project_members" AS SELECT * FROM "project_members" WHERE "project_id" NOT IN (SELECT "id" FROM "projects") OR "user_id" NOT IN (SELECT "id" FROM "users");
+DELETE FROM "project_members" WHERE "id" IN (SELECT "id" FROM "orphaned_project_members");
+CREATE TABLE "orphaned_task_assignees" AS SELECT * FROM "task_assignees" WHERE "task_id" NOT IN (SELECT "id" FROM "tasks");
+DELETE FROM "task_assignees" WHERE "id" IN (SELECT "id" FROM "orphaned_task_assignees");
+CREATE TABLE "orphaned_task_checklist_items" AS SELECT * FROM "task_checklist_items" WHERE "task_id" NOT IN (SELECT "id" FROM "tasks");
+DELETE FROM "task_checklist_items" WHERE "id" IN (SELECT "id" FROM "orphaned_task_checklist_items");
+CREATE TABLE "orphaned_task_attachments" AS SELECT * FROM "task_attachments" WHERE "task_id" NOT IN (SELECT "id" FROM "tasks");
+DELETE FROM "task_attachments" WHERE "id" IN (SELECT "id" FROM "orphaned_task_attachments");
+CREATE TABLE "orphaned_task_dependencies" AS SELECT * FROM "task_dependencies" WHERE "blocker_id" NOT IN (SELECT "id" FROM "tasks") OR "blocked_id" NOT IN (SELECT "id" FROM "tasks");
+DELETE FROM "task_dependencies" WHERE "id" IN (SELECT "id" FROM "orphaned_task_dependencies");
+CREATE TABLE "orphaned_task_labels" AS SELECT * FROM "task_labels" WHERE "task_id" NOT IN (SELECT "id" FROM "tasks");
+DELETE FROM "task_labels" WHERE "id" IN (SELECT "id" FROM "orphaned_task_labels");
+CREATE TABLE "orphaned_comments" AS SELECT * FROM "comments" WHERE "task_id" NOT IN (SELECT "id" FROM "tasks");
+DELETE FROM "comments" WHERE "id" IN (SELECT "id" FROM "orphaned_comments");
+
+ALTER TABLE "tasks" ADD CONSTRAINT "fk_tasks_project" FOREIGN KEY ("project_id") REFERENCES "projects" ("id");
+ALTER TABLE "project_members" ADD CONSTRAINT "fk_project_members_project" FOREIGN KEY ("project_id") REFERENCES "projects" ("id");
+ALTER TABLE "project_members" ADD CONSTRAINT "fk_project_members_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+ALTER TABLE "task_assignees" ADD CONSTRAINT "fk_task_assignees_task" FOREIGN KEY ("task_id") REFERENCES "tasks" ("id");
+ALTER TABLE "task_checklist_items" ADD CONSTRAINT "fk_task_checklist_items_task" FOREIGN KEY ("task_id") REFERENCES "tasks" ("id");
+ALTER TABLE "task_attachments" ADD CONSTRAINT "fk_task_attachments_task" FOREIGN KEY ("task_id") REFERENCES "tasks" ("id");
+ALTER TABLE "task_dependencies" ADD CONSTRAINT "fk_task_dependencies_blocker" FOREIGN KEY ("blocker_id") REFERENCES "tasks" ("id");
+ALTER TABLE "task_dependencies" ADD CONSTRAINT "fk_task_dependencies_blocked" FOREIGN KEY ("blocked_id") REFERENCES "tasks" ("id");
+ALTER TABLE "task_labels" ADD CONSTRAINT "fk_task_labels_task" FOREIGN KEY ("task_id") REFERENCES "tasks" ("id");
+ALTER TABLE "comments" ADD CONSTRAINT "fk_comments_task" FOREIGN KEY ("task_id") REFERENCES "tasks" ("id");
@@ -0,0 +1,274 @@
+-- the schema the models had when AutoMigrate stopped running on boot, databases
+-- made by AutoMigrate are marked with this version instead of running it
+
+CREATE TABLE `users` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` varchar(100) NOT NULL,
+    `email` varchar(100) NOT NULL UNIQUE,
+    `password` varchar(100) NOT NULL,
+    `role` varchar(20) NOT NULL DEFAULT 'member',
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_users_deleted_at` ON `users`(`deleted_at`);
+CREATE INDEX `idx_users_email` ON `users`(`email`);
+
+CREATE TABLE `tasks` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `created_by` integer NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `status` varchar(30) NOT NULL DEFAULT 'todo',
+    `status_changed_at` datetime,
+    `completed_at` datetime,
+    `completed_by` integer,
+    `start_at` datetime,
+    `due_at` datetime,
+    `priority` integer NOT NULL,
+    `project_id` integer NOT NULL,
+    `parent_id` integer,
+    `recurrence` varchar(200) NOT NULL DEFAULT '',
+    `timezone` varchar(64) NOT NULL DEFAULT '',
+    `next_id` integer,
+    `section_id` integer,
+    `board_rank` varchar(191) NOT NULL DEFAULT '',
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_tasks_created_by` ON `tasks`(`created_by`);
+CREATE INDEX `idx_tasks_deleted_at` ON `tasks`(`deleted_at`);
+CREATE INDEX `idx_tasks_due_at` ON `tasks`(`due_at`);
+CREATE INDEX `idx_tasks_parent_id` ON `tasks`(`parent_id`);
+CREATE INDEX `idx_tasks_priority` ON `tasks`(`priority`);
+CREATE INDEX `idx_tasks_rank` ON `tasks`(`board_rank`);
+CREATE INDEX `idx_tasks_section_id` ON `tasks`(`section_id`);
+
+CREATE TABLE `projects` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` integer,
+    `name` varchar(100) NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_projects_deleted_at` ON `projects`(`deleted_at`);
+
+CREATE TABLE `sessions` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `user_id` integer NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `revoked_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_sessions_deleted_at` ON `sessions`(`deleted_at`);
+CREATE INDEX `idx_sessions_user_id` ON `sessions`(`user_id`);
+
+CREATE TABLE `refresh_tokens` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `session_id` integer NOT NULL,
+    `token_hash` varchar(64) NOT NULL UNIQUE,
+    `expires_at` datetime NOT NULL,
+    `used_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_refresh_tokens_deleted_at` ON `refresh_tokens`(`deleted_at`);
+CREATE INDEX `idx_refresh_tokens_session_id` ON `refresh_tokens`(`session_id`);
+
+CREATE TABLE `workflow_statuses` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `project_id` integer NOT NULL,
+    `name` varchar(30) NOT NULL,
+    `position` integer NOT NULL,
+    `is_done` numeric NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_workflow_statuses_deleted_at` ON `workflow_statuses`(`deleted_at`);
+CREATE INDEX `idx_workflow_statuses_project_id` ON `workflow_statuses`(`project_id`);
+
+CREATE TABLE `workflow_transitions` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `project_id` integer NOT NULL,
+    `from_status` varchar(30) NOT NULL,
+    `to_status` varchar(30) NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_workflow_transitions_deleted_at` ON `workflow_transitions`(`deleted_at`);
+CREATE INDEX `idx_workflow_transitions_project_id` ON `workflow_transitions`(`project_id`);
+
+CREATE TABLE `project_members` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `project_id` integer NOT NULL,
+    `user_id` integer NOT NULL,
+    `role` varchar(20) NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_project_members_user_id` ON `project_members`(`user_id`);
+CREATE UNIQUE INDEX `idx_project_member` ON `project_members`(`project_id`,`user_id`);
+
+CREATE TABLE `project_invitations` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `project_id` integer NOT NULL,
+    `email` varchar(100) NOT NULL,
+    `role` varchar(20) NOT NULL,
+    `invited_by` integer NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `accepted_at` datetime,
+    `declined_at` datetime,
+    `revoked_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_project_invitations_deleted_at` ON `project_invitations`(`deleted_at`);
+CREATE INDEX `idx_project_invitations_email` ON `project_invitations`(`email`);
+CREATE INDEX `idx_project_invitations_project_id` ON `project_invitations`(`project_id`);
+
+CREATE TABLE `task_assignees` (
+    `id` integer,
+    `created_at` datetime,
+    `task_id` integer NOT NULL,
+    `user_id` integer NOT NULL,
+    `assigned_by` integer NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_task_assignees_user_id` ON `task_assignees`(`user_id`);
+CREATE UNIQUE INDEX `idx_task_assignee` ON `task_assignees`(`task_id`,`user_id`);
+
+CREATE TABLE `task_checklist_items` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `task_id` integer NOT NULL,
+    `name` varchar(200) NOT NULL,
+    `done` numeric NOT NULL DEFAULT false,
+    `position` integer NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_task_checklist_items_task_id` ON `task_checklist_items`(`task_id`);
+
+CREATE TABLE `task_attachments` (
+    `id` integer,
+    `created_at` datetime,
+    `task_id` integer NOT NULL,
+    `uploaded_by` integer NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `content_type` varchar(100) NOT NULL,
+    `size` integer NOT NULL,
+    `checksum` varchar(64) NOT NULL,
+    `storage_key` varchar(255) NOT NULL,
+    `orphaned_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_task_attachments_orphaned_at` ON `task_attachments`(`orphaned_at`);
+CREATE INDEX `idx_task_attachments_task_id` ON `task_attachments`(`task_id`);
+CREATE UNIQUE INDEX `idx_task_attachments_storage_key` ON `task_attachments`(`storage_key`);
+
+CREATE TABLE `task_dependencies` (
+    `id` integer,
+    `created_at` datetime,
+    `blocker_id` integer NOT NULL,
+    `blocked_id` integer NOT NULL,
+    `created_by` integer NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_task_dependencies_blocked_id` ON `task_dependencies`(`blocked_id`);
+CREATE UNIQUE INDEX `idx_task_dependency` ON `task_dependencies`(`blocker_id`,`blocked_id`);
+
+CREATE TABLE `comments` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `task_id` integer NOT NULL,
+    `user_id` integer NOT NULL,
+    `body` text NOT NULL,
+    `edited_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_comments_deleted_at` ON `comments`(`deleted_at`);
+CREATE INDEX `idx_comments_task_id` ON `comments`(`task_id`);
+CREATE INDEX `idx_comments_user_id` ON `comments`(`user_id`);
+
+CREATE TABLE `comment_revisions` (
+    `id` integer,
+    `created_at` datetime,
+    `comment_id` integer NOT NULL,
+    `body` text NOT NULL,
+    `edited_by` integer NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_comment_revisions_comment_id` ON `comment_revisions`(`comment_id`);
+
+CREATE TABLE `comment_mentions` (
+    `id` integer,
+    `created_at` datetime,
+    `comment_id` integer NOT NULL,
+    `user_id` integer NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_comment_mentions_user_id` ON `comment_mentions`(`user_id`);
+CREATE UNIQUE INDEX `idx_comment_mention` ON `comment_mentions`(`comment_id`,`user_id`);
+
+CREATE TABLE `notifications` (
+    `id` integer,
+    `created_at` datetime,
+    `user_id` integer NOT NULL,
+    `kind` varchar(30) NOT NULL,
+    `actor_id` integer NOT NULL,
+    `task_id` integer NOT NULL,
+    `comment_id` integer,
+    `read_at` datetime,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_notifications_user_id` ON `notifications`(`user_id`);
+
+CREATE TABLE `labels` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `project_id` integer NOT NULL,
+    `name` varchar(50) NOT NULL,
+    `colour` varchar(7) NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE UNIQUE INDEX `idx_project_label` ON `labels`(`project_id`,`name`);
+
+CREATE TABLE `task_labels` (
+    `id` integer,
+    `created_at` datetime,
+    `task_id` integer NOT NULL,
+    `label_id` integer NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_task_labels_label_id` ON `task_labels`(`label_id`);
+CREATE UNIQUE INDEX `idx_task_label` ON `task_labels`(`task_id`,`label_id`);
+
+CREATE TABLE `project_sections` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `project_id` integer NOT NULL,
+    `name` varchar(50) NOT NULL,
+    `position` integer NOT NULL,
+    PRIMARY KEY (`id`)
+);
+CREATE INDEX `idx_project_sections_project_id` ON `project_sections`(`project_id`);
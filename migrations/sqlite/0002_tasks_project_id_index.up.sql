@@ -0,0 +1,2 @@
+-- tasks are read by project on every board, listing and search query
+CREATE INDEX idx_tasks_project_id ON tasks (project_id);
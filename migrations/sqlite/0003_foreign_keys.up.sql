@@ -0,0 +1,175 @@
+-- the records point at their project, user or task. Rows left pointing at
+-- nothing could not take the constraint, they are moved to orphaned_ tables
+-- for the down migration to put them back.
+-- sqlite cannot add a constraint to a table, the tables are made again with it
+
+CREATE TABLE `orphaned_tasks` AS SELECT * FROM `tasks` WHERE `project_id` NOT IN (SELECT `id` FROM `projects`);
+DELETE FROM `tasks` WHERE `id` IN (SELECT `id` FROM `orphaned_tasks`);
+CREATE TABLE `orphaned_project_members` AS SELECT * FROM `project_members` WHERE `project_id` NOT IN (SELECT `id` FROM `projects`) OR `user_id` NOT IN (SELECT `id` FROM `users`);
+DELETE FROM `project_members` WHERE `id` IN (SELECT `id` FROM `orphaned_project_members`);
+CREATE TABLE `orphaned_task_assignees` AS SELECT * FROM `task_assignees` WHERE `task_id` NOT IN (SELECT `id` FROM `tasks`);
+DELETE FROM `task_assignees` WHERE `id` IN (SELECT `id` FROM `orphaned_task_assignees`);
+CREATE TABLE `orphaned_task_checklist_items` AS SELECT * FROM `task_checklist_items` WHERE `task_id` NOT IN (SELECT `id` FROM `tasks`);
+DELETE FROM `task_checklist_items` WHERE `id` IN (SELECT `id` FROM `orphaned_task_checklist_items`);
+CREATE TABLE `orphaned_task_attachments` AS SELECT * FROM `task_attachments` WHERE `task_id` NOT IN (SELECT `id` FROM `tasks`);
+DELETE FROM `task_attachments` WHERE `id` IN (SELECT `id` FROM `orphaned_task_attachments`);
+CREATE TABLE `orphaned_task_dependencies` AS SELECT * FROM `task_dependencies` WHERE `blocker_id` NOT IN (SELECT `id` FROM `tasks`) OR `blocked_id` NOT IN (SELECT `id` FROM `tasks`);
+DELETE FROM `task_dependencies` WHERE `id` IN (SELECT `id` FROM `orphaned_task_dependencies`);
+CREATE TABLE `orphaned_task_labels` AS SELECT * FROM `task_labels` WHERE `task_id` NOT IN (SELECT `id` FROM `tasks`);
+DELETE FROM `task_labels` WHERE `id` IN (SELECT `id` FROM `orphaned_task_labels`);
+CREATE TABLE `orphaned_comments` AS SELECT * FROM `comments` WHERE `task_id` NOT IN (SELECT `id` FROM `tasks`);
+DELETE FROM `comments` WHERE `id` IN (SELECT `id` FROM `orphaned_comments`);
+
+CREATE TABLE `tasks_new` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `created_by` integer NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `status` varchar(30) NOT NULL DEFAULT 'todo',
+    `status_changed_at` datetime,
+    `completed_at` datetime,
+    `completed_by` integer,
+    `start_at` datetime,
+    `due_at` datetime,
+    `priority` integer NOT NULL,
+    `project_id` integer NOT NULL,
+    `parent_id` integer,
+    `recurrence` varchar(200) NOT NULL DEFAULT '',
+    `timezone` varchar(64) NOT NULL DEFAULT '',
+    `next_id` integer,
+    `section_id` integer,
+    `board_rank` varchar(191) NOT NULL DEFAULT '',
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_tasks_project` FOREIGN KEY (`project_id`) REFERENCES `projects`(`id`)
+);
+INSERT INTO `tasks_new` (`id`,`created_at`,`updated_at`,`deleted_at`,`created_by`,`name`,`status`,`status_changed_at`,`completed_at`,`completed_by`,`start_at`,`due_at`,`priority`,`project_id`,`parent_id`,`recurrence`,`timezone`,`next_id`,`section_id`,`board_rank`) SELECT `id`,`created_at`,`updated_at`,`deleted_at`,`created_by`,`name`,`status`,`status_changed_at`,`completed_at`,`completed_by`,`start_at`,`due_at`,`priority`,`project_id`,`parent_id`,`recurrence`,`timezone`,`next_id`,`section_id`,`board_rank` FROM `tasks`;
+DROP TABLE `tasks`;
+ALTER TABLE `tasks_new` RENAME TO `tasks`;
+CREATE INDEX `idx_tasks_created_by` ON `tasks`(`created_by`);
+CREATE INDEX `idx_tasks_deleted_at` ON `tasks`(`deleted_at`);
+CREATE INDEX `idx_tasks_due_at` ON `tasks`(`due_at`);
+CREATE INDEX `idx_tasks_parent_id` ON `tasks`(`parent_id`);
+CREATE INDEX `idx_tasks_priority` ON `tasks`(`priority`);
+CREATE INDEX `idx_tasks_rank` ON `tasks`(`board_rank`);
+CREATE INDEX `idx_tasks_section_id` ON `tasks`(`section_id`);
+CREATE INDEX idx_tasks_project_id ON tasks (project_id);
+
+CREATE TABLE `project_members_new` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `project_id` integer NOT NULL,
+    `user_id` integer NOT NULL,
+    `role` varchar(20) NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_project_members_project` FOREIGN KEY (`project_id`) REFERENCES `projects`(`id`),
+    CONSTRAINT `fk_project_members_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+INSERT INTO `project_members_new` (`id`,`created_at`,`updated_at`,`project_id`,`user_id`,`role`) SELECT `id`,`created_at`,`updated_at`,`project_id`,`user_id`,`role` FROM `project_members`;
+DROP TABLE `project_members`;
+ALTER TABLE `project_members_new` RENAME TO `project_members`;
+CREATE INDEX `idx_project_members_user_id` ON `project_members`(`user_id`);
+CREATE UNIQUE INDEX `idx_project_member` ON `project_members`(`project_id`,`user_id`);
+
+CREATE TABLE `task_assignees_new` (
+    `id` integer,
+    `created_at` datetime,
+    `task_id` integer NOT NULL,
+    `user_id` integer NOT NULL,
+    `assigned_by` integer NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_task_assignees_task` FOREIGN KEY (`task_id`) REFERENCES `tasks`(`id`)
+);
+INSERT INTO `task_assignees_new` (`id`,`created_at`,`task_id`,`user_id`,`assigned_by`) SELECT `id`,`created_at`,`task_id`,`user_id`,`assigned_by` FROM `task_assignees`;
+DROP TABLE `task_assignees`;
+ALTER TABLE `task_assignees_new` RENAME TO `task_assignees`;
+CREATE INDEX `idx_task_assignees_user_id` ON `task_assignees`(`user_id`);
+CREATE UNIQUE INDEX `idx_task_assignee` ON `task_assignees`(`task_id`,`user_id`);
+
+CREATE TABLE `task_checklist_items_new` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `task_id` integer NOT NULL,
+    `name` varchar(200) NOT NULL,
+    `done` numeric NOT NULL DEFAULT false,
+    `position` integer NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_task_checklist_items_task` FOREIGN KEY (`task_id`) REFERENCES `tasks`(`id`)
+);
+INSERT INTO `task_checklist_items_new` (`id`,`created_at`,`updated_at`,`task_id`,`name`,`done`,`position`) SELECT `id`,`created_at`,`updated_at`,`task_id`,`name`,`done`,`position` FROM `task_checklist_items`;
+DROP TABLE `task_checklist_items`;
+ALTER TABLE `task_checklist_items_new` RENAME TO `task_checklist_items`;
+CREATE INDEX `idx_task_checklist_items_task_id` ON `task_checklist_items`(`task_id`);
+
+CREATE TABLE `task_attachments_new` (
+    `id` integer,
+    `created_at` datetime,
+    `task_id` integer NOT NULL,
+    `uploaded_by` integer NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `content_type` varchar(100) NOT NULL,
+    `size` integer NOT NULL,
+    `checksum` varchar(64) NOT NULL,
+    `storage_key` varchar(255) NOT NULL,
+    `orphaned_at` datetime,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_task_attachments_task` FOREIGN KEY (`task_id`) REFERENCES `tasks`(`id`)
+);
+INSERT INTO `task_attachments_new` (`id`,`created_at`,`task_id`,`uploaded_by`,`name`,`content_type`,`size`,`checksum`,`storage_key`,`orphaned_at`) SELECT `id`,`created_at`,`task_id`,`uploaded_by`,`name`,`content_type`,`size`,`checksum`,`storage_key`,`orphaned_at` FROM `task_attachments`;
+DROP TABLE `task_attachments`;
+ALTER TABLE `task_attachments_new` RENAME TO `task_attachments`;
+CREATE INDEX `idx_task_attachments_orphaned_at` ON `task_attachments`(`orphaned_at`);
+CREATE INDEX `idx_task_attachments_task_id` ON `task_attachments`(`task_id`);
+CREATE UNIQUE INDEX `idx_task_attachments_storage_key` ON `task_attachments`(`storage_key`);
+
+CREATE TABLE `task_dependencies_new` (
+    `id` integer,
+    `created_at` datetime,
+    `blocker_id` integer NOT NULL,
+    `blocked_id` integer NOT NULL,
+    `created_by` integer NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_task_dependencies_blocker` FOREIGN KEY (`blocker_id`) REFERENCES `tasks`(`id`),
+    CONSTRAINT `fk_task_dependencies_blocked` FOREIGN KEY (`blocked_id`) REFERENCES `tasks`(`id`)
+);
+INSERT INTO `task_dependencies_new` (`id`,`created_at`,`blocker_id`,`blocked_id`,`created_by`) SELECT `id`,`created_at`,`blocker_id`,`blocked_id`,`created_by` FROM `task_dependencies`;
+DROP TABLE `task_dependencies`;
+ALTER TABLE `task_dependencies_new` RENAME TO `task_dependencies`;
+CREATE INDEX `idx_task_dependencies_blocked_id` ON `task_dependencies`(`blocked_id`);
+CREATE UNIQUE INDEX `idx_task_dependency` ON `task_dependencies`(`blocker_id`,`blocked_id`);
+
+CREATE TABLE `task_labels_new` (
+    `id` integer,
+    `created_at` datetime,
+    `task_id` integer NOT NULL,
+    `label_id` integer NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_task_labels_task` FOREIGN KEY (`task_id`) REFERENCES `tasks`(`id`)
+);
+INSERT INTO `task_labels_new` (`id`,`created_at`,`task_id`,`label_id`) SELECT `id`,`created_at`,`task_id`,`label_id` FROM `task_labels`;
+DROP TABLE `task_labels`;
+ALTER TABLE `task_labels_new` RENAME TO `task_labels`;
+CREATE INDEX `idx_task_labels_label_id` ON `task_labels`(`label_id`);
+CREATE UNIQUE INDEX `idx_task_label` ON `task_labels`(`task_id`,`label_id`);
+
+CREATE TABLE `comments_new` (
+    `id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `task_id` integer NOT NULL,
+    `user_id` integer NOT NULL,
+    `body` text NOT NULL,
+    `edited_at` datetime,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_comments_task` FOREIGN KEY (`task_id`) REFERENCES `tasks`(`id`)
+);
+INSERT INTO `comments_new` (`id`,`created_at`,`updated_at`,`deleted_at`,`task_id`,`user_id`,`body`,`edited_at`) SELECT `id`,`created_at`,`updated_at`,`deleted_at`,`task_id`,`user_id`,`body`,`edited_at` FROM `comments`;
+DROP TABLE `comments`;
+ALTER TABLE `comments_new` RENAME TO `comments`;
+CREATE INDEX `idx_comments_deleted_at` ON `comments`(`deleted_at`);
+CREATE INDEX `idx_comments_task_id` ON `comments`(`task_id`);
+CREATE INDEX `idx_comments_user_id` ON `comments`(`user_id`);
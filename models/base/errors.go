@@ -0,0 +1,121 @@
+package base
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Kinds of domain errors, each is answered with one HTTP status
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict with current state")
+	ErrForbidden  = errors.New("forbidden access")
+	ErrValidation = errors.New("validation failed")
+	ErrTooLarge   = errors.New("request too large")
+	ErrMediaType  = errors.New("unsupported media type")
+)
+
+// Machine readable codes of the responses not made from an Error
+const (
+	CodeBadRequest   = "bad_request"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeValidation   = "validation_failed"
+	CodeInternal     = "internal_error"
+)
+
+// Error is a domain error the repositories return, Code is the machine
+// readable code the API answers with. errors.Is matches it against its kind
+type Error struct {
+	kind    error
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.kind
+}
+
+// Status is the HTTP status of the kind of the error
+func (e *Error) Status() int {
+	switch e.kind {
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrConflict:
+		return http.StatusConflict
+	case ErrForbidden:
+		return http.StatusForbidden
+	case ErrValidation:
+		return http.StatusUnprocessableEntity
+	case ErrTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case ErrMediaType:
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusInternalServerError
+}
+
+func NotFoundError(code string, msg string) *Error {
+	return &Error{kind: ErrNotFound, Code: code, Message: msg}
+}
+
+// NotFoundAs gives missing in place of a record gorm did not find, so the
+// answer names what was missing. Other errors are returned as they are
+func NotFoundAs(err error, missing *Error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return missing
+	}
+	return err
+}
+
+func ConflictError(code string, msg string) *Error {
+	return &Error{kind: ErrConflict, Code: code, Message: msg}
+}
+
+func ForbiddenError(code string, msg string) *Error {
+	return &Error{kind: ErrForbidden, Code: code, Message: msg}
+}
+
+func ValidationError(code string, msg string) *Error {
+	return &Error{kind: ErrValidation, Code: code, Message: msg}
+}
+
+func TooLargeError(code string, msg string) *Error {
+	return &Error{kind: ErrTooLarge, Code: code, Message: msg}
+}
+
+func MediaTypeError(code string, msg string) *Error {
+	return &Error{kind: ErrMediaType, Code: code, Message: msg}
+}
+
+// FieldError is one failing field of a request, Field is its json name
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is a request failing validation, it is answered with every
+// failing field. errors.Is matches it against ErrValidation
+type FieldErrors []FieldError
+
+func (fe FieldErrors) Error() string {
+	msgs := make([]string, len(fe))
+	for i, field := range fe {
+		msgs[i] = field.Message
+	}
+	return strings.Join(msgs, ", ")
+}
+
+func (fe FieldErrors) Unwrap() error {
+	return ErrValidation
+}
@@ -0,0 +1,100 @@
+package base
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var ErrInvalidPage = ValidationError("invalid_page", "invalid page")
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Page is the listing window asked by the client, Cursor wins over Offset when both are sent
+type Page struct {
+	Limit  int
+	Offset int
+	Cursor string
+	Sort   []Sort
+}
+
+type Meta struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+}
+
+// ParsePage reads limit, offset, cursor and sort (e.g. sort=-priority,name) from the query string
+func ParsePage(query url.Values) (Page, error) {
+	page := Page{Limit: DefaultPageLimit, Cursor: query.Get("cursor")}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > MaxPageLimit {
+			return page, ErrInvalidPage
+		}
+		page.Limit = limit
+	}
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return page, ErrInvalidPage
+		}
+		page.Offset = offset
+	}
+	if value := query.Get("sort"); value != "" {
+		for _, field := range strings.Split(value, ",") {
+			sort := Sort{Field: strings.TrimSpace(field)}
+			if strings.HasPrefix(sort.Field, "-") {
+				sort.Field, sort.Desc = sort.Field[1:], true
+			}
+			if sort.Field == "" {
+				return page, ErrInvalidPage
+			}
+			page.Sort = append(page.Sort, sort)
+		}
+	}
+
+	return page, nil
+}
+
+// SetLinks fills Next and Prev from the request url, keeping every other query param as sent
+func (m *Meta) SetLinks(u *url.URL) {
+	link := func(param string, value string) string {
+		query := u.Query()
+		query.Del("cursor")
+		query.Del("offset")
+		query.Set(param, value)
+		next := *u
+		next.RawQuery = query.Encode()
+		return next.RequestURI()
+	}
+
+	if m.NextCursor != "" {
+		m.Next = link("cursor", m.NextCursor)
+	} else if u.Query().Get("cursor") == "" && int64(m.Offset+m.Limit) < m.Total {
+		m.Next = link("offset", strconv.Itoa(m.Offset+m.Limit))
+	}
+
+	if m.PrevCursor != "" {
+		m.Prev = link("cursor", m.PrevCursor)
+	} else if u.Query().Get("cursor") == "" && m.Offset > 0 {
+		prev := m.Offset - m.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		m.Prev = link("offset", strconv.Itoa(prev))
+	}
+}
@@ -2,10 +2,14 @@ package base
 
 import "net/http"
 
+// Response is the envelope of every answer, Error is the machine readable code
+// of a failed request
 type Response struct {
 	Code    interface{} `json:"code"`
 	Message interface{} `json:"message"`
+	Error   string      `json:"error,omitempty"`
 	Data    interface{} `json:"data"`
+	Meta    *Meta       `json:"meta,omitempty"`
 }
 
 func Success(code interface{}, msg interface{}, data interface{}) Response {
@@ -25,6 +29,12 @@ func Success(code interface{}, msg interface{}, data interface{}) Response {
 	}
 }
 
+func SuccessPage(code interface{}, msg interface{}, data interface{}, meta Meta) Response {
+	res := Success(code, msg, data)
+	res.Meta = &meta
+	return res
+}
+
 func InternalServerError(code interface{}, msg interface{}, data interface{}) Response {
 	if code == nil {
 		code = http.StatusInternalServerError
@@ -38,6 +48,7 @@ func InternalServerError(code interface{}, msg interface{}, data interface{}) Re
 	return Response{
 		Code:    code,
 		Message: msg,
+		Error:   CodeInternal,
 		Data:    data,
 	}
 }
@@ -55,6 +66,97 @@ func BadRequest(code interface{}, msg interface{}, data interface{}) Response {
 	return Response{
 		Code:    code,
 		Message: msg,
+		Error:   CodeBadRequest,
+		Data:    data,
+	}
+}
+
+func Unauthorized(code interface{}, msg interface{}, data interface{}) Response {
+	if code == nil {
+		code = http.StatusUnauthorized
+	}
+	if msg == nil {
+		msg = "unauthorized"
+	}
+	if data == nil {
+		data = nil
+	}
+	return Response{
+		Code:    code,
+		Message: msg,
+		Error:   CodeUnauthorized,
+		Data:    data,
+	}
+}
+
+func Forbidden(code interface{}, msg interface{}, data interface{}) Response {
+	if code == nil {
+		code = http.StatusForbidden
+	}
+	if msg == nil {
+		msg = "forbidden access"
+	}
+	if data == nil {
+		data = nil
+	}
+	return Response{
+		Code:    code,
+		Message: msg,
+		Error:   CodeForbidden,
+		Data:    data,
+	}
+}
+
+func NotFound(code interface{}, msg interface{}, data interface{}) Response {
+	if code == nil {
+		code = http.StatusNotFound
+	}
+	if msg == nil {
+		msg = "not found"
+	}
+	if data == nil {
+		data = nil
+	}
+	return Response{
+		Code:    code,
+		Message: msg,
+		Error:   CodeNotFound,
+		Data:    data,
+	}
+}
+
+func Conflict(code interface{}, msg interface{}, data interface{}) Response {
+	if code == nil {
+		code = http.StatusConflict
+	}
+	if msg == nil {
+		msg = "conflict with current state"
+	}
+	if data == nil {
+		data = nil
+	}
+	return Response{
+		Code:    code,
+		Message: msg,
+		Error:   CodeConflict,
+		Data:    data,
+	}
+}
+
+func UnprocessableEntity(code interface{}, msg interface{}, data interface{}) Response {
+	if code == nil {
+		code = http.StatusUnprocessableEntity
+	}
+	if msg == nil {
+		msg = "validation failed"
+	}
+	if data == nil {
+		data = nil
+	}
+	return Response{
+		Code:    code,
+		Message: msg,
+		Error:   CodeValidation,
 		Data:    data,
 	}
 }
@@ -0,0 +1,35 @@
+package board
+
+import (
+	"part3/models/board/response"
+	"time"
+)
+
+// a board groups the tasks of a project by workflow status or by section
+const (
+	ByStatus  = "status"
+	BySection = "section"
+)
+
+// Section is a custom board column of a project, a task sits in at most one
+type Section struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Project_ID uint   `gorm:"not null;index"`
+	Name       string `gorm:"not null;type:varchar(50)"`
+	Position   int    `gorm:"not null"`
+}
+
+func (Section) TableName() string {
+	return "project_sections"
+}
+
+func (s *Section) ToSectionResponse() response.SectionResponse {
+	return response.SectionResponse{
+		ID:       s.ID,
+		Name:     s.Name,
+		Position: s.Position,
+	}
+}
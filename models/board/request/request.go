@@ -0,0 +1,19 @@
+package request
+
+import (
+	"part3/models/board"
+	"strings"
+)
+
+type SectionRequest struct {
+	Name     string `json:"name" validate:"max=50"`
+	Position *int   `json:"position" validate:"omitempty,min=0"`
+}
+
+func (sr *SectionRequest) ToSection() board.Section {
+	section := board.Section{Name: strings.TrimSpace(sr.Name)}
+	if sr.Position != nil {
+		section.Position = *sr.Position
+	}
+	return section
+}
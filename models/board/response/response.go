@@ -0,0 +1,23 @@
+package response
+
+import "part3/models/task/response"
+
+type SectionResponse struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+// ColumnResponse is a status or a section with its tasks in board order,
+// tasks outside any section are in a column without section_id
+type ColumnResponse struct {
+	Key        string                  `json:"key"`
+	Name       string                  `json:"name"`
+	Section_id *uint                   `json:"section_id"`
+	Tasks      []response.TaskResponse `json:"tasks"`
+}
+
+type BoardResponse struct {
+	By      string           `json:"by"`
+	Columns []ColumnResponse `json:"columns"`
+}
@@ -0,0 +1,53 @@
+package comment
+
+import (
+	"part3/models/comment/response"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Comment struct {
+	gorm.Model
+
+	Task_ID  uint   `gorm:"not null;index"`
+	User_ID  uint   `gorm:"not null;index"`
+	Body     string `gorm:"not null;type:text"`
+	EditedAt *time.Time
+}
+
+// Revision keeps the body a comment had before one of its edits
+type Revision struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	Comment_ID uint   `gorm:"not null;index"`
+	Body       string `gorm:"not null;type:text"`
+	EditedBy   uint   `gorm:"not null"`
+}
+
+func (Revision) TableName() string {
+	return "comment_revisions"
+}
+
+// Mention records a project member named in a comment
+type Mention struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	Comment_ID uint `gorm:"not null;uniqueIndex:idx_comment_mention"`
+	User_ID    uint `gorm:"not null;uniqueIndex:idx_comment_mention;index"`
+}
+
+func (Mention) TableName() string {
+	return "comment_mentions"
+}
+
+func (r *Revision) ToRevisionResponse() response.RevisionResponse {
+	return response.RevisionResponse{
+		Id:         r.ID,
+		Created_at: r.CreatedAt,
+		Body:       r.Body,
+		Edited_by:  r.EditedBy,
+	}
+}
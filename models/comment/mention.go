@@ -0,0 +1,35 @@
+package comment
+
+import (
+	"regexp"
+	"strings"
+)
+
+// a handle is an email, its local part, or the user's name written without spaces
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([\w.+-]+(?:@[\w-]+(?:\.[\w-]+)*)?)`)
+
+// Mentions lists the lowercased handles written as @handle in the body, each once
+func Mentions(body string) []string {
+	handles := []string{}
+	seen := map[string]bool{}
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		handle := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+	return handles
+}
+
+// Handles lists every handle that mentions the user
+func Handles(name string, email string) []string {
+	email = strings.ToLower(email)
+	handles := []string{email, strings.ReplaceAll(strings.ToLower(name), " ", "")}
+	if at := strings.Index(email, "@"); at > 0 {
+		handles = append(handles, email[:at])
+	}
+	return handles
+}
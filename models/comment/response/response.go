@@ -0,0 +1,28 @@
+package response
+
+import "time"
+
+type CommentResponse struct {
+	Id         uint       `json:"id"`
+	Created_at time.Time  `json:"created_at"`
+	Updated_at time.Time  `json:"updated_at"`
+	Edited_at  *time.Time `json:"edited_at"`
+	Task_id    uint       `json:"task_id"`
+	User_id    uint       `json:"user_id"`
+	User_name  string     `json:"user_name"`
+	Body       string     `json:"body"`
+
+	Mentions []MentionResponse `json:"mentions" gorm:"-"`
+}
+
+type MentionResponse struct {
+	User_id uint   `json:"user_id"`
+	Name    string `json:"name"`
+}
+
+type RevisionResponse struct {
+	Id         uint      `json:"id"`
+	Created_at time.Time `json:"created_at"`
+	Body       string    `json:"body"`
+	Edited_by  uint      `json:"edited_by"`
+}
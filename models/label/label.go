@@ -0,0 +1,48 @@
+package label
+
+import (
+	"part3/models/label/response"
+	"regexp"
+	"time"
+)
+
+const DefaultColour = "#808080"
+
+var colourPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// IsValidColour accepts #rrggbb hex colours
+func IsValidColour(colour string) bool {
+	return colourPattern.MatchString(colour)
+}
+
+// Label belongs to one project, its name is unique there whatever the case
+type Label struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Project_ID uint   `gorm:"not null;uniqueIndex:idx_project_label"`
+	Name       string `gorm:"not null;type:varchar(50);uniqueIndex:idx_project_label"`
+	Colour     string `gorm:"not null;type:varchar(7)"`
+}
+
+// TaskLabel puts a label on a task
+type TaskLabel struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	Task_ID  uint `gorm:"not null;uniqueIndex:idx_task_label"`
+	Label_ID uint `gorm:"not null;uniqueIndex:idx_task_label;index"`
+}
+
+func (TaskLabel) TableName() string {
+	return "task_labels"
+}
+
+func (l *Label) ToLabelResponse() response.LabelResponse {
+	return response.LabelResponse{
+		ID:     l.ID,
+		Name:   l.Name,
+		Colour: l.Colour,
+	}
+}
@@ -0,0 +1,22 @@
+package request
+
+import (
+	"part3/models/label"
+	"strings"
+)
+
+type LabelRequest struct {
+	Name   string `json:"name" validate:"max=50"`
+	Colour string `json:"colour"`
+}
+
+func (lr *LabelRequest) ToLabel() label.Label {
+	return label.Label{
+		Name:   strings.TrimSpace(lr.Name),
+		Colour: strings.ToLower(lr.Colour),
+	}
+}
+
+type TaskLabelRequest struct {
+	Label_ids []uint `json:"label_ids" validate:"required,min=1"`
+}
@@ -0,0 +1,7 @@
+package response
+
+type LabelResponse struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Colour string `json:"colour"`
+}
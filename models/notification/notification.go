@@ -0,0 +1,33 @@
+package notification
+
+import (
+	"part3/models/notification/response"
+	"time"
+)
+
+const KindMention = "mention"
+
+// Notification tells a user something happened that concerns them, Actor_ID did it
+type Notification struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	User_ID    uint   `gorm:"not null;index"`
+	Kind       string `gorm:"not null;type:varchar(30)"`
+	Actor_ID   uint   `gorm:"not null"`
+	Task_ID    uint   `gorm:"not null"`
+	Comment_ID *uint
+	ReadAt     *time.Time
+}
+
+func (n *Notification) ToNotificationResponse() response.NotificationResponse {
+	return response.NotificationResponse{
+		Id:         n.ID,
+		Created_at: n.CreatedAt,
+		Kind:       n.Kind,
+		Actor_id:   n.Actor_ID,
+		Task_id:    n.Task_ID,
+		Comment_id: n.Comment_ID,
+		Read_at:    n.ReadAt,
+	}
+}
@@ -0,0 +1,13 @@
+package response
+
+import "time"
+
+type NotificationResponse struct {
+	Id         uint       `json:"id"`
+	Created_at time.Time  `json:"created_at"`
+	Kind       string     `json:"kind"`
+	Actor_id   uint       `json:"actor_id"`
+	Task_id    uint       `json:"task_id"`
+	Comment_id *uint      `json:"comment_id"`
+	Read_at    *time.Time `json:"read_at"`
+}
@@ -0,0 +1,62 @@
+package project
+
+import (
+	"part3/models/project/response"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation lets an owner share a project with an email address that may not be registered yet,
+// the token sent by mail is signed over the row so nothing secret is stored
+type Invitation struct {
+	gorm.Model
+
+	Project_ID uint      `gorm:"not null;index"`
+	Email      string    `gorm:"not null;index;type:varchar(100)"`
+	Role       string    `gorm:"not null;type:varchar(20)"`
+	InvitedBy  uint      `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	AcceptedAt *time.Time
+	DeclinedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+func (Invitation) TableName() string {
+	return "project_invitations"
+}
+
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.DeclinedAt != nil:
+		return InvitationDeclined
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationExpired
+	}
+	return InvitationPending
+}
+
+func (i *Invitation) ToInvitationResponse(project_name string) response.InvitationResponse {
+	return response.InvitationResponse{
+		Id:           i.ID,
+		Created_at:   i.CreatedAt,
+		Project_id:   i.Project_ID,
+		Project_name: project_name,
+		Email:        i.Email,
+		Role:         i.Role,
+		Status:       i.Status(time.Now()),
+		Expires_at:   i.ExpiresAt,
+	}
+}
@@ -0,0 +1,49 @@
+package project
+
+import (
+	"part3/models/project/response"
+	"time"
+)
+
+const (
+	MemberOwner  = "owner"
+	MemberEditor = "editor"
+	MemberViewer = "viewer"
+)
+
+var MemberRoles = []string{MemberOwner, MemberEditor, MemberViewer}
+
+// EditRoles may change the project's tasks and settings, only owners manage members
+var EditRoles = []string{MemberOwner, MemberEditor}
+
+func IsValidMemberRole(role string) bool {
+	for _, r := range MemberRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Member gives a user access to a project, rows are hard deleted so a user can be added back later
+type Member struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Project_ID uint   `gorm:"not null;uniqueIndex:idx_project_member"`
+	User_ID    uint   `gorm:"not null;uniqueIndex:idx_project_member;index"`
+	Role       string `gorm:"not null;type:varchar(20)"`
+}
+
+func (Member) TableName() string {
+	return "project_members"
+}
+
+func (m *Member) ToMemberResponse() response.MemberResponse {
+	return response.MemberResponse{
+		User_id:    m.User_ID,
+		Role:       m.Role,
+		Created_at: m.CreatedAt,
+	}
+}
@@ -0,0 +1,10 @@
+package request
+
+import "time"
+
+// ProFilter narrows the project listing, zero values mean no filter
+type ProFilter struct {
+	Name          string
+	CreatedBefore *time.Time
+	CreatedAfter  *time.Time
+}
@@ -1,9 +1,12 @@
 package request
 
-import "part3/models/project"
+import (
+	"part3/models/project"
+	"strings"
+)
 
 type ProRequest struct {
-	Name string `json:"name"`
+	Name string `json:"name" validate:"required,max=100"`
 }
 
 func (p *ProRequest) ToProject() project.Project {
@@ -11,3 +14,32 @@ func (p *ProRequest) ToProject() project.Project {
 		Name: p.Name,
 	}
 }
+
+type MemberRequest struct {
+	User_id uint   `json:"user_id" validate:"required"`
+	Role    string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+func (m *MemberRequest) ToMember() project.Member {
+	return project.Member{
+		User_ID: m.User_id,
+		Role:    m.Role,
+	}
+}
+
+// MemberRoleRequest changes the role of a member, the user is in the path
+type MemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+type InvitationRequest struct {
+	Email string `json:"email" validate:"required,email,max=100"`
+	Role  string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+func (i *InvitationRequest) ToInvitation() project.Invitation {
+	return project.Invitation{
+		Email: strings.ToLower(strings.TrimSpace(i.Email)),
+		Role:  i.Role,
+	}
+}
@@ -8,3 +8,22 @@ type ProResponse struct {
 	Updated_at time.Time `jsonL:"updated_at"`
 	Name       string    `json:"name"`
 }
+
+type MemberResponse struct {
+	User_id    uint      `json:"user_id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Role       string    `json:"role"`
+	Created_at time.Time `json:"created_at"`
+}
+
+type InvitationResponse struct {
+	Id           uint      `json:"id"`
+	Created_at   time.Time `json:"created_at"`
+	Project_id   uint      `json:"project_id"`
+	Project_name string    `json:"project_name"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	Status       string    `json:"status"`
+	Expires_at   time.Time `json:"expires_at"`
+}
@@ -0,0 +1,13 @@
+package response
+
+// ResultResponse is one match, Snippet is the matched text HTML escaped with
+// the matching words wrapped in <mark>. A comment comes with its task
+type ResultResponse struct {
+	Type       string  `json:"type"`
+	ID         uint    `json:"id"`
+	Project_id uint    `json:"project_id"`
+	Task_id    *uint   `json:"task_id,omitempty"`
+	Title      string  `json:"title"`
+	Snippet    string  `json:"snippet"`
+	Score      float64 `json:"score"`
+}
@@ -0,0 +1,10 @@
+package search
+
+// kinds of records a search can return
+const (
+	TypeTask    = "task"
+	TypeProject = "project"
+	TypeComment = "comment"
+)
+
+var Types = []string{TypeTask, TypeProject, TypeComment}
@@ -0,0 +1,26 @@
+package session
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session groups every refresh token issued from one login, revoking it
+// invalidates the access tokens carrying its id as well
+type Session struct {
+	gorm.Model
+
+	User_ID   uint      `gorm:"not null;index"`
+	ExpiresAt time.Time `gorm:"not null"`
+	RevokedAt *time.Time
+}
+
+type RefreshToken struct {
+	gorm.Model
+
+	Session_ID uint      `gorm:"not null;index"`
+	TokenHash  string    `gorm:"unique;not null;type:varchar(64)"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	UsedAt     *time.Time
+}
@@ -0,0 +1,17 @@
+package task
+
+import "time"
+
+// Assignee links a task to one of the project members working on it
+type Assignee struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	Task_ID    uint `gorm:"not null;uniqueIndex:idx_task_assignee"`
+	User_ID    uint `gorm:"not null;uniqueIndex:idx_task_assignee;index"`
+	AssignedBy uint `gorm:"not null"`
+}
+
+func (Assignee) TableName() string {
+	return "task_assignees"
+}
@@ -0,0 +1,39 @@
+package task
+
+import (
+	"part3/models/task/response"
+	"time"
+)
+
+// Attachment describes a file uploaded to a task, the content lives in the storage under StorageKey
+type Attachment struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	Task_ID     uint   `gorm:"not null;index"`
+	UploadedBy  uint   `gorm:"not null"`
+	Name        string `gorm:"not null;type:varchar(255)"`
+	ContentType string `gorm:"not null;type:varchar(100)"`
+	Size        int64  `gorm:"not null"`
+	// hex encoded sha256 of the content
+	Checksum   string `gorm:"not null;type:varchar(64)"`
+	StorageKey string `gorm:"not null;type:varchar(255);uniqueIndex"`
+	// set once the task is deleted, the file is removed by the next purge
+	OrphanedAt *time.Time `gorm:"index"`
+}
+
+func (Attachment) TableName() string {
+	return "task_attachments"
+}
+
+func (a *Attachment) ToAttachmentResponse() response.AttachmentResponse {
+	return response.AttachmentResponse{
+		ID:           a.ID,
+		CreatedAt:    a.CreatedAt,
+		Name:         a.Name,
+		Content_type: a.ContentType,
+		Size:         a.Size,
+		Checksum:     a.Checksum,
+		Uploaded_by:  a.UploadedBy,
+	}
+}
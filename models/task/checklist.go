@@ -0,0 +1,31 @@
+package task
+
+import (
+	"part3/models/task/response"
+	"time"
+)
+
+// ChecklistItem is a lightweight step of a task, too small to be a subtask
+type ChecklistItem struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Task_ID  uint   `gorm:"not null;index"`
+	Name     string `gorm:"not null;type:varchar(200)"`
+	Done     bool   `gorm:"not null;default:false"`
+	Position int    `gorm:"not null"`
+}
+
+func (ChecklistItem) TableName() string {
+	return "task_checklist_items"
+}
+
+func (ci *ChecklistItem) ToChecklistItemResponse() response.ChecklistItemResponse {
+	return response.ChecklistItemResponse{
+		ID:       ci.ID,
+		Name:     ci.Name,
+		Done:     ci.Done,
+		Position: ci.Position,
+	}
+}
@@ -0,0 +1,18 @@
+package task
+
+import "time"
+
+// Dependency says the blocker task has to be done before the blocked one,
+// both tasks belong to the same project
+type Dependency struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	Blocker_ID uint `gorm:"not null;uniqueIndex:idx_task_dependency"`
+	Blocked_ID uint `gorm:"not null;uniqueIndex:idx_task_dependency;index"`
+	CreatedBy  uint `gorm:"not null"`
+}
+
+func (Dependency) TableName() string {
+	return "task_dependencies"
+}
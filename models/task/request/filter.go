@@ -0,0 +1,28 @@
+package request
+
+import "time"
+
+const (
+	LabelModeAny = "any"
+	LabelModeAll = "all"
+)
+
+// TaskFilter narrows the task listing, zero values mean no filter.
+// Parent_id pointing at 0 keeps top level tasks only, LabelMode is any or all of Labels
+type TaskFilter struct {
+	Project_id     int
+	Assignee       int
+	Labels         []string
+	LabelMode      string
+	Parent_id      *uint
+	Status         string
+	PriorityMin    *int
+	PriorityMax    *int
+	Name           string
+	CreatedBefore  *time.Time
+	CreatedAfter   *time.Time
+	DueBefore      *time.Time
+	DueAfter       *time.Time
+	Overdue        bool
+	IncompleteOnly bool
+}
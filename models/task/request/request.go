@@ -1,12 +1,24 @@
 package request
 
-import "part3/models/task"
+import (
+	"part3/models/task"
+	"part3/models/task/response"
+	"time"
+)
 
+// StartAt and DueAt are RFC3339 so the client's offset is kept, they are stored in UTC.
+// An update replaces the whole task, a missing or 0 Parent_id takes the task out of
+// its parent. Recurrence is a rule like FREQ=WEEKLY;BYDAY=MO read in Timezone, an
+// empty one stops the task recurring
 type TaskRequest struct {
-	Name       string `json:"name"`
-	Priority   int    `json:"priority"`
-	Project_id uint   `json:"project_id"`
-	Status     bool   `json:"status"`
+	Name       string     `json:"name" validate:"required,max=100"`
+	Priority   int        `json:"priority" validate:"gte=0"`
+	Project_id uint       `json:"project_id"`
+	Parent_id  *uint      `json:"parent_id"`
+	StartAt    *time.Time `json:"start_at"`
+	DueAt      *time.Time `json:"due_at"`
+	Recurrence *string    `json:"recurrence" validate:"omitempty,max=200"`
+	Timezone   *string    `json:"timezone" validate:"omitempty,max=64"`
 }
 
 func (t *TaskRequest) ToTask() task.Task {
@@ -14,6 +26,80 @@ func (t *TaskRequest) ToTask() task.Task {
 		Name:       t.Name,
 		Priority:   t.Priority,
 		Project_id: t.Project_id,
-		Status:     t.Status,
+		Parent_id:  t.Parent_id,
+		StartAt:    toUTC(t.StartAt),
+		DueAt:      toUTC(t.DueAt),
+		Recurrence: valueOf(t.Recurrence),
+		Timezone:   valueOf(t.Timezone),
 	}
 }
+
+// NewTaskRequest is the request that would leave the task as it is, a patch
+// is applied to it
+func NewTaskRequest(t response.TaskResponse) TaskRequest {
+	return TaskRequest{
+		Name:       t.Name,
+		Priority:   t.Priority,
+		Project_id: uint(t.Project_id),
+		Parent_id:  t.Parent_id,
+		StartAt:    t.StartAt,
+		DueAt:      t.DueAt,
+		Recurrence: &t.Recurrence,
+		Timezone:   &t.Timezone,
+	}
+}
+
+func valueOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ValidDates reports whether the task does not start after it is due
+func (t *TaskRequest) ValidDates() bool {
+	return t.StartAt == nil || t.DueAt == nil || !t.StartAt.After(*t.DueAt)
+}
+
+func toUTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+type AssigneeRequest struct {
+	User_ids []uint `json:"user_ids" validate:"required,min=1"`
+}
+
+type ChecklistRequest struct {
+	Name     string `json:"name" validate:"max=200"`
+	Done     *bool  `json:"done"`
+	Position *int   `json:"position"`
+}
+
+func (cr *ChecklistRequest) ToChecklistItem() task.ChecklistItem {
+	item := task.ChecklistItem{Name: cr.Name}
+	if cr.Done != nil {
+		item.Done = *cr.Done
+	}
+	if cr.Position != nil {
+		item.Position = *cr.Position
+	}
+	return item
+}
+
+type DependencyRequest struct {
+	Blocker_id uint `json:"blocker_id" validate:"required"`
+}
+
+// MoveRequest puts a task right before or right after another task of the
+// project, or at the end of the board when neither is given. Status and
+// Section_id change its column, Section_id 0 takes it out of its section
+type MoveRequest struct {
+	Status     string `json:"status"`
+	Section_id *uint  `json:"section_id"`
+	Before_id  uint   `json:"before_id"`
+	After_id   uint   `json:"after_id"`
+}
@@ -1,15 +1,110 @@
 package response
 
-import "time"
+import (
+	labelResp "part3/models/label/response"
+	"time"
+)
 
 type TaskResponse struct {
 	ID        uint      `json:"id"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 
-	Name         string `json:"name"`
-	Status       bool   `json:"status"`
-	Priority     int    `json:"priority"`
-	Project_id   int    `json:"project_id"`
-	Project_name string `json:"project_name"`
+	Name            string     `json:"name"`
+	CreatedBy       uint       `json:"created_by"`
+	Status          string     `json:"status"`
+	StatusChangedAt time.Time  `json:"status_changed_at"`
+	CompletedAt     *time.Time `json:"completed_at"`
+	CompletedBy     *uint      `json:"completed_by"`
+	StartAt         *time.Time `json:"start_at"`
+	DueAt           *time.Time `json:"due_at"`
+	Priority        int        `json:"priority"`
+	Project_id      int        `json:"project_id"`
+	Project_name    string     `json:"project_name"`
+	Parent_id       *uint      `json:"parent_id"`
+	Recurrence      string     `json:"recurrence"`
+	Timezone        string     `json:"timezone"`
+	Next_id         *uint      `json:"next_id"`
+	Section_id      *uint      `json:"section_id"`
+	Rank            string     `json:"rank" gorm:"column:board_rank"`
+
+	Assignees []AssigneeResponse        `json:"assignees" gorm:"-"`
+	Labels    []labelResp.LabelResponse `json:"labels" gorm:"-"`
+	Subtasks  ProgressResponse          `json:"subtasks" gorm:"-"`
+	Checklist ProgressResponse          `json:"checklist" gorm:"-"`
+	Progress  ProgressResponse          `json:"progress" gorm:"-"`
+}
+
+// ProgressResponse counts how much of a breakdown is done, e.g. 3 of 5
+type ProgressResponse struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+type ChecklistItemResponse struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Done     bool   `json:"done"`
+	Position int    `json:"position"`
+}
+
+type AssigneeResponse struct {
+	User_id uint   `json:"user_id"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+}
+
+type AttachmentResponse struct {
+	ID           uint      `json:"id"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Name         string    `json:"name"`
+	Content_type string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Checksum     string    `json:"checksum"`
+	Uploaded_by  uint      `json:"uploaded_by"`
+}
+
+type AgendaResponse struct {
+	Overdue  []TaskResponse `json:"overdue"`
+	Today    []TaskResponse `json:"today"`
+	Upcoming []TaskResponse `json:"upcoming"`
+}
+
+// DependencyTaskResponse is a task on the other side of a dependency
+type DependencyTaskResponse struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+type DependenciesResponse struct {
+	Blocked_by []DependencyTaskResponse `json:"blocked_by"`
+	Blocks     []DependencyTaskResponse `json:"blocks"`
+}
+
+// GraphNodeResponse is a task of the dependency graph, Duration is the work
+// left on it in days, counted from its start and due dates
+type GraphNodeResponse struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	StartAt     *time.Time `json:"start_at"`
+	DueAt       *time.Time `json:"due_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	Duration    int        `json:"duration"`
+}
+
+type GraphEdgeResponse struct {
+	Blocker_id uint `json:"blocker_id"`
+	Blocked_id uint `json:"blocked_id"`
+}
+
+// GraphResponse is the dependency DAG of a project, the critical path is the
+// chain of tasks with the most work left, from the first blocker to the last task
+type GraphResponse struct {
+	Nodes           []GraphNodeResponse `json:"nodes"`
+	Edges           []GraphEdgeResponse `json:"edges"`
+	Critical_path   []uint              `json:"critical_path"`
+	Critical_length int                 `json:"critical_length"`
 }
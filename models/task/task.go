@@ -2,6 +2,7 @@ package task
 
 import (
 	"part3/models/task/response"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -9,19 +10,44 @@ import (
 type Task struct {
 	gorm.Model
 
-	User_ID    uint
-	Name       string `gorm:"not null;type:varchar(100)"`
-	Status     bool   `type:"boolean"`
-	Priority   int    `gorm:"not null;index;type:int"`
-	Project_id uint   `gorm:"not null"`
+	CreatedBy       uint   `gorm:"not null;index"`
+	Name            string `gorm:"not null;type:varchar(100)"`
+	Status          string `gorm:"not null;default:todo;type:varchar(30)"`
+	StatusChangedAt time.Time
+	CompletedAt     *time.Time
+	CompletedBy     *uint
+	StartAt         *time.Time
+	DueAt           *time.Time `gorm:"index"`
+	Priority        int        `gorm:"not null;index;type:int"`
+	Project_id      uint       `gorm:"not null"`
+	Parent_id       *uint      `gorm:"index"`
+	Recurrence      string     `gorm:"not null;default:'';type:varchar(200)"`
+	Timezone        string     `gorm:"not null;default:'';type:varchar(64)"`
+	Next_id         *uint
+	Section_id      *uint  `gorm:"index"`
+	Rank            string `gorm:"column:board_rank;not null;default:'';type:varchar(191);index"`
 }
 
 func (t *Task) ToTaskResponse() response.TaskResponse {
 	return response.TaskResponse{
-		ID:         t.ID,
-		Name:       t.Name,
-		Status:     t.Status,
-		Priority:   t.Priority,
-		Project_id: int(t.Project_id),
+		ID:              t.ID,
+		CreatedAt:       t.CreatedAt,
+		UpdatedAt:       t.UpdatedAt,
+		Name:            t.Name,
+		CreatedBy:       t.CreatedBy,
+		Status:          t.Status,
+		StatusChangedAt: t.StatusChangedAt,
+		CompletedAt:     t.CompletedAt,
+		CompletedBy:     t.CompletedBy,
+		StartAt:         t.StartAt,
+		DueAt:           t.DueAt,
+		Priority:        t.Priority,
+		Project_id:      int(t.Project_id),
+		Parent_id:       t.Parent_id,
+		Recurrence:      t.Recurrence,
+		Timezone:        t.Timezone,
+		Next_id:         t.Next_id,
+		Section_id:      t.Section_id,
+		Rank:            t.Rank,
 	}
 }
@@ -1,6 +1,10 @@
 package request
 
 type Userlogin struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,max=100"`
+	Password string `json:"password" validate:"required,maxbytes=72"`
+}
+
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
@@ -2,10 +2,13 @@ package request
 
 import "part3/models/user"
 
+// Invitation is the signed token of a project invitation, the new account
+// joins the project when the token was sent to its email
 type UserRegister struct {
-	Name     string `json:"name"`
-	Email    string `json:"email" `
-	Password string `json:"password"`
+	Name       string `json:"name" validate:"required,max=100"`
+	Email      string `json:"email" validate:"required,email,max=100"`
+	Password   string `json:"password" validate:"required,maxbytes=72"`
+	Invitation string `json:"invitation"`
 }
 
 func (u *UserRegister) ToUser() user.User {
@@ -22,4 +25,16 @@ func (u *UserRegister) ToUserCont(name string, email string, password string) us
 		Email:    email,
 		Password: password,
 	}
-}
\ No newline at end of file
+}
+
+// UserUpdate replaces the profile, the password is never read back so it only
+// changes when given
+type UserUpdate struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=100"`
+	Password string `json:"password,omitempty" validate:"omitempty,maxbytes=72"`
+}
+
+type UserRole struct {
+	Role string `json:"role" validate:"required,oneof=admin manager member"`
+}
@@ -13,6 +13,7 @@ type UserResponse struct {
 
 	Name     string                  `json:"name"`
 	Email    string                  `json:"email"`
+	Role     string                  `json:"role"`
 	Projects []proResp.ProResponse   `json:"projects"`
 	Tasks    []taskResp.TaskResponse `json:"tasks"`
 }
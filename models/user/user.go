@@ -8,13 +8,32 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	RoleAdmin    = "admin"
+	RoleManager  = "manager"
+	RoleMember   = "member"
+	RoleReadOnly = "read-only"
+)
+
+var Roles = []string{RoleAdmin, RoleManager, RoleMember, RoleReadOnly}
+
+func IsValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 type User struct {
 	gorm.Model
 
 	Name     string            `gorm:"not null;type:varchar(100)"`
 	Email    string            `gorm:"unique;index;not null;type:varchar(100)"`
-	Password string            `gorm:"unique;not null;type:varchar(100)"`
-	Tasks    []task.Task       `gorm:"foreignKey:User_ID"`
+	Password string            `gorm:"not null;type:varchar(100)"`
+	Role     string            `gorm:"not null;default:member;type:varchar(20)"`
+	Tasks    []task.Task       `gorm:"foreignKey:CreatedBy"`
 	Projects []project.Project `gorm:"foreignKey:User_ID"`
 }
 
@@ -26,5 +45,6 @@ func (u *User) ToUserResponse() response.UserResponse {
 
 		Name:  u.Name,
 		Email: u.Email,
+		Role:  u.Role,
 	}
 }
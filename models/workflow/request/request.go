@@ -0,0 +1,56 @@
+package request
+
+import (
+	"errors"
+	"part3/models/workflow"
+)
+
+type StatusRequest struct {
+	Name string `json:"name"`
+	Done bool   `json:"done"`
+}
+
+type TransitionRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type WorkflowRequest struct {
+	Statuses    []StatusRequest     `json:"statuses"`
+	Transitions []TransitionRequest `json:"transitions"`
+}
+
+type TransitionTask struct {
+	Status string `json:"status" validate:"required"`
+}
+
+// ToWorkflow keeps the statuses in request order and rejects workflows
+// with duplicate names, no single done status or dangling transitions
+func (w *WorkflowRequest) ToWorkflow() (workflow.Workflow, error) {
+	wf := workflow.Workflow{}
+	names := map[string]bool{}
+	done := 0
+
+	for i, s := range w.Statuses {
+		if s.Name == "" || len(s.Name) > 30 || names[s.Name] {
+			return workflow.Workflow{}, errors.New("invalid status name")
+		}
+		names[s.Name] = true
+		if s.Done {
+			done++
+		}
+		wf.Statuses = append(wf.Statuses, workflow.Status{Name: s.Name, Position: i, IsDone: s.Done})
+	}
+	if len(wf.Statuses) < 2 || done != 1 {
+		return workflow.Workflow{}, errors.New("workflow needs at least two statuses and exactly one done status")
+	}
+
+	for _, t := range w.Transitions {
+		if !names[t.From] || !names[t.To] || t.From == t.To {
+			return workflow.Workflow{}, errors.New("invalid transition")
+		}
+		wf.Transitions = append(wf.Transitions, workflow.Transition{From: t.From, To: t.To})
+	}
+
+	return wf, nil
+}
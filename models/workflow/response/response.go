@@ -0,0 +1,17 @@
+package response
+
+type StatusResponse struct {
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+	Done     bool   `json:"done"`
+}
+
+type TransitionResponse struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type WorkflowResponse struct {
+	Statuses    []StatusResponse     `json:"statuses"`
+	Transitions []TransitionResponse `json:"transitions"`
+}
@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"part3/models/workflow/response"
+
+	"gorm.io/gorm"
+)
+
+const (
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusBlocked    = "blocked"
+	StatusReview     = "review"
+	StatusDone       = "done"
+)
+
+// Status is one column of a project's workflow, the lowest position is the
+// state new tasks start in
+type Status struct {
+	gorm.Model
+
+	Project_ID uint   `gorm:"not null;index"`
+	Name       string `gorm:"not null;type:varchar(30)"`
+	Position   int    `gorm:"not null"`
+	IsDone     bool   `gorm:"not null"`
+}
+
+func (Status) TableName() string {
+	return "workflow_statuses"
+}
+
+type Transition struct {
+	gorm.Model
+
+	Project_ID uint   `gorm:"not null;index"`
+	From       string `gorm:"column:from_status;not null;type:varchar(30)"`
+	To         string `gorm:"column:to_status;not null;type:varchar(30)"`
+}
+
+func (Transition) TableName() string {
+	return "workflow_transitions"
+}
+
+type Workflow struct {
+	Statuses    []Status
+	Transitions []Transition
+}
+
+// Default is used by projects that never configured their own workflow
+func Default() Workflow {
+	statuses := []Status{
+		{Name: StatusTodo, Position: 0},
+		{Name: StatusInProgress, Position: 1},
+		{Name: StatusBlocked, Position: 2},
+		{Name: StatusReview, Position: 3},
+		{Name: StatusDone, Position: 4, IsDone: true},
+	}
+	edges := [][2]string{
+		{StatusTodo, StatusInProgress},
+		{StatusTodo, StatusBlocked},
+		{StatusTodo, StatusDone},
+		{StatusInProgress, StatusTodo},
+		{StatusInProgress, StatusBlocked},
+		{StatusInProgress, StatusReview},
+		{StatusInProgress, StatusDone},
+		{StatusBlocked, StatusTodo},
+		{StatusBlocked, StatusInProgress},
+		{StatusReview, StatusInProgress},
+		{StatusReview, StatusDone},
+		{StatusDone, StatusTodo},
+	}
+	transitions := []Transition{}
+	for _, edge := range edges {
+		transitions = append(transitions, Transition{From: edge[0], To: edge[1]})
+	}
+	return Workflow{Statuses: statuses, Transitions: transitions}
+}
+
+func (w *Workflow) Initial() string {
+	initial := Status{Position: -1}
+	for _, s := range w.Statuses {
+		if initial.Position == -1 || s.Position < initial.Position {
+			initial = s
+		}
+	}
+	return initial.Name
+}
+
+func (w *Workflow) DoneStatus() string {
+	for _, s := range w.Statuses {
+		if s.IsDone {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+func (w *Workflow) Has(status string) bool {
+	for _, s := range w.Statuses {
+		if s.Name == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Workflow) CanTransition(from string, to string) bool {
+	for _, t := range w.Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Workflow) ToWorkflowResponse() response.WorkflowResponse {
+	resp := response.WorkflowResponse{
+		Statuses:    []response.StatusResponse{},
+		Transitions: []response.TransitionResponse{},
+	}
+	for _, s := range w.Statuses {
+		resp.Statuses = append(resp.Statuses, response.StatusResponse{Name: s.Name, Position: s.Position, Done: s.IsDone})
+	}
+	for _, t := range w.Transitions {
+		resp.Transitions = append(resp.Transitions, response.TransitionResponse{From: t.From, To: t.To})
+	}
+	return resp
+}
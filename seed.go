@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"part3/configs"
+	"part3/lib/database/seed"
+	"part3/utils"
+)
+
+// runSeed handles `seed`, the demo user signs in with the printed password
+func runSeed(config *configs.AppConfig, args []string) error {
+	flags := newFlags("seed")
+	password := flags.String("password", "", "password of the demo user, generated when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	pass, _, err := passwordOrNew(*password)
+	if err != nil {
+		return err
+	}
+
+	res, err := seed.Seed(utils.InitDB(config), pass)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("seeded %d projects and %d tasks for user %d\n", res.Projects, res.Tasks, res.User.ID)
+	fmt.Println("email:   ", res.User.Email)
+	fmt.Println("password:", res.Password)
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"part3/configs"
+	"part3/delivery/controllers/attachment"
+	"part3/delivery/controllers/auth"
+	"part3/delivery/controllers/board"
+	"part3/delivery/controllers/comment"
+	"part3/delivery/controllers/dependency"
+	"part3/delivery/controllers/invitation"
+	"part3/delivery/controllers/label"
+	"part3/delivery/controllers/notification"
+	"part3/delivery/controllers/project"
+	"part3/delivery/controllers/search"
+	"part3/delivery/controllers/task"
+	"part3/delivery/controllers/user"
+	"part3/delivery/middlewares"
+	"part3/delivery/routes"
+	"part3/delivery/validation"
+	_authDb "part3/lib/database/auth"
+	_commentDb "part3/lib/database/comment"
+	_labelDb "part3/lib/database/label"
+	_notificationDb "part3/lib/database/notification"
+	_proDb "part3/lib/database/project"
+	_searchDb "part3/lib/database/search"
+	_taskDB "part3/lib/database/task"
+	_userDb "part3/lib/database/user"
+	"part3/lib/mailer"
+	"part3/lib/storage"
+	"part3/utils"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// serve starts the API server, the migrations are applied first
+func serve(config *configs.AppConfig) error {
+	if err := middlewares.InitKeys(config); err != nil {
+		return err
+	}
+	db := utils.InitDB(config)
+
+	userRepo := _userDb.New(db)
+	userController := user.New(userRepo)
+	proRepo := _proDb.New(db)
+	proController := project.NewRepo(proRepo)
+	taskRepo := _taskDB.New(db)
+	taskRepo.SetBlockCompletion(config.Tasks.BlockCompletion)
+	taskController := task.New(taskRepo, proRepo)
+	mail, err := mailer.New(config)
+	if err != nil {
+		return err
+	}
+	invitationController := invitation.New(proRepo, mail, config.Invitation.BaseURL)
+	userController.SetInvitations(proRepo)
+	store, err := storage.New(config)
+	if err != nil {
+		return err
+	}
+	attachmentRepo := _taskDB.NewAttachments(db, store)
+	attachmentController := attachment.New(attachmentRepo, config.Storage.MaxSize, config.Storage.AllowedTypes)
+	go attachmentRepo.PurgeEvery(time.Hour, nil)
+	commentController := comment.New(_commentDb.New(db))
+	dependencyController := dependency.New(_taskDB.NewDependencies(db))
+	boardController := board.New(_taskDB.NewBoard(db))
+	searchController := search.New(_searchDb.New(db))
+	labelController := label.New(_labelDb.New(db))
+	notificationController := notification.New(_notificationDb.New(db))
+	authRepo := _authDb.New(db)
+	authController := auth.New(authRepo)
+	middlewares.SetSessionChecker(authRepo)
+
+	e := echo.New()
+	e.HTTPErrorHandler = middlewares.ErrorHandler
+	e.Validator = validation.New()
+
+	routes.UserPath(e, userController, authController)
+	routes.TaskPath(e, taskController)
+	routes.ProjectPath(e, proController)
+	routes.InvitationPath(e, invitationController)
+	routes.CommentPath(e, commentController)
+	routes.AttachmentPath(e, attachmentController)
+	routes.LabelPath(e, labelController)
+	routes.DependencyPath(e, dependencyController)
+	routes.BoardPath(e, boardController)
+	routes.SearchPath(e, searchController)
+	routes.NotificationPath(e, notificationController)
+	routes.AdminPath(e, userController, authController)
+
+	return e.Start(fmt.Sprintf(":%d", config.Port))
+}
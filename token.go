@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"part3/configs"
+	"part3/delivery/middlewares"
+	_authDb "part3/lib/database/auth"
+	_userDb "part3/lib/database/user"
+	"part3/utils"
+	"strings"
+)
+
+// runToken handles `token issue`, it opens a session for the user like a login
+// would, for scripts and for trying the API
+func runToken(config *configs.AppConfig, args []string) error {
+	if len(args) == 0 || args[0] != "issue" {
+		return errUsage
+	}
+	flags := newFlags("token issue")
+	email := flags.String("email", "", "email of the user")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*email) == "" {
+		return errUsage
+	}
+	if err := middlewares.InitKeys(config); err != nil {
+		return err
+	}
+
+	db := utils.InitDB(config)
+	found, err := findUser(_userDb.New(db), *email)
+	if err != nil {
+		return err
+	}
+	sess, refresh, err := _authDb.New(db).CreateSession(int(found.ID))
+	if err != nil {
+		return err
+	}
+	access, err := middlewares.GenerateToken(found, sess.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("access token: ", access)
+	fmt.Println("refresh token:", refresh)
+	fmt.Println("session ends: ", sess.ExpiresAt.Format("2006-01-02 15:04:05"))
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"part3/configs"
+	_authDb "part3/lib/database/auth"
+	_userDb "part3/lib/database/user"
+	"part3/models/user"
+	"part3/models/user/request"
+	"part3/utils"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// newFlags parses args for a command, the usage goes to stderr on bad flags
+func newFlags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+// newPassword is a random password for the commands given none
+func newPassword() (string, error) {
+	raw := make([]byte, 12)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// passwordOrNew returns the given password, or a new one and true
+func passwordOrNew(password string) (string, bool, error) {
+	if password != "" {
+		return password, false, nil
+	}
+	generated, err := newPassword()
+	return generated, true, err
+}
+
+// findUser looks the user up by email, a missing one is a plain message
+func findUser(users *_userDb.UserDb, email string) (user.User, error) {
+	found, err := users.GetByEmail(strings.TrimSpace(email))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return found, fmt.Errorf("no user with email %q", email)
+	}
+	return found, err
+}
+
+// runUser handles `user create` and `user reset-password`
+func runUser(config *configs.AppConfig, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "create":
+		flags := newFlags("user create")
+		name := flags.String("name", "", "display name")
+		email := flags.String("email", "", "email to sign in with")
+		password := flags.String("password", "", "password, generated when empty")
+		admin := flags.Bool("admin", false, "give the user the admin role")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if strings.TrimSpace(*name) == "" || strings.TrimSpace(*email) == "" {
+			return errUsage
+		}
+		pass, generated, err := passwordOrNew(*password)
+		if err != nil {
+			return err
+		}
+
+		newUser := user.User{Name: strings.TrimSpace(*name), Email: strings.TrimSpace(*email), Password: pass}
+		if *admin {
+			newUser.Role = user.RoleAdmin
+		}
+		created, err := _userDb.New(utils.InitDB(config)).Create(newUser)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created %s user %d <%s>\n", created.Role, created.ID, created.Email)
+		if generated {
+			fmt.Println("password:", pass)
+		}
+		return nil
+	case "reset-password":
+		flags := newFlags("user reset-password")
+		email := flags.String("email", "", "email of the user")
+		password := flags.String("password", "", "new password, generated when empty")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if strings.TrimSpace(*email) == "" {
+			return errUsage
+		}
+		pass, generated, err := passwordOrNew(*password)
+		if err != nil {
+			return err
+		}
+
+		db := utils.InitDB(config)
+		users := _userDb.New(db)
+		found, err := findUser(users, *email)
+		if err != nil {
+			return err
+		}
+		if _, err := users.UpdateById(int(found.ID), request.UserRegister{Name: found.Name, Email: found.Email, Password: pass}); err != nil {
+			return err
+		}
+		// whoever had the old password must sign in again
+		if err := _authDb.New(db).RevokeAllSessions(int(found.ID)); err != nil {
+			return err
+		}
+		fmt.Printf("reset the password of user %d <%s>, its sessions are revoked\n", found.ID, found.Email)
+		if generated {
+			fmt.Println("password:", pass)
+		}
+		return nil
+	}
+	return errUsage
+}
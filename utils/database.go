@@ -0,0 +1,295 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"part3/configs"
+	"part3/lib/migrate"
+	"part3/lib/rank"
+	"part3/migrations"
+	"part3/models/board"
+	"part3/models/comment"
+	"part3/models/label"
+	"part3/models/notification"
+	"part3/models/project"
+	"part3/models/session"
+	"part3/models/task"
+	"part3/models/user"
+	"part3/models/workflow"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/labstack/gommon/log"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// drivers InitDB knows, sqlite takes the database name as a file path and
+// keeps the database in memory when the name is empty or ":memory:"
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+var ErrUnknownDriver = errors.New("unknown database driver")
+
+// InitDB connects and brings the schema up to date
+func InitDB(config *configs.AppConfig) *gorm.DB {
+	DB, err := OpenDB(config)
+	if err != nil {
+		log.Info("error in connect database ", err)
+		panic(err)
+	}
+
+	if _, err := Migrate(DB); err != nil {
+		log.Info("error in migrate database ", err)
+		panic(err)
+	}
+	return DB
+}
+
+// OpenDB connects without touching the schema
+func OpenDB(config *configs.AppConfig) (*gorm.DB, error) {
+	dialector, err := Dialector(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// the migrations own the foreign keys, the legacy upgrade must not add its own
+	DB, err := gorm.Open(dialector, &gorm.Config{DisableForeignKeyConstraintWhenMigrating: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if dialector.Name() == DriverSQLite {
+		// sqlite has a single writer, and an in-memory database lives as long as
+		// its connection
+		sqlDB, err := DB.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return DB, nil
+}
+
+// IsDuplicate reports whether err is a unique constraint violation, on any of
+// the drivers
+func IsDuplicate(err error) bool {
+	var mysqlErr *mysqlDriver.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	return false
+}
+
+// Dialector picks the gorm driver from config.Database.Driver, MySQL when empty
+func Dialector(config *configs.AppConfig) (gorm.Dialector, error) {
+	database := config.Database
+
+	switch database.Driver {
+	case "", DriverMySQL:
+		return mysql.Open(fmt.Sprintf("%v:%v@tcp(%v:%v)/%v?charset=utf8&parseTime=True&loc=Local",
+			database.Username,
+			database.Password,
+			database.Address,
+			database.Port,
+			database.Name,
+		)), nil
+	case DriverPostgres:
+		return postgres.Open(fmt.Sprintf("host=%v port=%v user=%v password=%v dbname=%v sslmode=disable",
+			database.Address,
+			database.Port,
+			database.Username,
+			database.Password,
+			database.Name,
+		)), nil
+	case DriverSQLite:
+		if database.Name == "" || database.Name == ":memory:" {
+			return sqlite.Open(":memory:"), nil
+		}
+		return sqlite.Open(database.Name), nil
+	}
+	return nil, ErrUnknownDriver
+}
+
+// legacyVersion is the migration matching the schema AutoMigrate kept before
+// the migrations
+const legacyVersion = 1
+
+// NewMigrator reads the migrations of the driver of DB
+func NewMigrator(DB *gorm.DB) (*migrate.Migrator, error) {
+	files, err := fs.Sub(migrations.Files, DB.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(DB, files)
+}
+
+// Migrate applies the pending migrations and returns them. A database made by
+// AutoMigrate before the migrations gets the upgrades AutoMigrate did and is
+// marked at legacyVersion first
+func Migrate(DB *gorm.DB) ([]migrate.Migration, error) {
+	migrator, err := NewMigrator(DB)
+	if err != nil {
+		return nil, err
+	}
+
+	legacy := func(conn *gorm.DB) bool { return conn.Migrator().HasTable(&user.User{}) }
+	if err := migrator.Adopt(legacyVersion, legacy, upgradeLegacy); err != nil {
+		return nil, err
+	}
+
+	return migrator.Up()
+}
+
+// ResetDB reverts every migration and applies them again, tests start from an
+// empty schema with it
+func ResetDB(DB *gorm.DB) error {
+	migrator, err := NewMigrator(DB)
+	if err != nil {
+		return err
+	}
+
+	if _, err := migrator.Down(len(migrator.Migrations())); err != nil {
+		return err
+	}
+	_, err = migrator.Up()
+	return err
+}
+
+// upgradeLegacy is the AutoMigrate every boot used to run, it only runs once
+// now to bring an old database to legacyVersion
+func upgradeLegacy(DB *gorm.DB) error {
+	if err := DB.AutoMigrate(&user.User{}); err != nil {
+		return err
+	}
+	// user_id on tasks always meant the creator, keep the data under its real name
+	if DB.Migrator().HasTable(&task.Task{}) && DB.Migrator().HasColumn(&task.Task{}, "user_id") && !DB.Migrator().HasColumn(&task.Task{}, "created_by") {
+		if err := DB.Migrator().RenameColumn(&task.Task{}, "user_id", "created_by"); err != nil {
+			return err
+		}
+	}
+	if err := DB.AutoMigrate(&task.Task{}); err != nil {
+		return err
+	}
+	// status used to be a boolean column, map the old values onto the default workflow
+	if err := DB.Model(&task.Task{}).Where("status = ?", "1").Update("status", workflow.StatusDone).Error; err != nil {
+		return err
+	}
+	if err := DB.Model(&task.Task{}).Where("status = ?", "0").Update("status", workflow.StatusTodo).Error; err != nil {
+		return err
+	}
+	if err := DB.Model(&task.Task{}).Where("status_changed_at IS NULL").Update("status_changed_at", gorm.Expr("updated_at")).Error; err != nil {
+		return err
+	}
+	if err := DB.Model(&task.Task{}).Where("status = ? AND completed_at IS NULL", workflow.StatusDone).Update("completed_at", gorm.Expr("status_changed_at")).Error; err != nil {
+		return err
+	}
+	if err := DB.AutoMigrate(
+		&project.Project{},
+		&session.Session{},
+		&session.RefreshToken{},
+		&workflow.Status{},
+		&workflow.Transition{},
+		&project.Member{},
+		&project.Invitation{},
+		&task.Assignee{},
+		&task.ChecklistItem{},
+		&task.Attachment{},
+		&task.Dependency{},
+		&comment.Comment{},
+		&comment.Revision{},
+		&comment.Mention{},
+		&notification.Notification{},
+		&label.Label{},
+		&label.TaskLabel{},
+		&board.Section{},
+	); err != nil {
+		return err
+	}
+	if err := rankTasks(DB); err != nil {
+		return err
+	}
+	if err := fulltextIndexes(DB); err != nil {
+		return err
+	}
+	// projects used to belong to their creator only, make every creator the owner
+	return DB.Exec(`INSERT INTO project_members (created_at, updated_at, project_id, user_id, role)
+		SELECT CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, projects.id, projects.user_id, ?
+		FROM projects
+		WHERE projects.deleted_at IS NULL AND NOT EXISTS (
+			SELECT 1 FROM project_members WHERE project_members.project_id = projects.id AND project_members.user_id = projects.user_id
+		)`, project.MemberOwner).Error
+}
+
+// rankTasks puts the tasks made before the board after the ranked ones of their
+// project, in the order they were created
+func rankTasks(DB *gorm.DB) error {
+	unranked := []task.Task{}
+	if err := DB.Unscoped().Where("board_rank = ''").Order("project_id").Order("id").Find(&unranked).Error; err != nil {
+		return err
+	}
+
+	last := map[uint]string{}
+	for _, t := range unranked {
+		if _, ok := last[t.Project_id]; !ok {
+			var max struct{ Board_rank *string }
+			if err := DB.Unscoped().Model(&task.Task{}).Select("max(board_rank) as board_rank").Where("project_id = ?", t.Project_id).Scan(&max).Error; err != nil {
+				return err
+			}
+			last[t.Project_id] = ""
+			if max.Board_rank != nil {
+				last[t.Project_id] = *max.Board_rank
+			}
+		}
+		key, err := rank.Between(last[t.Project_id], "")
+		if err != nil {
+			return err
+		}
+		if err := DB.Unscoped().Model(&task.Task{}).Where("id = ?", t.ID).Update("board_rank", key).Error; err != nil {
+			return err
+		}
+		last[t.Project_id] = key
+	}
+	return nil
+}
+
+// fulltextIndexes backs the search with FULLTEXT indexes on MySQL, other
+// databases search with LIKE
+func fulltextIndexes(DB *gorm.DB) error {
+	if DB.Dialector.Name() != DriverMySQL {
+		return nil
+	}
+	for _, index := range []struct {
+		model  interface{}
+		table  string
+		name   string
+		column string
+	}{
+		{&task.Task{}, "tasks", "idx_tasks_name_fulltext", "name"},
+		{&project.Project{}, "projects", "idx_projects_name_fulltext", "name"},
+		{&comment.Comment{}, "comments", "idx_comments_body_fulltext", "body"},
+	} {
+		if DB.Migrator().HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := DB.Exec("CREATE FULLTEXT INDEX " + index.name + " ON " + index.table + " (" + index.column + ")").Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,40 +0,0 @@
-package utils
-
-import (
-	"fmt"
-	"part3/configs"
-	"part3/models/project"
-	"part3/models/task"
-	"part3/models/user"
-
-	"github.com/labstack/gommon/log"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
-)
-
-func InitDB(config *configs.AppConfig) *gorm.DB {
-
-	connectionString := fmt.Sprintf("%v:%v@tcp(%v:%v)/%v?charset=utf8&parseTime=True&loc=Local",
-		config.Database.Username,
-		config.Database.Password,
-		config.Database.Address,
-		config.Database.Port,
-		config.Database.Name,
-	)
-	fmt.Println(connectionString)
-	DB, err := gorm.Open(mysql.Open(connectionString), &gorm.Config{})
-
-	if err != nil {
-		log.Info("error in connect database ", err)
-		panic(err)
-	}
-
-	AutoMigrate(DB)
-	return DB
-}
-
-func AutoMigrate(DB *gorm.DB) {
-	DB.AutoMigrate(&user.User{})
-	DB.AutoMigrate(&task.Task{})
-	DB.AutoMigrate(&project.Project{})
-}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func HashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// IsHashedPassword reports whether the stored value is a bcrypt hash,
+// rows created before hashing was introduced still hold plaintext
+func IsHashedPassword(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+// missingHash stands in for the password of an email nobody registered, it has
+// the cost HashPassword uses
+const missingHash = "$2a$10$9SoYS/U09v7l5EN4mlWfqODbIfkeQxa.q/DeXg9LsYtsML2memyMi"
+
+// CheckMissingPassword takes as long as CheckPassword on a hashed password, so
+// an unknown email cannot be told from a wrong password by the time it takes
+func CheckMissingPassword(password string) {
+	bcrypt.CompareHashAndPassword([]byte(missingHash), []byte(password))
+}
+
+func CheckPassword(stored string, password string) bool {
+	if IsHashedPassword(stored) {
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) == nil
+	}
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+}